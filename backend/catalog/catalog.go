@@ -0,0 +1,23 @@
+// Package catalog holds the bundled species dataset that seeds the species
+// collection, and reads, cleans up and merges species data for imports.
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+//go:embed species.json
+var speciesJSON []byte
+
+// Species returns the bundled species entries
+func Species() ([]types.Species, error) {
+	var species []types.Species
+	if err := json.Unmarshal(speciesJSON, &species); err != nil {
+		return nil, fmt.Errorf("parse bundled species: %w", err)
+	}
+	return species, nil
+}
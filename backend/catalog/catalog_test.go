@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+)
+
+func TestSpecies_BundledDatasetIsConsistent(t *testing.T) {
+	species, err := Species()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(species) == 0 {
+		t.Fatal("bundled dataset is empty")
+	}
+
+	ids := make(map[string]bool, len(species))
+	for _, entry := range species {
+		if entry.ID != util.Slugify(entry.ScientificName) {
+			t.Errorf("%s: ID is not the slug of %q", entry.ID, entry.ScientificName)
+		}
+		if ids[entry.ID] {
+			t.Errorf("%s: duplicate ID", entry.ID)
+		}
+		ids[entry.ID] = true
+
+		langs := map[string]bool{}
+		for _, name := range entry.CommonNames {
+			langs[name.Lang] = true
+		}
+		if !langs["en"] {
+			t.Errorf("%s: no English common name", entry.ID)
+		}
+	}
+}
+
+func TestSpecies_BundledDatasetIsValidAndNormalized(t *testing.T) {
+	species, err := Species()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range species {
+		for _, e := range validation.ValidateSpecies(entry) {
+			t.Errorf("%s: %s: %s", entry.ID, e.Field, e.Message)
+		}
+		if len(Diff(entry, Normalize(entry))) > 0 {
+			t.Errorf("%s: not normalized: %+v", entry.ID, Diff(entry, Normalize(entry)))
+		}
+	}
+}
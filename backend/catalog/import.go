@@ -0,0 +1,350 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+)
+
+// Record is a species entry read from an import file
+type Record struct {
+	// Source locates the record, e.g. "species.csv:12"
+	Source  string
+	Species types.Species
+	// Errors lists the cells that could not be parsed
+	Errors []types.ValidationError
+}
+
+// ReadJSON reads a JSON array of species in the format of the bundled dataset
+func ReadJSON(r io.Reader, name string) ([]Record, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var species []types.Species
+	if err := dec.Decode(&species); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON: %w", name, err)
+	}
+	records := make([]Record, len(species))
+	for i, entry := range species {
+		records[i] = Record{Source: fmt.Sprintf("%s[%d]", name, i), Species: entry}
+	}
+	return records, nil
+}
+
+// csvNamePrefix starts the columns holding common names, one column per
+// language such as "name.de"
+const csvNamePrefix = "name."
+
+// csvListSeparator separates common names and symptoms within a cell
+const csvListSeparator = ";"
+
+var csvColumns = []string{
+	"scientificName",
+	"family",
+	"sunlight",
+	"wateringIntervalDays",
+	"fertilizingIntervalDays",
+	"npkRatio",
+	"mistingIntervalDays",
+	"targetHumidityPct",
+	"toxicityCats",
+	"toxicityDogs",
+	"toxicityHumans",
+	"symptoms",
+	"winterRestPeriod",
+	"winterWaterFactor",
+	"winterMinTempCelsius",
+	"winterFertilize",
+}
+
+func isCSVColumn(name string) bool {
+	return slices.Contains(csvColumns, name) ||
+		(strings.HasPrefix(name, csvNamePrefix) && len(name) > len(csvNamePrefix))
+}
+
+// ReadCSV reads species with one row per species. Common names go in
+// "name.<lang>" columns; several names per language are separated by ";".
+func ReadCSV(r io.Reader, name string) ([]Record, error) {
+	in, err := util.NewCSVReader(r, isCSVColumn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if !in.HasColumn("scientificName") {
+		return nil, fmt.Errorf("%s: CSV file needs a scientificName column", name)
+	}
+	var langs []string
+	for _, column := range in.Columns() {
+		if lang, ok := strings.CutPrefix(column, csvNamePrefix); ok {
+			langs = append(langs, lang)
+		}
+	}
+
+	var records []Record
+	for {
+		row, err := in.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		species := types.Species{
+			ScientificName:          row.Str("scientificName"),
+			Family:                  row.Str("family"),
+			Sunlight:                types.SunlightRequirement(row.Str("sunlight")),
+			WateringIntervalDays:    row.Int("wateringIntervalDays"),
+			FertilizingIntervalDays: row.Int("fertilizingIntervalDays"),
+			NPKRatio:                row.Str("npkRatio"),
+			MistingIntervalDays:     row.Int("mistingIntervalDays"),
+			TargetHumidityPct:       row.Float("targetHumidityPct"),
+			Toxicity: types.Toxicity{
+				Cats:     types.ToxicityLevel(row.Str("toxicityCats")),
+				Dogs:     types.ToxicityLevel(row.Str("toxicityDogs")),
+				Humans:   types.ToxicityLevel(row.Str("toxicityHumans")),
+				Symptoms: row.List("symptoms", csvListSeparator),
+			},
+			Winter: types.SpeciesWinter{
+				RestPeriod:     row.Bool("winterRestPeriod"),
+				WaterFactor:    row.Float("winterWaterFactor"),
+				MinTempCelsius: row.Float("winterMinTempCelsius"),
+				Fertilize:      row.Bool("winterFertilize"),
+			},
+		}
+		for _, lang := range langs {
+			for _, common := range row.List(csvNamePrefix+lang, csvListSeparator) {
+				species.CommonNames = append(species.CommonNames, types.CommonName{Lang: lang, Name: common})
+			}
+		}
+		records = append(records, Record{
+			Source:  name + ":" + strconv.Itoa(row.Line),
+			Species: species,
+			Errors:  row.Errors,
+		})
+	}
+	return records, nil
+}
+
+var sunlightRequirements = []types.SunlightRequirement{
+	types.SunlightFullSun,
+	types.SunlightIndirectSun,
+	types.SunlightPartialShade,
+	types.SunlightPartialToFullShade,
+	types.SunlightFullShade,
+}
+
+// Normalize cleans up the spelling of a species entry and derives its ID
+// from the scientific name. Genus names are capitalized and epithets lower
+// case; quoted cultivar names are kept as they are.
+func Normalize(species types.Species) types.Species {
+	words := strings.Fields(species.ScientificName)
+	for i, word := range words {
+		switch {
+		case i == 0:
+			words[i] = capitalize(word)
+		case strings.IndexAny(word, `'"‘“`) != 0:
+			words[i] = strings.ToLower(word)
+		}
+	}
+	species.ScientificName = strings.Join(words, " ")
+	species.ID = util.Slugify(species.ScientificName)
+	species.Family = capitalize(collapseSpaces(species.Family))
+
+	names := make([]types.CommonName, 0, len(species.CommonNames))
+	for _, common := range species.CommonNames {
+		common.Lang = strings.ToLower(strings.TrimSpace(common.Lang))
+		common.Name = collapseSpaces(common.Name)
+		if common.Name != "" && !hasCommonName(names, common) {
+			names = append(names, common)
+		}
+	}
+	species.CommonNames = names
+
+	for _, sunlight := range sunlightRequirements {
+		if strings.EqualFold(collapseSpaces(string(species.Sunlight)), string(sunlight)) {
+			species.Sunlight = sunlight
+		}
+	}
+	species.NPKRatio = strings.ReplaceAll(species.NPKRatio, " ", "")
+
+	species.Toxicity.Cats = normalizeToxicity(species.Toxicity.Cats)
+	species.Toxicity.Dogs = normalizeToxicity(species.Toxicity.Dogs)
+	species.Toxicity.Humans = normalizeToxicity(species.Toxicity.Humans)
+	var symptoms []string
+	for _, symptom := range species.Toxicity.Symptoms {
+		symptom = strings.ToLower(collapseSpaces(symptom))
+		if symptom != "" && !slices.Contains(symptoms, symptom) {
+			symptoms = append(symptoms, symptom)
+		}
+	}
+	species.Toxicity.Symptoms = symptoms
+	return species
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func capitalize(s string) string {
+	runes := []rune(strings.ToLower(s))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+func normalizeToxicity(level types.ToxicityLevel) types.ToxicityLevel {
+	return types.ToxicityLevel(strings.ToLower(strings.TrimSpace(string(level))))
+}
+
+func hasCommonName(names []types.CommonName, name types.CommonName) bool {
+	for _, existing := range names {
+		if existing.Lang == name.Lang && strings.EqualFold(existing.Name, name.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merged is a species entry merged from all records with its scientific name
+type Merged struct {
+	Species types.Species
+	Sources []string
+	// Conflicts lists the fields the records disagreed on
+	Conflicts []string
+}
+
+// Merge normalizes the records and merges those with the same scientific
+// name, in the order each name first appears. Later records override the
+// values of earlier ones, but empty values never override. Common names and
+// symptoms are combined, and flags are set if any record sets them.
+func Merge(records []Record) []Merged {
+	var merged []Merged
+	index := make(map[string]int)
+	for _, record := range records {
+		species := Normalize(record.Species)
+		i, ok := index[species.ID]
+		if !ok {
+			index[species.ID] = len(merged)
+			merged = append(merged, Merged{Species: species, Sources: []string{record.Source}})
+			continue
+		}
+		m := &merged[i]
+		m.Sources = append(m.Sources, record.Source)
+		m.Conflicts = mergeSpecies(&m.Species, species, m.Conflicts)
+	}
+	return merged
+}
+
+func mergeSpecies(dst *types.Species, src types.Species, conflicts []string) []string {
+	mergeValue(&dst.Family, src.Family, "family", &conflicts)
+	mergeValue(&dst.Sunlight, src.Sunlight, "sunlight", &conflicts)
+	mergeValue(&dst.WateringIntervalDays, src.WateringIntervalDays, "wateringIntervalDays", &conflicts)
+	mergeValue(&dst.FertilizingIntervalDays, src.FertilizingIntervalDays, "fertilizingIntervalDays", &conflicts)
+	mergeValue(&dst.NPKRatio, src.NPKRatio, "npkRatio", &conflicts)
+	mergeValue(&dst.MistingIntervalDays, src.MistingIntervalDays, "mistingIntervalDays", &conflicts)
+	mergeValue(&dst.TargetHumidityPct, src.TargetHumidityPct, "targetHumidityPct", &conflicts)
+	mergeValue(&dst.Toxicity.Cats, src.Toxicity.Cats, "toxicity.cats", &conflicts)
+	mergeValue(&dst.Toxicity.Dogs, src.Toxicity.Dogs, "toxicity.dogs", &conflicts)
+	mergeValue(&dst.Toxicity.Humans, src.Toxicity.Humans, "toxicity.humans", &conflicts)
+	mergeValue(&dst.Winter.WaterFactor, src.Winter.WaterFactor, "winter.waterFactor", &conflicts)
+	mergeValue(&dst.Winter.MinTempCelsius, src.Winter.MinTempCelsius, "winter.minTempCelsius", &conflicts)
+	dst.Winter.RestPeriod = dst.Winter.RestPeriod || src.Winter.RestPeriod
+	dst.Winter.Fertilize = dst.Winter.Fertilize || src.Winter.Fertilize
+
+	for _, common := range src.CommonNames {
+		if !hasCommonName(dst.CommonNames, common) {
+			dst.CommonNames = append(dst.CommonNames, common)
+		}
+	}
+	for _, symptom := range src.Toxicity.Symptoms {
+		if !slices.Contains(dst.Toxicity.Symptoms, symptom) {
+			dst.Toxicity.Symptoms = append(dst.Toxicity.Symptoms, symptom)
+		}
+	}
+	return conflicts
+}
+
+func mergeValue[T comparable](dst *T, src T, field string, conflicts *[]string) {
+	var zero T
+	if src == zero || src == *dst {
+		return
+	}
+	if *dst != zero && !slices.Contains(*conflicts, field) {
+		*conflicts = append(*conflicts, field)
+	}
+	*dst = src
+}
+
+// Change is a field that differs between the stored and the imported entry
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff lists the fields that differ between two entries of the same species
+func Diff(old, new types.Species) []Change {
+	oldFields := fields(old)
+	newFields := fields(new)
+
+	names := make([]string, 0, len(newFields))
+	for name := range newFields {
+		names = append(names, name)
+	}
+	for name := range oldFields {
+		if _, ok := newFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		if oldFields[name] != newFields[name] {
+			changes = append(changes, Change{Field: name, Old: oldFields[name], New: newFields[name]})
+		}
+	}
+	return changes
+}
+
+// fields flattens an entry into printable values by field name
+func fields(species types.Species) map[string]string {
+	values := map[string]string{
+		"scientificName":          species.ScientificName,
+		"family":                  species.Family,
+		"sunlight":                string(species.Sunlight),
+		"wateringIntervalDays":    strconv.Itoa(species.WateringIntervalDays),
+		"fertilizingIntervalDays": strconv.Itoa(species.FertilizingIntervalDays),
+		"npkRatio":                species.NPKRatio,
+		"mistingIntervalDays":     strconv.Itoa(species.MistingIntervalDays),
+		"targetHumidityPct":       formatFloat(species.TargetHumidityPct),
+		"toxicity.cats":           string(species.Toxicity.Cats),
+		"toxicity.dogs":           string(species.Toxicity.Dogs),
+		"toxicity.humans":         string(species.Toxicity.Humans),
+		"toxicity.symptoms":       strings.Join(species.Toxicity.Symptoms, csvListSeparator),
+		"winter.restPeriod":       strconv.FormatBool(species.Winter.RestPeriod),
+		"winter.waterFactor":      formatFloat(species.Winter.WaterFactor),
+		"winter.minTempCelsius":   formatFloat(species.Winter.MinTempCelsius),
+		"winter.fertilize":        strconv.FormatBool(species.Winter.Fertilize),
+	}
+	for _, common := range species.CommonNames {
+		field := "commonNames." + common.Lang
+		if values[field] != "" {
+			values[field] += csvListSeparator
+		}
+		values[field] += common.Name
+	}
+	return values
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
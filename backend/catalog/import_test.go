@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestReadCSV_ParsesNamesPerLanguage(t *testing.T) {
+	csv := "scientificName,name.en,name.de,wateringIntervalDays,toxicityCats,symptoms\n" +
+		"monstera  DELICIOSA,Swiss cheese plant;Monstera,Fensterblatt,7,Moderate,Drooling; vomiting\n" +
+		"Aloe vera,Aloe,,weekly,mild,\n"
+	records, err := ReadCSV(strings.NewReader(csv), "species.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records", len(records))
+	}
+	if records[1].Source != "species.csv:3" || len(records[1].Errors) != 1 {
+		t.Errorf("record 2: source %q errors %+v", records[1].Source, records[1].Errors)
+	}
+
+	monstera := Normalize(records[0].Species)
+	if monstera.ID != "monstera-deliciosa" || monstera.ScientificName != "Monstera deliciosa" {
+		t.Errorf("name: got %q %q", monstera.ID, monstera.ScientificName)
+	}
+	want := []types.CommonName{
+		{Lang: "de", Name: "Fensterblatt"},
+		{Lang: "en", Name: "Swiss cheese plant"},
+		{Lang: "en", Name: "Monstera"},
+	}
+	if !slices.Equal(monstera.CommonNames, want) {
+		t.Errorf("common names: got %+v", monstera.CommonNames)
+	}
+	if monstera.Toxicity.Cats != types.ToxicityModerate ||
+		!slices.Equal(monstera.Toxicity.Symptoms, []string{"drooling", "vomiting"}) {
+		t.Errorf("toxicity: got %+v", monstera.Toxicity)
+	}
+
+	if _, err := ReadCSV(strings.NewReader("scientificName,colour\n"), "bad.csv"); err == nil {
+		t.Error("unknown column was accepted")
+	}
+}
+
+func TestMerge_CombinesRecordsByScientificName(t *testing.T) {
+	records := []Record{
+		{Source: "a.json[0]", Species: types.Species{
+			ScientificName:       "Ficus elastica",
+			CommonNames:          []types.CommonName{{Lang: "en", Name: "Rubber plant"}},
+			WateringIntervalDays: 7,
+			Sunlight:             "indirect sun",
+		}},
+		{Source: "b.csv:2", Species: types.Species{
+			ScientificName:       "FICUS Elastica",
+			CommonNames:          []types.CommonName{{Lang: "en", Name: "rubber plant"}, {Lang: "de", Name: "Gummibaum"}},
+			WateringIntervalDays: 10,
+			NPKRatio:             "3 - 1 - 2",
+			Winter:               types.SpeciesWinter{RestPeriod: true},
+		}},
+		{Source: "b.csv:3", Species: types.Species{ScientificName: "Hedera helix"}},
+	}
+
+	merged := Merge(records)
+	if len(merged) != 2 || merged[0].Species.ID != "ficus-elastica" || merged[1].Species.ID != "hedera-helix" {
+		t.Fatalf("merged: got %+v", merged)
+	}
+	ficus := merged[0]
+	if !slices.Equal(ficus.Sources, []string{"a.json[0]", "b.csv:2"}) ||
+		!slices.Equal(ficus.Conflicts, []string{"wateringIntervalDays"}) {
+		t.Errorf("sources %v conflicts %v", ficus.Sources, ficus.Conflicts)
+	}
+	s := ficus.Species
+	if s.WateringIntervalDays != 10 || s.NPKRatio != "3-1-2" || s.Sunlight != types.SunlightIndirectSun ||
+		!s.Winter.RestPeriod || len(s.CommonNames) != 2 {
+		t.Errorf("merged species: got %+v", s)
+	}
+}
+
+func TestDiff_ListsChangedFields(t *testing.T) {
+	old := types.Species{
+		ScientificName:       "Hedera helix",
+		CommonNames:          []types.CommonName{{Lang: "en", Name: "English ivy"}},
+		WateringIntervalDays: 7,
+	}
+	updated := old
+	updated.WateringIntervalDays = 5
+	updated.CommonNames = []types.CommonName{{Lang: "en", Name: "English ivy"}, {Lang: "fr", Name: "Lierre"}}
+
+	changes := Diff(old, updated)
+	want := []Change{
+		{Field: "commonNames.fr", Old: "", New: "Lierre"},
+		{Field: "wateringIntervalDays", Old: "7", New: "5"},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("diff: got %+v", changes)
+	}
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Errorf("diff of equal entries: got %+v", changes)
+	}
+}
@@ -2,21 +2,38 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/qreepex/water-me-app/backend/logging"
 	"github.com/qreepex/water-me-app/backend/middlewares"
 	"github.com/qreepex/water-me-app/backend/routes"
 	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 
 	_ "github.com/joho/godotenv/autoload"
 )
 
 func main() {
-	ctx := context.Background()
+	logging.Setup("api")
+
+	// ctx is cancelled on SIGTERM/SIGINT (e.g. during a Kubernetes rollout)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, "water-me-api")
+	if err != nil {
+		fatal("failed to initialize tracing", err)
+	}
+
 	connString := getenv("DATABASE_URL", "mongodb://localhost:27017/plants")
 	mongoUser := getenv("MONGODB_USERNAME", "test2")
 	mongoPassword := getenv("MONGODB_PASSWORD", "test")
@@ -24,23 +41,35 @@ func main() {
 
 	db, err := services.Connect(connString, mongoDatabase, mongoUser, mongoPassword)
 	if err != nil {
-		log.Fatalf("failed to initialize database: %v", err)
+		fatal("failed to initialize database", err)
 	}
 	defer db.Close()
 
-	firebase, err := services.NewFirebaseService()
-	if err != nil {
-		log.Fatalf("failed to initialize firebase: %v", err)
-	}
-
-	// Protected S3 & plant routes
-	s3svc, err := services.NewS3Service(ctx)
-	if err != nil {
-		log.Fatalf("failed to init s3: %v", err)
+	// Firebase (default) or self-hosted email/password accounts, selected by AUTH_MODE
+	authMode := getenv("AUTH_MODE", services.AuthModeFirebase)
+	var (
+		authenticator services.Authenticator
+		firebase      *services.FirebaseService
+		localAuth     *services.LocalAuth
+	)
+	switch authMode {
+	case services.AuthModeFirebase:
+		firebase, err = services.NewFirebaseService()
+		if err != nil {
+			fatal("failed to initialize firebase", err)
+		}
+		authenticator = firebase
+	case services.AuthModeLocal:
+		localAuth, err = services.NewLocalAuthFromEnv(db)
+		if err != nil {
+			fatal("failed to initialize local auth", err)
+		}
+		authenticator = localAuth
+	default:
+		fatal("invalid AUTH_MODE", errors.New(authMode))
 	}
+	slog.Info("auth mode", "mode", authMode)
 
-	// Configure S3 bucket CORS for browser uploads
-	// This allows the frontend to make direct PUT requests to S3
 	allowedOrigins := []string{
 		"https://localhost",
 		"http://localhost",
@@ -48,16 +77,30 @@ func main() {
 		"https://water-me.app",
 		"https://my.water-me.app",
 	}
-	if err := s3svc.SetupCORS(ctx, allowedOrigins); err != nil {
-		log.Printf("warning: failed to setup S3 CORS: %v", err)
-	} else {
-		log.Println("S3 bucket CORS configured successfully")
+
+	// Object storage for photos: S3 or local disk, selected by STORAGE_BACKEND
+	objects, err := services.NewObjectStoreFromEnv(ctx)
+	if err != nil {
+		fatal("failed to init object storage", err)
+	}
+	if s3svc, ok := objects.(*services.S3Service); ok {
+		defer s3svc.Close()
+
+		// Configure S3 bucket CORS for browser uploads
+		// This allows the frontend to make direct PUT requests to S3
+		if err := s3svc.SetupCORS(ctx, allowedOrigins); err != nil {
+			slog.Warn("failed to setup S3 CORS", "error", err)
+		} else {
+			slog.Info("S3 bucket CORS configured successfully")
+		}
 	}
 
 	cors := handlers.CORS(
 		handlers.AllowedOrigins(allowedOrigins),
 		handlers.AllowedHeaders([]string{"Authorization", "Content-Type", "*"}),
-		handlers.ExposedHeaders([]string{"Authorization", "Content-Type", "ETag"}),
+		handlers.ExposedHeaders(
+			[]string{"Authorization", "Content-Type", "ETag", middlewares.RequestIDHeader},
+		),
 		handlers.AllowedMethods(
 			[]string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		),
@@ -65,16 +108,74 @@ func main() {
 	)
 
 	r := mux.NewRouter()
+	// Tracing runs first so every later middleware and log line sees the span
+	r.Use(otelmux.Middleware("water-me-api"))
+	r.Use(middlewares.RequestIDMiddleware)
+	r.Use(middlewares.MetricsMiddleware)
 	r.Use(cors)
 
-	routes.RegisterRoutes(r, db, s3svc)
+	routes.RegisterRoutes(r, db, objects)
+	if local, ok := objects.(*services.LocalStorage); ok {
+		// Signed upload and download URLs of the local backend point at the API
+		routes.StorageHandler(r, local)
+	}
+	if localAuth != nil {
+		routes.AuthHandler(r, localAuth)
+	}
+	checks := services.DependencyChecks(db, objects, firebase)
+	routes.HealthHandler(r, checks, nil)
+
+	r.Use(middlewares.AuthMiddleware(authenticator, services.NewAPITokens(db)))
 
-	r.Use(middlewares.AuthMiddleware(firebase))
+	// /metrics is only served on the internal probe port, never on the
+	// public one
+	probeServer := routes.NewProbeServer(":"+getenv("PROBE_PORT", "8081"), checks, nil)
+	go func() {
+		if err := probeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("probe server error", "error", err)
+		}
+	}()
+	defer probeServer.Shutdown(context.Background())
 
 	port := getenv("PORT", "8080")
-	log.Printf("Starting API server on :%s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           r,
+		ReadHeaderTimeout: getenvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       getenvDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:      getenvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       getenvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("starting API server", "port", port)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal("failed to start server", err)
+		}
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining in-flight requests")
+	}
+
+	// Stop accepting new connections and wait for in-flight requests to finish
+	shutdownTimeout := getenvDuration("SHUTDOWN_TIMEOUT", 25*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("graceful shutdown did not complete", "error", err)
+	} else {
+		slog.Info("API server stopped")
+	}
+
+	// Flush spans of the drained requests
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
 	}
 }
 
@@ -84,3 +185,23 @@ func getenv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getenvDuration parses a duration such as "15s" from the environment
+func getenvDuration(key string, fallback time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		slog.Warn("invalid duration, using fallback", "key", key, "value", val, "fallback", fallback)
+		return fallback
+	}
+	return d
+}
+
+// fatal logs a startup error and exits; deferred cleanups are skipped
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
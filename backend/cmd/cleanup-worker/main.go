@@ -2,17 +2,38 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/metrics"
+	"github.com/qreepex/water-me-app/backend/routes"
 	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/tracing"
 
+	"github.com/google/uuid"
 	_ "github.com/joho/godotenv/autoload"
 )
 
+const cleanupInterval = 30 * time.Minute
+
 func main() {
-	ctx := context.Background()
+	logging.Setup("cleanup-worker")
+
+	// ctx is cancelled on SIGTERM/SIGINT (e.g. during a Kubernetes rollout)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, "water-me-cleanup-worker")
+	if err != nil {
+		fatal("failed to initialize tracing", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	connString := getenv("DATABASE_URL", "mongodb://localhost:27017/plants")
 	mongoUser := getenv("MONGODB_USERNAME", "test2")
 	mongoPassword := getenv("MONGODB_PASSWORD", "test")
@@ -20,36 +41,96 @@ func main() {
 
 	db, err := services.Connect(connString, mongoDatabase, mongoUser, mongoPassword)
 	if err != nil {
-		log.Fatalf("failed to initialize database: %v", err)
+		fatal("failed to initialize database", err)
 	}
 	defer db.Close()
 
-	s3svc, err := services.NewS3Service(ctx)
+	objects, err := services.NewObjectStoreFromEnv(ctx)
 	if err != nil {
-		log.Fatalf("failed to init s3: %v", err)
+		fatal("failed to init object storage", err)
 	}
+	if s3svc, ok := objects.(*services.S3Service); ok {
+		defer s3svc.Close()
+	}
+
+	// Expose /livez and /readyz so Kubernetes can restart a stuck worker
+	heartbeat := services.NewHeartbeat(3 * cleanupInterval)
+	probeServer := routes.NewProbeServer(
+		":"+getenv("PROBE_PORT", "8081"),
+		services.DependencyChecks(db, objects, nil),
+		heartbeat,
+	)
+	go func() {
+		if err := probeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("probe server error", "error", err)
+		}
+	}()
+	defer probeServer.Shutdown(context.Background())
 
-	runCleanupCheck(db, s3svc)
+	runCleanupCheck(ctx, db, objects, heartbeat)
 }
 
-// runCleanupCheck runs a background job to clean up orphaned uploads every 30 minutes
-func runCleanupCheck(db *services.MongoDB, s3 *services.S3Service) {
-	ticker := time.NewTicker(30 * time.Minute)
+// runCleanupCheck runs a background job to clean up orphaned uploads and
+// expired data exports every 30 minutes.
+// It returns once ctx is cancelled; a cleanup pass that is already running is finished first.
+func runCleanupCheck(
+	ctx context.Context,
+	db *services.MongoDB,
+	objects services.ObjectStore,
+	heartbeat *services.Heartbeat,
+) {
+	ticker := time.NewTicker(cleanupInterval)
 	defer ticker.Stop()
 
-	log.Println("Orphaned upload cleanup worker started (runs every 30 minutes)")
+	slog.Info("orphaned upload cleanup worker started", "interval", cleanupInterval.String())
 
-	for range ticker.C {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		uploadSvc := services.NewUploadService(db, s3)
-		count, err := uploadSvc.CleanupOrphanedUploads(ctx, 1*time.Hour)
-		cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutdown signal received, cleanup worker stopped")
+			return
+		case <-ticker.C:
+		}
 
+		// Detached from the shutdown signal so S3 and Mongo deletes stay in sync
+		workCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Minute)
+		workCtx = logging.WithAttrs(workCtx, "runId", uuid.NewString())
+		workCtx, span := tracing.Tracer.Start(workCtx, "cleanup.run")
+		uploadSvc := services.NewUploadService(db, objects)
+		count, err := uploadSvc.CleanupOrphanedUploads(workCtx, 1*time.Hour)
 		if err != nil {
-			log.Printf("Cleanup worker error: %v", err)
-		} else if count > 0 {
-			log.Printf("Cleaned up %d orphaned uploads", count)
+			tracing.RecordError(span, err)
+			slog.ErrorContext(workCtx, "cleanup pass failed", "error", err)
+			span.End()
+			cancel()
+			continue
 		}
+
+		metrics.OrphanUploadsDeleted.Add(float64(count))
+		if count > 0 {
+			slog.InfoContext(workCtx, "cleaned up orphaned uploads", "count", count)
+		}
+
+		expired, err := services.NewExporter(db, objects).DeleteExpired(workCtx, time.Now())
+		if err != nil {
+			tracing.RecordError(span, err)
+			slog.ErrorContext(workCtx, "failed to delete expired exports", "error", err)
+		} else if expired > 0 {
+			slog.InfoContext(workCtx, "deleted expired exports", "count", expired)
+		}
+
+		// Refresh the storage usage snapshot after the orphans are gone
+		usage, err := db.GetStorageUsageByUser(workCtx)
+		if err != nil {
+			tracing.RecordError(span, err)
+			slog.ErrorContext(workCtx, "failed to compute storage usage", "error", err)
+		} else {
+			metrics.SetS3UserBytes(usage)
+		}
+		span.End()
+		cancel()
+
+		heartbeat.Beat()
 	}
 }
 
@@ -59,3 +140,9 @@ func getenv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// fatal logs a startup error and exits; deferred cleanups are skipped
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/migrations"
+	"github.com/qreepex/water-me-app/backend/services"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+const usage = `Usage: migrate [command]
+
+Commands:
+  up      Apply all pending migrations (default)
+  status  List migrations and whether they have been applied
+`
+
+func main() {
+	logging.Setup("migrate")
+
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	command := "up"
+	if flag.NArg() > 0 {
+		command = flag.Arg(0)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	connString := getenv("DATABASE_URL", "mongodb://localhost:27017/plants")
+	mongoUser := getenv("MONGODB_USERNAME", "test2")
+	mongoPassword := getenv("MONGODB_PASSWORD", "test")
+	mongoDatabase := getenv("MONGODB_DATABASE", "plants")
+
+	db, err := services.Connect(connString, mongoDatabase, mongoUser, mongoPassword)
+	if err != nil {
+		fatal("failed to initialize database", err)
+	}
+	defer db.Close()
+
+	switch command {
+	case "up":
+		done, err := migrations.Run(ctx, db.Database())
+		if err != nil {
+			db.Close()
+			fatal("migration failed", err)
+		}
+		slog.Info("migrations complete", "applied", len(done))
+	case "status":
+		if err := printStatus(ctx, db); err != nil {
+			db.Close()
+			fatal("failed to read migration status", err)
+		}
+	default:
+		flag.Usage()
+		db.Close()
+		os.Exit(2)
+	}
+}
+
+func printStatus(ctx context.Context, db *services.MongoDB) error {
+	applied, err := migrations.Applied(ctx, db.Database())
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations.All() {
+		status := "pending"
+		if record, ok := applied[migration.Version]; ok {
+			status = "applied " + record.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%4d  %-28s  %s\n", migration.Version, status, migration.Description)
+	}
+	return nil
+}
+
+func getenv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// fatal logs an error and exits; deferred cleanups are skipped
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
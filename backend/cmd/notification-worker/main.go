@@ -2,14 +2,22 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/routes"
 	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/tracing"
 
+	"github.com/google/uuid"
 	_ "github.com/joho/godotenv/autoload"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -18,7 +26,17 @@ const (
 )
 
 func main() {
-	ctx := context.Background()
+	logging.Setup("notification-worker")
+
+	// ctx is cancelled on SIGTERM/SIGINT (e.g. during a Kubernetes rollout)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, "water-me-notification-worker")
+	if err != nil {
+		fatal("failed to initialize tracing", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Load configuration from environment
 	config := loadConfig()
@@ -31,78 +49,120 @@ func main() {
 		config.DatabasePassword,
 	)
 	if err != nil {
-		log.Fatalf("failed to initialize database: %v", err)
+		fatal("failed to initialize database", err)
 	}
 	defer db.Close()
 
 	// Initialize Firebase service
 	firebase, err := services.NewFirebaseService()
 	if err != nil {
-		log.Fatalf("failed to initialize firebase: %v", err)
+		fatal("failed to initialize firebase", err)
 	}
 
 	// Load notification messages from JSON files
 	messageDir := filepath.Join(".", "messages")
 	if err := services.LoadNotificationMessages(messageDir); err != nil {
-		log.Fatalf("failed to load notification messages: %v", err)
+		fatal("failed to load notification messages", err)
 	}
 
-	log.Println("========================================")
-	log.Println("Notification Worker Started")
-	log.Println("========================================")
-	log.Printf("Scalable for 50k+ users with 100+ plants each")
-	log.Printf("Configuration:")
-	log.Printf("  - Plants batch size: %d", plantsBatchSize)
-	log.Printf("  - FCM batch size: %d", services.FCMBatchSize)
-	log.Printf("  - Check interval: %v", workerInterval)
-	log.Printf("  - Cooldown period: %v", services.NotificationCooldown)
-	log.Println("========================================")
+	slog.Info("notification worker started",
+		"plantsBatchSize", plantsBatchSize,
+		"fcmBatchSize", services.FCMBatchSize,
+		"interval", workerInterval.String(),
+		"cooldown", services.NotificationCooldown.String(),
+	)
+
+	// Expose /livez and /readyz so Kubernetes can restart a stuck worker
+	heartbeat := services.NewHeartbeat(3 * workerInterval)
+	probeServer := routes.NewProbeServer(
+		":"+config.ProbePort,
+		services.DependencyChecks(db, nil, firebase),
+		heartbeat,
+	)
+	go func() {
+		if err := probeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("probe server error", "error", err)
+		}
+	}()
+	defer probeServer.Shutdown(context.Background())
 
 	// Run notification check loop
 	ticker := time.NewTicker(workerInterval)
 	defer ticker.Stop()
 
 	// Run immediately on startup
-	runNotificationCheck(ctx, db, firebase)
-
-	// Then run every interval
-	for range ticker.C {
-		runNotificationCheck(ctx, db, firebase)
+	runNotificationCheck(ctx, db, firebase, heartbeat)
+
+	// Then run every interval until a shutdown signal arrives
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutdown signal received, notification worker stopped")
+			return
+		case <-ticker.C:
+			runNotificationCheck(ctx, db, firebase, heartbeat)
+		}
 	}
 }
 
-// runNotificationCheck orchestrates the entire notification check process
+// runNotificationCheck orchestrates the entire notification check process.
+// Cancelling ctx stops the check after the user currently being notified.
 func runNotificationCheck(
 	ctx context.Context,
 	db *services.MongoDB,
 	firebase *services.FirebaseService,
+	heartbeat *services.Heartbeat,
 ) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute) // Long timeout for large batches
+	// The work context is detached from the shutdown signal so a multicast is
+	// never aborted half-way; ProcessNotifications stops between users instead.
+	workCtx, cancel := context.WithTimeout(
+		context.WithoutCancel(ctx),
+		10*time.Minute, // Long timeout for large batches
+	)
 	defer cancel()
 
+	// Every log line of this run carries the same run ID
+	workCtx = logging.WithAttrs(workCtx, "runId", uuid.NewString())
+	workCtx, span := tracing.Tracer.Start(workCtx, "notifications.run")
+	defer span.End()
+
 	startTime := time.Now()
-	log.Println("========== Starting notification check ==========")
+	slog.InfoContext(workCtx, "starting notification check")
 
 	// Process all notification types and get statistics
-	stats := services.ProcessNotifications(ctx, db, firebase, plantsBatchSize)
+	stats := services.ProcessNotifications(workCtx, ctx.Done(), db, firebase, plantsBatchSize)
 
 	// Log results
-	duration := time.Since(startTime)
-	log.Println("========== Notification check complete ==========")
-	log.Printf("Duration: %v", duration)
-	log.Printf("Plants checked: %d", stats.PlantsChecked)
-	log.Printf("Notifications sent: %d", stats.NotificationsSent)
-	log.Printf("Notifications failed: %d", stats.NotificationsFailed)
-	log.Printf("Users notified: %d", stats.UsersNotified)
+	successRate := 0.0
 	if stats.NotificationsSent > 0 {
-		successRate := float64(
+		successRate = float64(
 			stats.NotificationsSent,
 		) / float64(
 			stats.NotificationsSent+stats.NotificationsFailed,
 		) * 100
-		log.Printf("Success rate: %.2f%%", successRate)
 	}
-	log.Println("================================================")
+	slog.InfoContext(workCtx, "notification check finished",
+		"duration", time.Since(startTime).String(),
+		"interrupted", stats.Interrupted,
+		"plantsChecked", stats.PlantsChecked,
+		"notificationsSent", stats.NotificationsSent,
+		"notificationsFailed", stats.NotificationsFailed,
+		"usersNotified", stats.UsersNotified,
+		"errors", stats.Errors,
+		"successRatePct", successRate,
+	)
+
+	span.SetAttributes(
+		attribute.Int("plants.checked", stats.PlantsChecked),
+		attribute.Int("notifications.sent", stats.NotificationsSent),
+		attribute.Int("notifications.failed", stats.NotificationsFailed),
+	)
+
+	// Failures of single plants, users or plans are logged and counted above
+	// and retried on the next run; they don't make the worker unhealthy
+	if !stats.Interrupted {
+		heartbeat.Beat()
+	}
 }
 
 type Config struct {
@@ -110,6 +170,7 @@ type Config struct {
 	DatabaseUser     string
 	DatabasePassword string
 	DatabaseName     string
+	ProbePort        string
 }
 
 func loadConfig() Config {
@@ -118,6 +179,7 @@ func loadConfig() Config {
 		DatabaseUser:     getenv("MONGODB_USERNAME", "test2"),
 		DatabasePassword: getenv("MONGODB_PASSWORD", "test"),
 		DatabaseName:     getenv("MONGODB_DATABASE", "plants"),
+		ProbePort:        getenv("PROBE_PORT", "8081"),
 	}
 }
 
@@ -127,3 +189,9 @@ func getenv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// fatal logs a startup error and exits; deferred cleanups are skipped
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
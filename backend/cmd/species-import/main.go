@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/qreepex/water-me-app/backend/catalog"
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+const usage = `Usage: species-import [-dry-run] FILE...
+
+Imports species into the catalog from .csv and .json files. Records with the
+same scientific name are merged, later files taking precedence. Nothing is
+written if any record is invalid.
+
+Flags:
+`
+
+func main() {
+	logging.Setup("species-import")
+
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	records, err := readRecords(flag.Args())
+	if err != nil {
+		fatal("failed to read species files", err)
+	}
+
+	connString := getenv("DATABASE_URL", "mongodb://localhost:27017/plants")
+	mongoUser := getenv("MONGODB_USERNAME", "test2")
+	mongoPassword := getenv("MONGODB_PASSWORD", "test")
+	mongoDatabase := getenv("MONGODB_DATABASE", "plants")
+
+	db, err := services.Connect(connString, mongoDatabase, mongoUser, mongoPassword)
+	if err != nil {
+		fatal("failed to initialize database", err)
+	}
+	defer db.Close()
+
+	if err := importSpecies(ctx, db, records, *dryRun, os.Stdout); err != nil {
+		db.Close()
+		fatal("species import failed", err)
+	}
+}
+
+// readRecords reads all files, choosing the format by file extension
+func readRecords(paths []string) ([]catalog.Record, error) {
+	var records []catalog.Record
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		var read []catalog.Record
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			read, err = catalog.ReadCSV(file, path)
+		case ".json":
+			read, err = catalog.ReadJSON(file, path)
+		default:
+			err = fmt.Errorf("%s: unsupported file type, use .csv or .json", path)
+		}
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, read...)
+	}
+	return records, nil
+}
+
+// importSpecies merges and validates the records, reports how the catalog
+// would change and applies the changes unless dryRun is set
+func importSpecies(
+	ctx context.Context,
+	store services.SpeciesStore,
+	records []catalog.Record,
+	dryRun bool,
+	out io.Writer,
+) error {
+	invalid := 0
+	for _, record := range records {
+		for _, e := range record.Errors {
+			fmt.Fprintf(out, "! %s: %s\n", record.Source, e.Message)
+			invalid++
+		}
+	}
+
+	merged := catalog.Merge(records)
+	for _, m := range merged {
+		for _, e := range validation.ValidateSpecies(m.Species) {
+			fmt.Fprintf(out, "! %s (%s): %s: %s\n",
+				m.Species.ScientificName, strings.Join(m.Sources, ", "), e.Field, e.Message)
+			invalid++
+		}
+		if len(m.Sources) > 1 {
+			fmt.Fprintf(out, "  merged %d records for %s", len(m.Sources), m.Species.ScientificName)
+			if len(m.Conflicts) > 0 {
+				fmt.Fprintf(out, ", later values won for %s", strings.Join(m.Conflicts, ", "))
+			}
+			fmt.Fprintln(out)
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("found %d problems, nothing was written", invalid)
+	}
+
+	var created, changed, unchanged int
+	for _, m := range merged {
+		existing, err := store.GetSpecies(ctx, m.Species.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			fmt.Fprintf(out, "+ %s\n", m.Species.ID)
+			created++
+		} else {
+			changes := catalog.Diff(*existing, m.Species)
+			if len(changes) == 0 {
+				unchanged++
+				continue
+			}
+			fmt.Fprintf(out, "~ %s\n", m.Species.ID)
+			for _, change := range changes {
+				fmt.Fprintf(out, "    %s: %q -> %q\n", change.Field, change.Old, change.New)
+			}
+			changed++
+		}
+		if !dryRun {
+			if err := store.UpsertSpecies(ctx, m.Species); err != nil {
+				return fmt.Errorf("upsert %s: %w", m.Species.ID, err)
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "species import complete",
+		"records", len(records),
+		"created", created,
+		"changed", changed,
+		"unchanged", unchanged,
+		"dryRun", dryRun,
+	)
+	return nil
+}
+
+func getenv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+// fatal logs an error and exits; deferred cleanups are skipped
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
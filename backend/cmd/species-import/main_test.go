@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/catalog"
+	"github.com/qreepex/water-me-app/backend/services/memstore"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const ivyCSV = "scientificName,family,name.en,sunlight,wateringIntervalDays,fertilizingIntervalDays," +
+	"npkRatio,targetHumidityPct,toxicityCats,toxicityDogs,toxicityHumans,winterWaterFactor\n" +
+	"Hedera helix,Araliaceae,English ivy,Partial Shade,5,14,10-10-10,50,moderate,moderate,moderate,0.6\n" +
+	"Pilea peperomioides,Urticaceae,Chinese money plant,Indirect Sun,7,30,20-20-20,50,none,none,none,0.6\n"
+
+func TestImportSpecies_ReportsAndUpsertsChanges(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	if err := store.UpsertSpecies(ctx, types.Species{
+		ID:                   "hedera-helix",
+		ScientificName:       "Hedera helix",
+		Family:               "Araliaceae",
+		CommonNames:          []types.CommonName{{Lang: "en", Name: "English ivy"}},
+		Sunlight:             types.SunlightPartialShade,
+		WateringIntervalDays: 7,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := catalog.ReadCSV(strings.NewReader(ivyCSV), "ivy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := importSpecies(ctx, store, records, true, &out); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	report := out.String()
+	if !strings.Contains(report, "+ pilea-peperomioides") ||
+		!strings.Contains(report, `wateringIntervalDays: "7" -> "5"`) {
+		t.Errorf("report:\n%s", report)
+	}
+	if pilea, _ := store.GetSpecies(ctx, "pilea-peperomioides"); pilea != nil {
+		t.Error("dry run wrote to the store")
+	}
+
+	if err := importSpecies(ctx, store, records, false, &out); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	ivy, _ := store.GetSpecies(ctx, "hedera-helix")
+	if ivy == nil || ivy.WateringIntervalDays != 5 || ivy.Toxicity.Humans != types.ToxicityModerate {
+		t.Errorf("hedera-helix: got %+v", ivy)
+	}
+}
+
+func TestImportSpecies_WritesNothingIfAnyRecordIsInvalid(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	invalid := strings.Replace(ivyCSV, "Indirect Sun,7,", "Indirect Sun,400,", 1)
+	records, err := catalog.ReadCSV(strings.NewReader(invalid), "ivy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := importSpecies(ctx, store, records, false, &out); err == nil {
+		t.Fatal("invalid import succeeded")
+	}
+	if !strings.Contains(out.String(), "watering.intervalDays") {
+		t.Errorf("report:\n%s", out.String())
+	}
+	if ivy, _ := store.GetSpecies(ctx, "hedera-helix"); ivy != nil {
+		t.Error("valid record was written despite an invalid one")
+	}
+}
@@ -1,13 +1,43 @@
 package constants
 
 var MongoDBCollections = struct {
-	Plants        string
-	Notifications string
-	Uploads       string
+	Plants           string
+	Notifications    string
+	Uploads          string
+	SchemaMigrations string
+	Users            string
+	APITokens        string
+	Households       string
+	HouseholdInvites string
+	CareEvents       string
+	Vacations        string
+	PlantShares      string
+	AccountErasures  string
+	Exports          string
+	Species          string
+	SafetyProfiles   string
+	Rooms            string
+	PestOutbreaks    string
+	TreatmentPlans   string
 }{
-	Plants:        "plants",
-	Notifications: "notifications",
-	Uploads:       "uploads",
+	Plants:           "plants",
+	Notifications:    "notifications",
+	Uploads:          "uploads",
+	SchemaMigrations: "schema_migrations",
+	Users:            "users",
+	APITokens:        "api_tokens",
+	Households:       "households",
+	HouseholdInvites: "household_invites",
+	CareEvents:       "care_events",
+	Vacations:        "vacations",
+	PlantShares:      "plant_shares",
+	AccountErasures:  "account_erasures",
+	Exports:          "exports",
+	Species:          "species",
+	SafetyProfiles:   "safety_profiles",
+	Rooms:            "rooms",
+	PestOutbreaks:    "pest_outbreaks",
+	TreatmentPlans:   "treatment_plans",
 }
 
 const UserIdKey = "userID"
@@ -21,3 +51,12 @@ var AllowedImageContentTypes = map[string]bool{
 	"image/png":  true,
 	"image/webp": true,
 }
+
+// InviteLinkBaseURL is prefixed to household invite codes to build shareable links
+const InviteLinkBaseURL = "https://my.water-me.app/join/"
+
+// VacationLinkBaseURL is prefixed to vacation caretaker codes to build shareable links
+const VacationLinkBaseURL = "https://my.water-me.app/caretake/"
+
+// ShareLinkBaseURL is prefixed to plant share tokens to build public links
+const ShareLinkBaseURL = "https://my.water-me.app/shared/"
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+type requestIDKey struct{}
+
+// Setup installs a JSON slog logger as the process default. Records logged with
+// the *Context variants automatically carry the attributes attached via WithAttrs
+// and the trace and span ID of the active span.
+// The standard library log package is routed through the same handler.
+func Setup(component string) *slog.Logger {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: parseLevel(os.Getenv("LOG_LEVEL")),
+	})
+
+	logger := slog.New(contextHandler{Handler: handler}).With("component", component)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// WithAttrs returns a context whose log records include the given attributes
+// in addition to any already attached
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	existing, _ := ctx.Value(ctxKey{}).([]any)
+	attrs := make([]any, 0, len(existing)+len(args))
+	attrs = append(attrs, existing...)
+	attrs = append(attrs, args...)
+	return context.WithValue(ctx, ctxKey{}, attrs)
+}
+
+// WithRequestID stores the request ID in the context and adds it to its log attributes
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return WithAttrs(ctx, "requestId", requestID)
+}
+
+// RequestID returns the request ID stored in the context, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// HashUserID returns a stable, non-reversible identifier for a user so one
+// user's requests can be correlated across logs without logging the raw ID
+func HashUserID(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// contextHandler adds the attributes stored in the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(ctxKey{}).([]any); ok {
+		record.Add(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		record.Add("traceId", sc.TraceID().String(), "spanId", sc.SpanID().String())
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "waterme"
+
+// --- API ---
+
+var HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "http_requests_total",
+	Help:      "HTTP requests by route template, method and status code.",
+}, []string{"route", "method", "status"})
+
+var HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "http_request_duration_seconds",
+	Help:      "HTTP request latency by route template and method.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"route", "method"})
+
+var RateLimitRejections = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limit_rejections_total",
+	Help:      "Requests rejected with 429 by the rate limiter.",
+})
+
+// --- MongoDB ---
+
+var MongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "mongo_operation_duration_seconds",
+	Help:      "MongoDB command latency by command name and outcome.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"command", "outcome"})
+
+// --- Notification worker ---
+
+var NotificationsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "fcm_notifications_sent_total",
+	Help:      "Push notifications accepted by FCM by notification type.",
+}, []string{"type"})
+
+var NotificationsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "fcm_notifications_failed_total",
+	Help:      "Push notifications rejected by FCM by notification type.",
+}, []string{"type"})
+
+var TokensDeactivated = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "fcm_tokens_deactivated_total",
+	Help:      "Device tokens marked inactive after FCM rejected them.",
+})
+
+var PlantsDue = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "plants_due",
+	Help:      "Plants due for care in the last notification run by care type.",
+}, []string{"type"})
+
+// --- Cleanup worker ---
+
+var OrphanUploadsDeleted = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "orphan_uploads_deleted_total",
+	Help:      "Unreferenced uploads removed from S3 by the cleanup worker.",
+})
+
+// userBytesBuckets are the upper bounds used for the per-user storage distribution
+var userBytesBuckets = []float64{
+	256 << 10, // 256 KiB
+	1 << 20,   // 1 MiB
+	5 << 20,
+	10 << 20,
+	20 << 20,
+	40 << 20, // 20 uploads at the 2 MiB limit
+}
+
+var s3UserBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "s3_user_bytes",
+	Help:      "Number of users whose stored S3 bytes are at most le (cumulative, like a histogram).",
+}, []string{"le"})
+
+var s3BytesTotal = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "s3_bytes",
+	Help:      "Total bytes stored in S3 across all users.",
+})
+
+// SetS3UserBytes publishes a snapshot of the stored bytes per user.
+// Users are bucketed instead of labelled to keep the series count bounded.
+func SetS3UserBytes(usage map[string]int64) {
+	counts := make([]int, len(userBytesBuckets))
+	var total int64
+	for _, bytes := range usage {
+		total += bytes
+		for i, upper := range userBytesBuckets {
+			if float64(bytes) <= upper {
+				counts[i]++
+			}
+		}
+	}
+
+	for i, upper := range userBytesBuckets {
+		s3UserBytes.WithLabelValues(formatBound(upper)).Set(float64(counts[i]))
+	}
+	s3UserBytes.WithLabelValues("+Inf").Set(float64(len(usage)))
+	s3BytesTotal.Set(float64(total))
+}
+
+func formatBound(upper float64) string {
+	return strconv.FormatFloat(upper, 'f', -1, 64)
+}
+
+// MongoMonitor returns a command monitor that records the latency of every MongoDB command
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			MongoOperationDuration.WithLabelValues(evt.CommandName, "success").
+				Observe(evt.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			MongoOperationDuration.WithLabelValues(evt.CommandName, "error").
+				Observe(evt.Duration.Seconds())
+		},
+	}
+}
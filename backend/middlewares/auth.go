@@ -2,17 +2,55 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/logging"
 	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
 )
 
 type ctxKey string
 
+const apiTokenKey ctxKey = "apiToken"
+
+// publicPaths are served without authentication
+var publicPaths = map[string]bool{
+	"/api/stats": true,
+	"/livez":     true,
+	"/readyz":    true,
+	// Local auth mode
+	"/api/auth/signup":  true,
+	"/api/auth/login":   true,
+	"/api/auth/refresh": true,
+}
+
+// publicPrefixes are path prefixes served without authentication. Local storage
+// URLs carry their own signature; shared plants are protected by their token.
+var publicPrefixes = []string{
+	services.LocalStoragePath,
+	services.PublicSharePath,
+}
+
+func isPublicPath(path string) bool {
+	if publicPaths[path] {
+		return true
+	}
+	for _, prefix := range publicPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithUserID stores the user ID in the request context and adds its hash to the log context.
 func WithUserID(r *http.Request, userID string) *http.Request {
 	ctx := context.WithValue(r.Context(), constants.UserIdKey, userID)
+	ctx = logging.WithAttrs(ctx, "userHash", logging.HashUserID(userID))
 	return r.WithContext(ctx)
 }
 
@@ -22,8 +60,19 @@ func GetUserID(r *http.Request) (string, bool) {
 	return id, ok
 }
 
-// AuthMiddleware validates Bearer JWT and injects userID into context.
-func AuthMiddleware(firebase *services.FirebaseService) func(http.Handler) http.Handler {
+// GetAPIToken returns the personal API token the request was authenticated with, if any.
+func GetAPIToken(r *http.Request) (*types.APIToken, bool) {
+	token, ok := r.Context().Value(apiTokenKey).(*types.APIToken)
+	return token, ok
+}
+
+// AuthMiddleware validates the Bearer token and injects userID into context.
+// Personal API tokens are verified with apiTokens and restricted to the routes
+// their scopes allow; all other tokens are verified with authenticator.
+func AuthMiddleware(
+	authenticator services.Authenticator,
+	apiTokens *services.APITokens,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.Method == http.MethodOptions {
@@ -31,15 +80,19 @@ func AuthMiddleware(firebase *services.FirebaseService) func(http.Handler) http.
 				return
 			}
 
-			auth(next.ServeHTTP, firebase)(w, r)
+			auth(next.ServeHTTP, authenticator, apiTokens)(w, r)
 		})
 	}
 }
 
-func auth(next http.HandlerFunc, firebase *services.FirebaseService) http.HandlerFunc {
+func auth(
+	next http.HandlerFunc,
+	authenticator services.Authenticator,
+	apiTokens *services.APITokens,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Allow unauthenticated access to /api/stats
-		if r.URL.Path == "/api/stats" {
+		// Allow unauthenticated access to public endpoints and health probes
+		if isPublicPath(r.URL.Path) {
 			next(w, r)
 			return
 		}
@@ -51,14 +104,48 @@ func auth(next http.HandlerFunc, firebase *services.FirebaseService) http.Handle
 		}
 
 		tokenStr := strings.TrimPrefix(auth, "Bearer ")
-		token, err := firebase.VerifyIDToken(r.Context(), tokenStr)
+		if apiTokens != nil && services.IsAPIToken(tokenStr) {
+			authAPIToken(w, r, next, apiTokens, tokenStr)
+			return
+		}
+
+		uid, err := authenticator.Authenticate(r.Context(), tokenStr)
 		if err != nil {
 			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
 			return
 		}
 
-		uid := token.UID
-
 		next(w, WithUserID(r, uid))
 	}
 }
+
+func authAPIToken(
+	w http.ResponseWriter,
+	r *http.Request,
+	next http.HandlerFunc,
+	apiTokens *services.APITokens,
+	tokenStr string,
+) {
+	token, err := apiTokens.Authenticate(r.Context(), tokenStr)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		util.ServerError(w, r, err)
+		return
+	}
+
+	scope, ok := requiredScope(r)
+	if !ok {
+		http.Error(w, "Forbidden: route not available to API tokens", http.StatusForbidden)
+		return
+	}
+	if !token.HasScope(scope) {
+		http.Error(w, "Forbidden: token lacks scope "+string(scope), http.StatusForbidden)
+		return
+	}
+
+	r = WithUserID(r, token.UserID)
+	next(w, r.WithContext(context.WithValue(r.Context(), apiTokenKey, token)))
+}
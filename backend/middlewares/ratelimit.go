@@ -1,10 +1,13 @@
 package middlewares
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/metrics"
 )
 
 // RateLimiter tracks requests per identifier
@@ -95,7 +98,11 @@ func (rl *RateLimiter) IsRateLimited(userID string, ip string) bool {
 	}
 	limiter.count++
 	if limiter.count > userRequestsPerMinute {
-		log.Printf("Rate limit exceeded for user %s: %d requests", userID, limiter.count)
+		slog.Warn(
+			"rate limit exceeded for user",
+			"userHash", logging.HashUserID(userID),
+			"requests", limiter.count,
+		)
 		return true
 	}
 
@@ -106,7 +113,7 @@ func (rl *RateLimiter) IsRateLimited(userID string, ip string) bool {
 	}
 	limiter.requestsIP++
 	if limiter.requestsIP > ipRequestsPerMinute {
-		log.Printf("Rate limit exceeded for IP %s: %d requests", ip, limiter.requestsIP)
+		slog.Warn("rate limit exceeded for IP", "ip", ip, "requests", limiter.requestsIP)
 		return true
 	}
 
@@ -121,6 +128,7 @@ func RateLimitMiddleware(rl *RateLimiter) func(http.Handler) http.Handler {
 			userID := r.Header.Get("X-User-ID") // Set by auth middleware
 
 			if rl.IsRateLimited(userID, ip) {
+				metrics.RateLimitRejections.Inc()
 				w.Header().Set("Retry-After", "60")
 				http.Error(w, "Too many requests", http.StatusTooManyRequests)
 				return
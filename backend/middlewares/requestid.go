@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/qreepex/water-me-app/backend/logging"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is read from incoming requests and echoed on every response
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits client supplied IDs to something safe to log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware propagates X-Request-ID, generating one when the client
+// did not send a usable value, and attaches it to the request's log context.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
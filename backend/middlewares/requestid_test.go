@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/logging"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	}))
+
+	t.Run("propagates a valid client ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/plants", nil)
+		req.Header.Set(RequestIDHeader, "abc-123")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if seen != "abc-123" || rec.Header().Get(RequestIDHeader) != "abc-123" {
+			t.Errorf("expected abc-123 in context and response, got %q / %q",
+				seen, rec.Header().Get(RequestIDHeader))
+		}
+	})
+
+	t.Run("replaces an unsafe client ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/plants", nil)
+		req.Header.Set(RequestIDHeader, "bad id\nforged log line")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if seen == "" || seen == req.Header.Get(RequestIDHeader) {
+			t.Errorf("expected a generated ID, got %q", seen)
+		}
+		if rec.Header().Get(RequestIDHeader) != seen {
+			t.Errorf("response header %q does not match context %q",
+				rec.Header().Get(RequestIDHeader), seen)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"github.com/gorilla/mux"
+)
+
+// routeScopes maps "METHOD /path/template" to the scope an API token needs for
+// the route. Routes not listed here cannot be used with API tokens.
+var routeScopes = map[string]types.TokenScope{
+	"GET /api/plants":                                        types.ScopePlantsRead,
+	"GET /api/plants/{id}":                                   types.ScopePlantsRead,
+	"GET /api/plants/slug/{slug}":                            types.ScopePlantsRead,
+	"GET /api/plants/export":                                 types.ScopePlantsRead,
+	"GET /api/plants/risky":                                  types.ScopePlantsRead,
+	"POST /api/plants/water":                                 types.ScopePlantsCare,
+	"GET /api/species":                                       types.ScopePlantsRead,
+	"GET /api/species/{id}":                                  types.ScopePlantsRead,
+	"GET /api/rooms":                                         types.ScopePlantsRead,
+	"GET /api/rooms/{id}":                                    types.ScopePlantsRead,
+	"GET /api/rooms/{id}/plants":                             types.ScopePlantsRead,
+	"GET /api/rooms/placement":                               types.ScopePlantsRead,
+	"GET /api/plants/{id}/placement":                         types.ScopePlantsRead,
+	"GET /api/plants/watering-recommendations":               types.ScopePlantsRead,
+	"GET /api/plants/{id}/watering-recommendation":           types.ScopePlantsRead,
+	"POST /api/plants/{id}/watering-recommendation/accept":   types.ScopePlantsCare,
+	"POST /api/plants/{id}/snooze":                           types.ScopePlantsCare,
+	"GET /api/plants/analytics":                              types.ScopePlantsRead,
+	"GET /api/plants/{id}/analytics":                         types.ScopePlantsRead,
+	"GET /api/pests/outbreaks":                               types.ScopePlantsRead,
+	"GET /api/pests/stats":                                   types.ScopePlantsRead,
+	"GET /api/plants/{id}/treatments":                        types.ScopePlantsRead,
+	"POST /api/plants/{id}/treatments/{planId}/applications": types.ScopePlantsCare,
+
+	"GET /api/notifications":                      types.ScopeNotificationsManage,
+	"PUT /api/notifications":                      types.ScopeNotificationsManage,
+	"DELETE /api/notifications":                   types.ScopeNotificationsManage,
+	"POST /api/notifications/tokens":              types.ScopeNotificationsManage,
+	"DELETE /api/notifications/tokens/{deviceId}": types.ScopeNotificationsManage,
+}
+
+// requiredScope returns the scope an API token needs for the matched route
+func requiredScope(r *http.Request) (types.TokenScope, bool) {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "", false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return "", false
+	}
+	scope, ok := routeScopes[r.Method+" "+template]
+	return scope, ok
+}
@@ -0,0 +1,943 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/catalog"
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// all lists every migration. Append new ones with the next version number and
+// never change or renumber a migration that has shipped.
+var all = []Migration{
+	{
+		Version:     1,
+		Description: "backfill missing and duplicate plant slugs",
+		Up:          backfillPlantSlugs,
+	},
+	{
+		Version:     2,
+		Description: "index plants by userId, (userId, slug) and photoIds",
+		Up:          createPlantIndexes,
+	},
+	{
+		Version:     3,
+		Description: "merge duplicate notification configs and index userId as unique",
+		Up:          createNotificationIndexes,
+	},
+	{
+		Version:     4,
+		Description: "index uploads by createdAt",
+		Up:          createUploadIndexes,
+	},
+	{
+		Version:     5,
+		Description: "index users by unique email",
+		Up:          createUserIndexes,
+	},
+	{
+		Version:     6,
+		Description: "index api tokens by unique hash and userId",
+		Up:          createAPITokenIndexes,
+	},
+	{
+		Version:     7,
+		Description: "index households by member, invites by code and plants by householdId",
+		Up:          createHouseholdIndexes,
+	},
+	{
+		Version:     8,
+		Description: "index care events by owner and plant, vacations by user, code and caretaker",
+		Up:          createVacationIndexes,
+	},
+	{
+		Version:     9,
+		Description: "index plant shares by unique token and plantId",
+		Up:          createPlantShareIndexes,
+	},
+	{
+		Version:     10,
+		Description: "index uploads, care events and plant shares by user for account erasure",
+		Up:          createAccountErasureIndexes,
+	},
+	{
+		Version:     11,
+		Description: "index exports by user and expiry",
+		Up:          createExportIndexes,
+	},
+	{
+		Version:     12,
+		Description: "index species by names and seed the bundled species catalog",
+		Up:          seedSpecies,
+	},
+	{
+		Version:     13,
+		Description: "copy species toxicity to the plants linked to them",
+		Up:          backfillPlantToxicity,
+	},
+	{
+		Version:     14,
+		Description: "index rooms by (userId, nameKey), create rooms from plant locations and link residents to them",
+		Up:          migrateRooms,
+	},
+	{
+		Version:     15,
+		Description: "index pest outbreaks by user and next inspection",
+		Up:          createPestOutbreakIndexes,
+	},
+	{
+		Version:     16,
+		Description: "index treatment plans by plant and next reminder",
+		Up:          createTreatmentPlanIndexes,
+	},
+	{
+		Version:     17,
+		Description: "fail duplicate active exports and allow one active export per user",
+		Up:          createActiveExportIndex,
+	},
+}
+
+// createIndexes is idempotent: MongoDB ignores an index that already exists
+// with the same name and options
+func createIndexes(
+	ctx context.Context,
+	collection *mongo.Collection,
+	models ...mongo.IndexModel,
+) error {
+	names, err := collection.Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return fmt.Errorf("create indexes on %s: %w", collection.Name(), err)
+	}
+	slog.InfoContext(ctx, "indexes ensured", "collection", collection.Name(), "indexes", names)
+	return nil
+}
+
+func createPlantIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Plants),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "slug", Value: 1}},
+			Options: options.Index().SetName("userId_slug_unique").SetUnique(true),
+		},
+		// Used by the orphaned upload check in IsUploadReferenced
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "photoIds", Value: 1}},
+			Options: options.Index().SetName("photoIds"),
+		},
+	)
+}
+
+func createNotificationIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := mergeDuplicateNotificationConfigs(ctx, db); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Notifications),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId_unique").SetUnique(true),
+		},
+	)
+}
+
+func createUploadIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Uploads),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("createdAt"),
+		},
+	)
+}
+
+func createUserIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Users),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("email_unique").SetUnique(true),
+		},
+	)
+}
+
+func createAPITokenIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.APITokens),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName("hash_unique").SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId"),
+		},
+	)
+}
+
+func createHouseholdIndexes(ctx context.Context, db *mongo.Database) error {
+	err := createIndexes(ctx, db.Collection(constants.MongoDBCollections.Households),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "members.userId", Value: 1}},
+			Options: options.Index().SetName("members_userId"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = createIndexes(ctx, db.Collection(constants.MongoDBCollections.HouseholdInvites),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("code_unique").SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "householdId", Value: 1}},
+			Options: options.Index().SetName("householdId"),
+		},
+		// MongoDB removes expired invites by itself
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Plants),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "householdId", Value: 1}},
+			Options: options.Index().SetName("householdId").SetSparse(true),
+		},
+	)
+}
+
+func createVacationIndexes(ctx context.Context, db *mongo.Database) error {
+	err := createIndexes(ctx, db.Collection(constants.MongoDBCollections.CareEvents),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "ownerId", Value: 1}, {Key: "at", Value: 1}},
+			Options: options.Index().SetName("ownerId_at"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "plantId", Value: 1}, {Key: "at", Value: 1}},
+			Options: options.Index().SetName("plantId_at"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Vacations),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "inviteCode", Value: 1}},
+			Options: options.Index().SetName("inviteCode_unique").SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "caretakerId", Value: 1}},
+			Options: options.Index().SetName("caretakerId").SetSparse(true),
+		},
+		// Used by the notification worker to find summaries to send
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "endsAt", Value: 1}, {Key: "summarySentAt", Value: 1}},
+			Options: options.Index().SetName("endsAt_summarySentAt"),
+		},
+	)
+}
+
+func createPlantShareIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.PlantShares),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetName("token_unique").SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "plantId", Value: 1}},
+			Options: options.Index().SetName("plantId"),
+		},
+	)
+}
+
+func createAccountErasureIndexes(ctx context.Context, db *mongo.Database) error {
+	err := createIndexes(ctx, db.Collection(constants.MongoDBCollections.Uploads),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = createIndexes(ctx, db.Collection(constants.MongoDBCollections.CareEvents),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.PlantShares),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "ownerId", Value: 1}},
+			Options: options.Index().SetName("ownerId"),
+		},
+	)
+}
+
+func createExportIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.Exports),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("userId_createdAt"),
+		},
+		// Not a TTL index: the cleanup worker deletes the archive together with the record
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt").SetSparse(true),
+		},
+	)
+}
+
+func createPestOutbreakIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.PestOutbreaks),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "detectedAt", Value: -1}},
+			Options: options.Index().SetName("userId_detectedAt"),
+		},
+		// Used by the notification worker to find inspections to remind of
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextInspectionAt", Value: 1}},
+			Options: options.Index().SetName("status_nextInspectionAt"),
+		},
+	)
+}
+
+func createTreatmentPlanIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db.Collection(constants.MongoDBCollections.TreatmentPlans),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "plantId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("plantId_createdAt"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("userId"),
+		},
+		// Used by the notification worker to find treatments to remind of
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextReminderAt", Value: 1}},
+			Options: options.Index().SetName("status_nextReminderAt"),
+		},
+	)
+}
+
+// seedSpecies inserts the bundled species that are missing. Existing entries
+// are left alone, so later edits to the catalog are not overwritten.
+func seedSpecies(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(constants.MongoDBCollections.Species)
+	err := createIndexes(ctx, collection,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "scientificName", Value: 1}},
+			Options: options.Index().SetName("scientificName_unique").SetUnique(true),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "commonNames.name", Value: 1}},
+			Options: options.Index().SetName("commonNames_name"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	species, err := catalog.Species()
+	if err != nil {
+		return err
+	}
+	inserted := 0
+	for _, entry := range species {
+		result, err := collection.UpdateOne(ctx,
+			bson.M{"_id": entry.ID},
+			bson.M{"$setOnInsert": entry},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("seed species %s: %w", entry.ID, err)
+		}
+		if result.UpsertedCount > 0 {
+			inserted++
+		}
+	}
+	slog.InfoContext(ctx, "seeded species catalog", "bundled", len(species), "inserted", inserted)
+	return nil
+}
+
+// backfillPlantToxicity fills in the per-animal toxicity of plants linked to a
+// species with toxicity data. Plants with their own toxicity are left alone.
+func backfillPlantToxicity(ctx context.Context, db *mongo.Database) error {
+	cursor, err := db.Collection(constants.MongoDBCollections.Species).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var species []types.Species
+	if err := cursor.All(ctx, &species); err != nil {
+		return err
+	}
+
+	plants := db.Collection(constants.MongoDBCollections.Plants)
+	var updated int64
+	for _, entry := range species {
+		toxicity := entry.Toxicity
+		if toxicity.Cats == "" && toxicity.Dogs == "" && toxicity.Humans == "" {
+			continue
+		}
+		result, err := plants.UpdateMany(ctx,
+			bson.M{"speciesId": entry.ID, "toxicity": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"toxicity": toxicity, "isToxic": toxicity.Toxic()}},
+		)
+		if err != nil {
+			return fmt.Errorf("backfill toxicity for %s: %w", entry.ID, err)
+		}
+		updated += result.ModifiedCount
+	}
+	slog.InfoContext(ctx, "backfilled plant toxicity", "plants", updated)
+	return nil
+}
+
+// migrateRooms turns the free-text room names of plant locations into rooms.
+// Names that differ only in case or spacing become one room, named as the
+// oldest plant spells it. Plants already linked to a room are left alone.
+// Residents of safety profiles then reach the rooms by ID.
+func migrateRooms(ctx context.Context, db *mongo.Database) error {
+	rooms := db.Collection(constants.MongoDBCollections.Rooms)
+	err := createIndexes(ctx, rooms,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "nameKey", Value: 1}},
+			Options: options.Index().SetName("userId_nameKey_unique").SetUnique(true),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	plants := db.Collection(constants.MongoDBCollections.Plants)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}}).
+		SetProjection(bson.M{"userId": 1, "location": 1})
+	cursor, err := plants.Find(ctx, bson.M{
+		"location.room":   bson.M{"$nin": bson.A{nil, ""}},
+		"location.roomId": bson.M{"$in": bson.A{nil, ""}},
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("list plants: %w", err)
+	}
+	var unlinked []types.Plant
+	if err := cursor.All(ctx, &unlinked); err != nil {
+		return fmt.Errorf("decode plants: %w", err)
+	}
+
+	created, linked := 0, int64(0)
+	for _, group := range roomGroups(unlinked) {
+		first := group[0]
+		room, isNew, err := findOrCreateRoom(ctx, rooms, first.UserID, *first.Location)
+		if err != nil {
+			return err
+		}
+		if isNew {
+			created++
+		}
+
+		filter, update, err := roomLink(group, room)
+		if err != nil {
+			return err
+		}
+		result, err := plants.UpdateMany(ctx, filter, update)
+		if err != nil {
+			return fmt.Errorf("link plants to room: %w", err)
+		}
+		linked += result.ModifiedCount
+	}
+
+	profiles, residentRooms, err := migrateResidentRooms(ctx, db, rooms)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "migrated plant rooms",
+		"roomsCreated", created+residentRooms, "plantsLinked", linked, "profilesUpdated", profiles)
+	return nil
+}
+
+// legacyResident is a Resident as stored when residents reached rooms by name
+type legacyResident struct {
+	types.Resident `bson:",inline"`
+	Rooms          []string `bson:"rooms,omitempty"`
+}
+
+// migrateResidentRooms replaces the room names that residents of safety
+// profiles can reach with room IDs. A user's profile gets the user's rooms
+// of those names, a household's those of all members. Names without a room
+// get one, for households in the household owner's rooms. It returns the
+// number of profiles updated and rooms created.
+func migrateResidentRooms(ctx context.Context, db *mongo.Database, rooms *mongo.Collection) (int, int, error) {
+	updated, created := 0, 0
+
+	profiles := db.Collection(constants.MongoDBCollections.SafetyProfiles)
+	cursor, err := profiles.Find(ctx, bson.M{"residents.rooms": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, 0, fmt.Errorf("list safety profiles: %w", err)
+	}
+	var userProfiles []struct {
+		UserID    string           `bson:"_id"`
+		Residents []legacyResident `bson:"residents"`
+	}
+	if err := cursor.All(ctx, &userProfiles); err != nil {
+		return 0, 0, fmt.Errorf("decode safety profiles: %w", err)
+	}
+	for _, profile := range userProfiles {
+		residents, n, err := linkResidentRooms(ctx, rooms, profile.Residents, []string{profile.UserID}, profile.UserID)
+		if err != nil {
+			return 0, 0, err
+		}
+		created += n
+		_, err = profiles.UpdateOne(ctx,
+			bson.M{"_id": profile.UserID},
+			bson.M{"$set": bson.M{"residents": residents}},
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf("update safety profile: %w", err)
+		}
+		updated++
+	}
+
+	households := db.Collection(constants.MongoDBCollections.Households)
+	cursor, err = households.Find(ctx, bson.M{"profile.residents.rooms": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, 0, fmt.Errorf("list households: %w", err)
+	}
+	var householdProfiles []struct {
+		ID      string                  `bson:"_id"`
+		Members []types.HouseholdMember `bson:"members"`
+		Profile struct {
+			Residents []legacyResident `bson:"residents"`
+		} `bson:"profile"`
+	}
+	if err := cursor.All(ctx, &householdProfiles); err != nil {
+		return 0, 0, fmt.Errorf("decode households: %w", err)
+	}
+	for _, household := range householdProfiles {
+		if len(household.Members) == 0 {
+			continue
+		}
+		owners := make([]string, 0, len(household.Members))
+		creator := household.Members[0].UserID
+		for _, member := range household.Members {
+			owners = append(owners, member.UserID)
+			if member.Role == types.RoleOwner {
+				creator = member.UserID
+			}
+		}
+		residents, n, err := linkResidentRooms(ctx, rooms, household.Profile.Residents, owners, creator)
+		if err != nil {
+			return 0, 0, err
+		}
+		created += n
+		_, err = households.UpdateOne(ctx,
+			bson.M{"_id": household.ID},
+			bson.M{"$set": bson.M{"profile.residents": residents}},
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf("update household profile: %w", err)
+		}
+		updated++
+	}
+	return updated, created, nil
+}
+
+// linkResidentRooms returns residents reaching the rooms of owners named like
+// their room names, creating the missing rooms for creator, and the number of
+// rooms created
+func linkResidentRooms(
+	ctx context.Context,
+	rooms *mongo.Collection,
+	residents []legacyResident,
+	owners []string,
+	creator string,
+) ([]types.Resident, int, error) {
+	cursor, err := rooms.Find(ctx, bson.M{"userId": bson.M{"$in": owners}},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, 0, fmt.Errorf("list rooms: %w", err)
+	}
+	var existing []types.Room
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, 0, fmt.Errorf("decode rooms: %w", err)
+	}
+	roomIDs := make(map[string][]string)
+	for _, room := range existing {
+		roomIDs[room.NameKey] = append(roomIDs[room.NameKey], room.ID)
+	}
+
+	created := 0
+	for _, resident := range residents {
+		for _, name := range resident.Rooms {
+			key := types.RoomNameKey(name)
+			if key == "" || len(roomIDs[key]) > 0 {
+				continue
+			}
+			room, isNew, err := findOrCreateRoom(ctx, rooms, creator, types.Location{Room: strings.TrimSpace(name)})
+			if err != nil {
+				return nil, 0, err
+			}
+			if isNew {
+				created++
+			}
+			roomIDs[key] = append(roomIDs[key], room.ID)
+		}
+	}
+	return residentRoomIDs(residents, roomIDs), created, nil
+}
+
+// residentRoomIDs returns residents that reach the rooms in roomIDs, by name
+// key, of their room names
+func residentRoomIDs(residents []legacyResident, roomIDs map[string][]string) []types.Resident {
+	result := make([]types.Resident, 0, len(residents))
+	for _, legacy := range residents {
+		resident := legacy.Resident
+		for _, name := range legacy.Rooms {
+			for _, id := range roomIDs[types.RoomNameKey(name)] {
+				if !slices.Contains(resident.RoomIDs, id) {
+					resident.RoomIDs = append(resident.RoomIDs, id)
+				}
+			}
+		}
+		result = append(result, resident)
+	}
+	return result
+}
+
+// roomLink returns the filter and update that link the plants of group to
+// room. Plant IDs are stored as ObjectIDs.
+func roomLink(group []types.Plant, room types.Room) (bson.M, bson.M, error) {
+	ids := make(bson.A, 0, len(group))
+	for _, plant := range group {
+		oid, err := objectID(plant.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, oid)
+	}
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+	update := bson.M{"$set": bson.M{"location.roomId": room.ID, "location.room": room.Name}}
+	return filter, update, nil
+}
+
+// roomGroups groups plants with a room name by user and room name key, in the
+// order the first plant of each group appears
+func roomGroups(plants []types.Plant) [][]types.Plant {
+	index := make(map[[2]string]int)
+	var groups [][]types.Plant
+	for _, plant := range plants {
+		if plant.Location == nil {
+			continue
+		}
+		nameKey := types.RoomNameKey(plant.Location.Room)
+		if nameKey == "" {
+			continue
+		}
+		key := [2]string{plant.UserID, nameKey}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], plant)
+	}
+	return groups
+}
+
+// findOrCreateRoom returns the user's room named like loc.Room, creating it
+// from loc if there is none, and whether it did. Reruns find the rooms that
+// earlier runs created.
+func findOrCreateRoom(
+	ctx context.Context,
+	rooms *mongo.Collection,
+	userID string,
+	loc types.Location,
+) (types.Room, bool, error) {
+	var room types.Room
+	err := rooms.FindOne(ctx, bson.M{"userId": userID, "nameKey": types.RoomNameKey(loc.Room)}).Decode(&room)
+	if err == nil {
+		return room, false, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return room, false, fmt.Errorf("find room: %w", err)
+	}
+
+	room, err = services.NewRoom(userID, types.CreateRoomRequest{Name: loc.Room, IsOutdoors: loc.IsOutdoors}, time.Now())
+	if err != nil {
+		return room, false, err
+	}
+	if _, err := rooms.InsertOne(ctx, room); err != nil {
+		return room, false, fmt.Errorf("create room: %w", err)
+	}
+	return room, true, nil
+}
+
+// backfillPlantSlugs gives every plant without a slug, or whose slug is already
+// used by an older plant of the same user, a fresh unique slug. This must run
+// before the unique (userId, slug) index is created.
+func backfillPlantSlugs(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(constants.MongoDBCollections.Plants)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}}).
+		SetProjection(bson.M{"userId": 1, "slug": 1, "name": 1, "location": 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("list plants: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	var userPlants []types.Plant
+	flush := func() error {
+		n, err := fixUserSlugs(ctx, collection, userPlants)
+		updated += n
+		userPlants = userPlants[:0]
+		return err
+	}
+
+	for cursor.Next(ctx) {
+		var plant types.Plant
+		if err := cursor.Decode(&plant); err != nil {
+			return fmt.Errorf("decode plant: %w", err)
+		}
+		if len(userPlants) > 0 && userPlants[0].UserID != plant.UserID {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		userPlants = append(userPlants, plant)
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("iterate plants: %w", err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "plant slugs backfilled", "updated", updated)
+	return nil
+}
+
+// fixUserSlugs stores new slugs for one user's plants with an empty or
+// duplicate slug and returns how many were changed
+func fixUserSlugs(
+	ctx context.Context,
+	collection *mongo.Collection,
+	plants []types.Plant,
+) (int, error) {
+	fixed := slugFixes(plants)
+	for _, plant := range fixed {
+		oid, err := objectID(plant.ID)
+		if err != nil {
+			return 0, err
+		}
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"_id": oid},
+			bson.M{"$set": bson.M{"slug": plant.Slug}},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("update slug of plant %s: %w", plant.ID, err)
+		}
+	}
+	return len(fixed), nil
+}
+
+// slugFixes returns the plants (ordered oldest first) that need a new slug,
+// with the new slug set. The oldest plant keeps a contested slug.
+func slugFixes(plants []types.Plant) []types.Plant {
+	var kept, broken []types.Plant
+	taken := make(map[string]bool, len(plants))
+	for _, plant := range plants {
+		if plant.Slug != "" && !taken[plant.Slug] {
+			taken[plant.Slug] = true
+			kept = append(kept, plant)
+		} else {
+			broken = append(broken, plant)
+		}
+	}
+
+	for i := range broken {
+		broken[i].Slug = util.GenerateUniqueSlug(broken[i].Name, broken[i].Location, kept)
+		kept = append(kept, broken[i])
+	}
+	return broken
+}
+
+func objectID(id string) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("invalid object id %q: %w", id, err)
+	}
+	return oid, nil
+}
+
+// mergeDuplicateNotificationConfigs keeps the most recently updated config per
+// user, moves device tokens from the other configs into it and deletes them
+func mergeDuplicateNotificationConfigs(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(constants.MongoDBCollections.Notifications)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$group": bson.M{"_id": "$userId", "count": bson.M{"$sum": 1}}},
+		bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("find duplicate notification configs: %w", err)
+	}
+	var duplicates []struct {
+		UserID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return fmt.Errorf("decode duplicate notification configs: %w", err)
+	}
+
+	for _, duplicate := range duplicates {
+		cursor, err := collection.Find(ctx,
+			bson.M{"userId": duplicate.UserID},
+			options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}}),
+		)
+		if err != nil {
+			return fmt.Errorf("list notification configs: %w", err)
+		}
+		var configs []struct {
+			ID           any                 `bson:"_id"`
+			DeviceTokens []types.DeviceToken `bson:"deviceTokens"`
+		}
+		if err := cursor.All(ctx, &configs); err != nil {
+			return fmt.Errorf("decode notification configs: %w", err)
+		}
+		if len(configs) < 2 {
+			continue
+		}
+
+		keeper := configs[0]
+		tokens := keeper.DeviceTokens
+		seen := make(map[string]bool, len(tokens))
+		for _, token := range tokens {
+			seen[token.Token] = true
+		}
+		var obsolete bson.A
+		for _, config := range configs[1:] {
+			for _, token := range config.DeviceTokens {
+				if !seen[token.Token] {
+					seen[token.Token] = true
+					tokens = append(tokens, token)
+				}
+			}
+			obsolete = append(obsolete, config.ID)
+		}
+
+		// Update before delete so a crash in between never loses tokens
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"_id": keeper.ID},
+			bson.M{"$set": bson.M{"deviceTokens": tokens}},
+		)
+		if err != nil {
+			return fmt.Errorf("merge device tokens: %w", err)
+		}
+		_, err = collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": obsolete}})
+		if err != nil {
+			return fmt.Errorf("delete duplicate notification configs: %w", err)
+		}
+
+		slog.InfoContext(ctx, "merged duplicate notification configs",
+			"userHash", logging.HashUserID(duplicate.UserID),
+			"removed", len(obsolete),
+		)
+	}
+	return nil
+}
+
+// activeExportStatuses are the statuses of exports that are still being built
+var activeExportStatuses = bson.A{types.ExportPending, types.ExportRunning}
+
+// createActiveExportIndex keeps each user's newest active export, marks the
+// others as failed and indexes userId as unique among active exports, so that
+// concurrent requests cannot start two exports. $in in a partial filter
+// requires MongoDB 6.0.
+func createActiveExportIndex(ctx context.Context, db *mongo.Database) error {
+	exports := db.Collection(constants.MongoDBCollections.Exports)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}).
+		SetProjection(bson.M{"userId": 1, "status": 1, "createdAt": 1})
+	cursor, err := exports.Find(ctx, bson.M{"status": bson.M{"$in": activeExportStatuses}}, opts)
+	if err != nil {
+		return fmt.Errorf("list active exports: %w", err)
+	}
+	var active []types.DataExport
+	if err := cursor.All(ctx, &active); err != nil {
+		return fmt.Errorf("decode exports: %w", err)
+	}
+
+	if ids := duplicateActiveExports(active); len(ids) > 0 {
+		now := time.Now()
+		result, err := exports.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": ids}},
+			bson.M{"$set": bson.M{
+				"status":    types.ExportFailed,
+				"error":     "Export was interrupted",
+				"updatedAt": now,
+				"expiresAt": now.Add(services.ExportRetention),
+			}},
+		)
+		if err != nil {
+			return fmt.Errorf("fail duplicate exports: %w", err)
+		}
+		slog.InfoContext(ctx, "duplicate active exports failed", "count", result.ModifiedCount)
+	}
+
+	return createIndexes(ctx, exports,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().
+				SetName("userId_active_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": bson.M{"$in": activeExportStatuses}}),
+		},
+	)
+}
+
+// duplicateActiveExports returns the IDs of all but the first export of each
+// user, given exports sorted by user and newest first
+func duplicateActiveExports(exports []types.DataExport) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, export := range exports {
+		if seen[export.UserID] {
+			ids = append(ids, export.ID)
+			continue
+		}
+		seen[export.UserID] = true
+	}
+	return ids
+}
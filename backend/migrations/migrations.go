@@ -0,0 +1,119 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration is one versioned schema change. Up must be idempotent: a run that
+// crashes after Up but before the version is recorded will execute it again.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record is the document stored in schema_migrations for an applied migration
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+	DurationMs  int64     `bson:"durationMs"`
+}
+
+// Applied returns the recorded migrations keyed by version
+func Applied(ctx context.Context, db *mongo.Database) (map[int]Record, error) {
+	cursor, err := db.Collection(constants.MongoDBCollections.SchemaMigrations).
+		Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("decode applied migrations: %w", err)
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// Pending returns the migrations that have not been applied yet, in version order
+func Pending(ctx context.Context, db *mongo.Database) ([]Migration, error) {
+	applied, err := Applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range All() {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies all pending migrations in version order and records each one
+// as soon as it succeeds. It stops at the first failure.
+func Run(ctx context.Context, db *mongo.Database) ([]Migration, error) {
+	pending, err := Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := db.Collection(constants.MongoDBCollections.SchemaMigrations)
+
+	var done []Migration
+	for _, migration := range pending {
+		slog.InfoContext(ctx, "applying migration",
+			"version", migration.Version,
+			"description", migration.Description,
+		)
+
+		start := time.Now()
+		if err := migration.Up(ctx, db); err != nil {
+			return done, fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		record := Record{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+			DurationMs:  time.Since(start).Milliseconds(),
+		}
+		// Upsert so a concurrent runner recording the same version is harmless
+		_, err := collection.ReplaceOne(ctx,
+			bson.M{"_id": record.Version},
+			record,
+			options.Replace().SetUpsert(true),
+		)
+		if err != nil {
+			return done, fmt.Errorf("record migration %d: %w", migration.Version, err)
+		}
+
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// All returns every known migration ordered by version
+func All() []Migration {
+	list := make([]Migration, len(all))
+	copy(list, all)
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list
+}
@@ -0,0 +1,149 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSlugFixes(t *testing.T) {
+	plants := []types.Plant{
+		{ID: "1", Name: "Monstera", Slug: "monstera"},
+		{ID: "2", Name: "Monstera", Slug: "monstera"},
+		{ID: "3", Name: "Ficus", Slug: ""},
+		{ID: "4", Name: "Ficus", Slug: "ficus"},
+	}
+
+	fixed := slugFixes(plants)
+
+	want := map[string]string{"2": "monstera-1", "3": "ficus-1"}
+	if len(fixed) != len(want) {
+		t.Fatalf("got %d fixes, want %d: %+v", len(fixed), len(want), fixed)
+	}
+	for _, plant := range fixed {
+		if plant.Slug != want[plant.ID] {
+			t.Errorf("plant %s: got slug %q, want %q", plant.ID, plant.Slug, want[plant.ID])
+		}
+	}
+}
+
+func TestSlugFixesLeavesValidSlugs(t *testing.T) {
+	plants := []types.Plant{
+		{ID: "1", Name: "Monstera", Slug: "monstera"},
+		{ID: "2", Name: "Ficus", Slug: "ficus"},
+	}
+	if fixed := slugFixes(plants); len(fixed) != 0 {
+		t.Fatalf("expected no fixes, got %+v", fixed)
+	}
+}
+
+func TestAllVersionsAreUnique(t *testing.T) {
+	seen := map[int]bool{}
+	for i, migration := range All() {
+		if seen[migration.Version] {
+			t.Fatalf("duplicate migration version %d", migration.Version)
+		}
+		if i > 0 && migration.Version <= All()[i-1].Version {
+			t.Fatalf("migrations not ordered at version %d", migration.Version)
+		}
+		seen[migration.Version] = true
+	}
+}
+
+func TestRoomGroupsIgnoreCaseAndSpacing(t *testing.T) {
+	plants := []types.Plant{
+		{ID: "1", UserID: "a", Location: &types.Location{Room: "Living Room"}},
+		{ID: "2", UserID: "a", Location: &types.Location{Room: " living  room"}},
+		{ID: "3", UserID: "b", Location: &types.Location{Room: "Living Room"}},
+		{ID: "4", UserID: "a", Location: &types.Location{Room: "  "}},
+		{ID: "5", UserID: "a"},
+	}
+
+	groups := roomGroups(plants)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+	if len(groups[0]) != 2 || groups[0][0].ID != "1" || groups[0][1].ID != "2" {
+		t.Errorf("first group: got %+v", groups[0])
+	}
+	if len(groups[1]) != 1 || groups[1][0].ID != "3" {
+		t.Errorf("second group: got %+v", groups[1])
+	}
+}
+
+func TestRoomLinkMatchesObjectIDsAndSetsRoomID(t *testing.T) {
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	group := []types.Plant{
+		{ID: first.Hex(), UserID: "a", Location: &types.Location{Room: "living room"}},
+		{ID: second.Hex(), UserID: "a", Location: &types.Location{Room: "Living Room"}},
+	}
+	room := types.Room{ID: "room1", Name: "Living Room"}
+
+	filter, update, err := roomLink(group, room)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, _ := filter["_id"].(bson.M)["$in"].(bson.A)
+	if len(ids) != 2 || ids[0] != first || ids[1] != second {
+		t.Errorf("filter: got %v, want the plants' ObjectIDs", filter)
+	}
+	set, _ := update["$set"].(bson.M)
+	if set["location.roomId"] != "room1" || set["location.room"] != "Living Room" {
+		t.Errorf("update: got %v", update)
+	}
+
+	if _, _, err := roomLink([]types.Plant{{ID: "not-an-id"}}, room); err == nil {
+		t.Error("invalid plant ID: want an error")
+	}
+}
+
+func TestResidentRoomIDsReplacesRoomNames(t *testing.T) {
+	raw, err := bson.Marshal(bson.M{"kind": "dog", "name": "Rex", "rooms": bson.A{"kitchen", " Hall ", "KITCHEN"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dog legacyResident
+	if err := bson.Unmarshal(raw, &dog); err != nil {
+		t.Fatal(err)
+	}
+	residents := []legacyResident{dog, {Resident: types.Resident{Kind: types.ResidentCat}}}
+	roomIDs := map[string][]string{"kitchen": {"k1", "k2"}, "hall": {"h1"}}
+
+	got := residentRoomIDs(residents, roomIDs)
+	if len(got) != 2 || got[0].Kind != types.ResidentDog || got[0].Name != "Rex" {
+		t.Fatalf("residents: got %+v", got)
+	}
+	if ids := got[0].RoomIDs; len(ids) != 3 || ids[0] != "k1" || ids[1] != "k2" || ids[2] != "h1" {
+		t.Errorf("room IDs: got %v", ids)
+	}
+	if got[1].RoomIDs != nil {
+		t.Errorf("resident without rooms: got %v, want every room", got[1].RoomIDs)
+	}
+
+	stored, err := bson.Marshal(got[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bson.Raw(stored).LookupErr("rooms"); err == nil {
+		t.Error("migrated resident still stores room names")
+	}
+}
+
+func TestDuplicateActiveExportsKeepsNewestPerUser(t *testing.T) {
+	exports := []types.DataExport{
+		{ID: "a2", UserID: "a"},
+		{ID: "a1", UserID: "a"},
+		{ID: "b1", UserID: "b"},
+		{ID: "c3", UserID: "c"},
+		{ID: "c2", UserID: "c"},
+		{ID: "c1", UserID: "c"},
+	}
+
+	got := duplicateActiveExports(exports)
+	if len(got) != 3 || got[0] != "a1" || got[1] != "c2" || got[2] != "c1" {
+		t.Errorf("duplicates: got %v, want [a1 c2 c1]", got)
+	}
+}
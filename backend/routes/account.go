@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/gorilla/mux"
+)
+
+// AccountHandler registers routes for managing the user's account
+func AccountHandler(router *mux.Router, eraser *services.AccountEraser) {
+	router.HandleFunc("/api/account", func(w http.ResponseWriter, r *http.Request) {
+		deleteAccount(w, r, eraser)
+	}).Methods(http.MethodDelete, http.MethodOptions)
+}
+
+// deleteAccount erases all of the user's data. Repeating the request after a
+// failure resumes the erasure; repeating it after success is a no-op.
+func deleteAccount(w http.ResponseWriter, r *http.Request, eraser *services.AccountEraser) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	erasure, err := eraser.Erase(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, erasure)
+}
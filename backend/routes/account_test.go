@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestAccount_DeleteErasesEverything(t *testing.T) {
+	api := newTestAPI(t)
+	household, shared := api.sharedHousehold("sitter", types.RoleEditor)
+	sitterPlant := api.createPlant("sitter", "Aloe")
+	api.do(http.MethodPut, "/api/plants/"+sitterPlant.ID+"/household", "sitter",
+		map[string]any{"householdId": household.ID}, nil)
+
+	api.objects.Put("users/owner/a.jpg", 1024, "image/jpeg")
+	api.do(http.MethodPatch, "/api/plants/"+shared.ID, "owner",
+		map[string]any{"photoIds": []string{"users/owner/a.jpg"}}, nil)
+	api.do(http.MethodPost, "/api/notifications/tokens", "owner",
+		map[string]any{"token": "fcm-1", "deviceId": "phone", "deviceType": "android"}, nil)
+	token := api.createAPIToken("owner", types.ScopePlantsRead)
+
+	var erasure types.AccountErasure
+	if status := api.do(http.MethodDelete, "/api/account", "owner", nil, &erasure); status != http.StatusOK {
+		t.Fatalf("delete account: got %d", status)
+	}
+	if erasure.CompletedAt == nil || erasure.Attempts != 1 || erasure.UserHash == "" {
+		t.Errorf("erasure record: got %+v", erasure)
+	}
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "owner", nil, &plants)
+	if len(plants) != 0 {
+		t.Errorf("plants left: %+v", plants)
+	}
+	if api.objects.Has("users/owner/a.jpg") {
+		t.Error("photo still stored")
+	}
+	if _, err := api.store.GetNotificationConfig(context.Background(), "owner"); err == nil {
+		t.Error("notification config with device tokens still stored")
+	}
+	if status := api.do(http.MethodGet, "/api/plants", token.Token, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("api token after erasure: got %d, want 401", status)
+	}
+
+	// The owner's household is gone and the other member's plant is unshared
+	var households []types.Household
+	api.do(http.MethodGet, "/api/households", "sitter", nil, &households)
+	if len(households) != 0 {
+		t.Errorf("sitter households: got %+v", households)
+	}
+	var sitterPlants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "sitter", nil, &sitterPlants)
+	if len(sitterPlants) != 1 || sitterPlants[0].HouseholdID != "" {
+		t.Errorf("sitter plants: got %+v", sitterPlants)
+	}
+
+	// Repeating the request is harmless and keeps the audit record
+	if status := api.do(http.MethodDelete, "/api/account", "owner", nil, &erasure); status != http.StatusOK {
+		t.Fatalf("repeat delete: got %d", status)
+	}
+	if erasure.Attempts != 2 {
+		t.Errorf("repeat erasure: got %+v", erasure)
+	}
+}
@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/gorilla/mux"
+)
+
+// AnalyticsHandler registers routes for plant growth and health analytics. It
+// registers static /api/plants paths, so it must come before PlantHandler.
+func AnalyticsHandler(
+	router *mux.Router,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+) {
+	router.HandleFunc("/api/plants/analytics", func(w http.ResponseWriter, r *http.Request) {
+		getAnalyticsSummary(w, r, careEvents, access)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/analytics", func(w http.ResponseWriter, r *http.Request) {
+		getPlantAnalytics(w, r, careEvents, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+// getAnalyticsSummary sums up the analytics of the accessible plants and
+// lists the declining ones
+func getAnalyticsSummary(
+	w http.ResponseWriter,
+	r *http.Request,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plants, err := access.ListPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	eventsByOwner := make(map[string][]types.CareEvent)
+	analytics := make([]types.PlantAnalytics, 0, len(plants))
+	for _, plant := range plants {
+		events, ok := eventsByOwner[plant.UserID]
+		if !ok {
+			events, err = allCareEvents(r.Context(), careEvents, plant.UserID)
+			if err != nil {
+				util.ServerError(w, r, err)
+				return
+			}
+			eventsByOwner[plant.UserID] = events
+		}
+		analytics = append(analytics, services.AnalyzePlant(plant, events))
+	}
+	util.RespondJSON(w, http.StatusOK, services.SummarizeAnalytics(plants, analytics))
+}
+
+func getPlantAnalytics(
+	w http.ResponseWriter,
+	r *http.Request,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return
+	}
+	events, err := allCareEvents(r.Context(), careEvents, plant.UserID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, services.AnalyzePlant(*plant, events))
+}
+
+func allCareEvents(
+	ctx context.Context,
+	careEvents services.CareEventStore,
+	ownerID string,
+) ([]types.CareEvent, error) {
+	return careEvents.GetCareEvents(ctx, ownerID, time.Time{}, time.Now().Add(time.Second))
+}
@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestAnalytics_PlantSeriesScoreAndDecliningSummary(t *testing.T) {
+	api := newTestAPI(t)
+	now := time.Now()
+	var plant types.Plant
+	status := api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name": "Calathea",
+		"watering": map[string]any{
+			"intervalDays": 7,
+			"method":       types.MethodTopWatering,
+			"waterType":    types.WaterTap,
+		},
+		"growthHistory": []map[string]any{
+			{"id": "g3", "date": now.AddDate(0, 0, -2), "heightCm": 33, "leafCount": 12, "health": "Fair"},
+			{"id": "g1", "date": now.AddDate(0, 0, -60), "heightCm": 30, "leafCount": 10, "health": "Good"},
+			{"id": "g2", "date": now.AddDate(0, 0, -32), "heightCm": 34, "leafCount": 12, "health": "Good"},
+		},
+	}, &plant)
+	if status != http.StatusCreated {
+		t.Fatalf("create plant: got %d", status)
+	}
+	// Every 10 days with a 7 day interval is late every time
+	api.logWaterings(plant, 6, 10)
+	api.createPlant("user1", "New")
+
+	var analytics types.PlantAnalytics
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID+"/analytics", "user1", nil, &analytics); status != http.StatusOK {
+		t.Fatalf("analytics: got %d", status)
+	}
+	if len(analytics.Growth) != 3 || analytics.Growth[0].HeightCmPerWeek != nil || *analytics.Growth[1].HeightCmPerWeek != 1 {
+		t.Errorf("growth: got %+v", analytics.Growth)
+	}
+	if len(analytics.Health) != 3 || analytics.Health[2].Health != types.HealthFair {
+		t.Errorf("health timeline: got %+v", analytics.Health)
+	}
+	if analytics.Care.Waterings != 5 || analytics.Care.Late != 5 || *analytics.Care.OnTimeRate != 0 ||
+		analytics.Care.AverageDelayDays != 3 {
+		t.Errorf("care: got %+v", analytics.Care)
+	}
+	// health 45*0.4 + trend 20*0.2 + growth 20*0.15 + care 0*0.25
+	if analytics.HealthScore == nil || *analytics.HealthScore != 25 || len(analytics.Components) != 4 {
+		t.Errorf("score: got %v %+v", analytics.HealthScore, analytics.Components)
+	}
+	if !analytics.Declining || len(analytics.DecliningReasons) != 2 {
+		t.Errorf("declining: got %+v", analytics.DecliningReasons)
+	}
+
+	var summary types.AnalyticsSummary
+	if status := api.do(http.MethodGet, "/api/plants/analytics", "user1", nil, &summary); status != http.StatusOK {
+		t.Fatalf("summary: got %d", status)
+	}
+	if summary.Plants != 2 || summary.Scored != 1 || *summary.AverageHealthScore != 25 {
+		t.Errorf("summary: got %+v", summary)
+	}
+	if len(summary.Declining) != 1 || summary.Declining[0].PlantID != plant.ID || summary.Declining[0].Slug != plant.Slug {
+		t.Errorf("declining plants: got %+v", summary.Declining)
+	}
+
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID+"/analytics", "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("other user: got %d, want 404", status)
+	}
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// APITokenHandler registers routes to manage personal API tokens
+func APITokenHandler(router *mux.Router, database services.APITokenStore) {
+	tokens := services.NewAPITokens(database)
+
+	router.HandleFunc("/api/tokens", func(w http.ResponseWriter, r *http.Request) {
+		getAPITokens(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/tokens", func(w http.ResponseWriter, r *http.Request) {
+		createAPIToken(w, r, database, tokens)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/tokens/{id}", func(w http.ResponseWriter, r *http.Request) {
+		revokeAPIToken(w, r, database, mux.Vars(r)["id"])
+	}).Methods(http.MethodDelete, http.MethodOptions)
+}
+
+func getAPITokens(w http.ResponseWriter, r *http.Request, db services.APITokenStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := db.GetAPITokens(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, tokens)
+}
+
+func createAPIToken(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.APITokenStore,
+	tokens *services.APITokens,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.CreateAPITokenRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errors := validation.ValidateCreateAPITokenRequest(req, time.Now()); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+
+	existing, err := db.GetAPITokens(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if len(existing) >= validation.MaxAPITokensPerUser {
+		util.BadRequest(w, "API token limit exceeded", map[string]interface{}{
+			"limit":   validation.MaxAPITokensPerUser,
+			"current": len(existing),
+		})
+		return
+	}
+
+	created, err := tokens.Create(r.Context(), userID, req)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusCreated, created)
+}
+
+func revokeAPIToken(w http.ResponseWriter, r *http.Request, db services.APITokenStore, id string) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	deleted, err := db.DeleteAPIToken(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !deleted {
+		util.NotFound(w)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func (api *testAPI) createAPIToken(userID string, scopes ...types.TokenScope) types.CreateAPITokenResponse {
+	api.t.Helper()
+
+	var created types.CreateAPITokenResponse
+	body := map[string]any{"name": "home assistant", "scopes": scopes}
+	if status := api.do(http.MethodPost, "/api/tokens", userID, body, &created); status != http.StatusCreated {
+		api.t.Fatalf("create api token: got status %d", status)
+	}
+	return created
+}
+
+func TestAPITokens_ScopesAreEnforced(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createPlant("user1", "Ficus")
+	readOnly := api.createAPIToken("user1", types.ScopePlantsRead)
+
+	var plants []types.Plant
+	if status := api.do(http.MethodGet, "/api/plants", readOnly.Token, nil, &plants); status != http.StatusOK {
+		t.Fatalf("list plants: got %d", status)
+	}
+	if len(plants) != 1 || plants[0].ID != plant.ID {
+		t.Errorf("list plants: got %d plants, want user1's", len(plants))
+	}
+
+	water := map[string]any{"plantIds": []string{plant.ID}}
+	checks := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodPost, "/api/plants/water", water},
+		{http.MethodGet, "/api/notifications", nil},
+		{http.MethodDelete, "/api/plants/" + plant.ID, nil},
+		// Tokens can never manage tokens
+		{http.MethodPost, "/api/tokens", map[string]any{"name": "x", "scopes": []string{"plants:read"}}},
+	}
+	for _, check := range checks {
+		if status := api.do(check.method, check.path, readOnly.Token, check.body, nil); status != http.StatusForbidden {
+			t.Errorf("%s %s with read-only token: got %d, want 403", check.method, check.path, status)
+		}
+	}
+
+	care := api.createAPIToken("user1", types.ScopePlantsCare)
+	if status := api.do(http.MethodPost, "/api/plants/water", care.Token, water, nil); status != http.StatusOK {
+		t.Errorf("water with care token: got %d", status)
+	}
+}
+
+func TestAPITokens_ListAndRevoke(t *testing.T) {
+	api := newTestAPI(t)
+	created := api.createAPIToken("user1", types.ScopePlantsRead)
+
+	var tokens []types.APIToken
+	api.do(http.MethodGet, "/api/tokens", "user1", nil, &tokens)
+	if len(tokens) != 1 || tokens[0].Prefix == "" || tokens[0].Hash != "" {
+		t.Fatalf("list: got %+v", tokens)
+	}
+	api.do(http.MethodGet, "/api/tokens", "user2", nil, &tokens)
+	if len(tokens) != 0 {
+		t.Errorf("user2 sees %d of user1's tokens", len(tokens))
+	}
+
+	if status := api.do(http.MethodDelete, "/api/tokens/"+created.ID, "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("revoke as user2: got %d, want 404", status)
+	}
+	if status := api.do(http.MethodDelete, "/api/tokens/"+created.ID, "user1", nil, nil); status != http.StatusOK {
+		t.Fatalf("revoke: got %d", status)
+	}
+	if status := api.do(http.MethodGet, "/api/plants", created.Token, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("use revoked token: got %d, want 401", status)
+	}
+}
+
+func TestAPITokens_Expiry(t *testing.T) {
+	api := newTestAPI(t)
+
+	past := map[string]any{"name": "old", "scopes": []string{"plants:read"}, "expiresAt": time.Now().Add(-time.Hour)}
+	if status := api.do(http.MethodPost, "/api/tokens", "user1", past, nil); status != http.StatusBadRequest {
+		t.Errorf("create expired token: got %d, want 400", status)
+	}
+
+	created := api.createAPIToken("user1", types.ScopePlantsRead)
+	stored, _ := api.store.GetAPITokenByHash(t.Context(), services.HashAPIToken(created.Token))
+	expired := time.Now().Add(-time.Minute)
+	stored.ExpiresAt = &expired
+	api.store.DeleteAPIToken(t.Context(), "user1", stored.ID)
+	api.store.CreateAPIToken(t.Context(), *stored)
+
+	if status := api.do(http.MethodGet, "/api/plants", created.Token, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("use expired token: got %d, want 401", status)
+	}
+}
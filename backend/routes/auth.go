@@ -0,0 +1,145 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/qreepex/water-me-app/backend/metrics"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// AuthHandler registers the endpoints of the local email/password auth mode
+func AuthHandler(router *mux.Router, auth *services.LocalAuth) {
+	// Login attempts are limited per email and IP
+	loginLimiter := services.NewRateLimiter()
+
+	router.HandleFunc("/api/auth/signup", func(w http.ResponseWriter, r *http.Request) {
+		signup(w, r, auth)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		login(w, r, auth, loginLimiter)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshToken(w, r, auth)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/auth/password", func(w http.ResponseWriter, r *http.Request) {
+		changePassword(w, r, auth)
+	}).Methods(http.MethodPost, http.MethodOptions)
+}
+
+func signup(w http.ResponseWriter, r *http.Request, auth *services.LocalAuth) {
+	var req types.SignupRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errs := validation.ValidateSignupRequest(req); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	response, err := auth.Signup(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "Email already registered"})
+			return
+		}
+		util.ServerError(w, r, err)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusCreated, response)
+}
+
+func login(
+	w http.ResponseWriter,
+	r *http.Request,
+	auth *services.LocalAuth,
+	limiter *services.RateLimiter,
+) {
+	var req types.LoginRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+
+	if limiter.IsRateLimited(req.Email, getRealIP(r)) {
+		metrics.RateLimitRejections.Inc()
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+
+	response, err := auth.Login(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+			return
+		}
+		util.ServerError(w, r, err)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, response)
+}
+
+func refreshToken(w http.ResponseWriter, r *http.Request, auth *services.LocalAuth) {
+	var req types.RefreshRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if req.RefreshToken == "" {
+		util.BadRequest(w, "refreshToken is required", nil)
+		return
+	}
+
+	response, err := auth.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+			return
+		}
+		util.ServerError(w, r, err)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, response)
+}
+
+func changePassword(w http.ResponseWriter, r *http.Request, auth *services.LocalAuth) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.ChangePasswordRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errs := validation.ValidateChangePasswordRequest(req); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	if err := auth.ChangePassword(r.Context(), userID, req); err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			util.BadRequest(w, "Current password is incorrect", nil)
+			return
+		}
+		util.ServerError(w, r, err)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, map[string]string{"message": "Password changed"})
+}
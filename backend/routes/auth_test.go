@@ -0,0 +1,124 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/middlewares"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/services/memstore"
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"github.com/gorilla/mux"
+)
+
+// newLocalAuthAPI wires the router as cmd/api does in AUTH_MODE=local
+func newLocalAuthAPI(t *testing.T) http.Handler {
+	t.Helper()
+
+	store := memstore.New()
+	auth := services.NewLocalAuth(store, []byte("test-secret"))
+
+	r := mux.NewRouter()
+	RegisterRoutes(r, store, memstore.NewObjectStore())
+	AuthHandler(r, auth)
+	r.Use(middlewares.AuthMiddleware(auth, services.NewAPITokens(store)))
+	return r
+}
+
+func sendAuth(t *testing.T, h http.Handler, method, path, token string, body any, out any) int {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if out != nil && rec.Code < 300 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode response %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestLocalAuth_SignupLoginAndAccess(t *testing.T) {
+	h := newLocalAuthAPI(t)
+	creds := map[string]string{"email": "Ada@Example.com", "password": "correct horse"}
+
+	var signup types.LoginResponse
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/signup", "", creds, &signup); status != http.StatusCreated {
+		t.Fatalf("signup: got %d", status)
+	}
+	if signup.User.Email != "ada@example.com" || signup.Token == "" {
+		t.Errorf("signup: got %+v", signup.User)
+	}
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/signup", "", creds, nil); status != http.StatusConflict {
+		t.Errorf("duplicate signup: got %d, want 409", status)
+	}
+
+	var login types.LoginResponse
+	creds["email"] = "ada@example.com"
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/login", "", creds, &login); status != http.StatusOK {
+		t.Fatalf("login: got %d", status)
+	}
+	wrong := map[string]string{"email": "ada@example.com", "password": "wrong password"}
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/login", "", wrong, nil); status != http.StatusUnauthorized {
+		t.Errorf("login with wrong password: got %d, want 401", status)
+	}
+
+	var plants []types.Plant
+	if status := sendAuth(t, h, http.MethodGet, "/api/plants", login.Token, nil, &plants); status != http.StatusOK {
+		t.Errorf("list plants with access token: got %d", status)
+	}
+	if status := sendAuth(t, h, http.MethodGet, "/api/plants", login.RefreshToken, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("list plants with refresh token: got %d, want 401", status)
+	}
+}
+
+func TestLocalAuth_ChangePasswordRevokesRefreshTokens(t *testing.T) {
+	h := newLocalAuthAPI(t)
+
+	var session types.LoginResponse
+	creds := map[string]string{"email": "ada@example.com", "password": "correct horse"}
+	sendAuth(t, h, http.MethodPost, "/api/auth/signup", "", creds, &session)
+
+	refresh := map[string]string{"refreshToken": session.RefreshToken}
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/refresh", "", refresh, nil); status != http.StatusOK {
+		t.Fatalf("refresh: got %d", status)
+	}
+
+	change := map[string]string{"currentPassword": "wrong password", "newPassword": "battery staple"}
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/password", session.Token, change, nil); status != http.StatusBadRequest {
+		t.Errorf("change with wrong current password: got %d, want 400", status)
+	}
+
+	// Refresh tokens are compared with second precision
+	time.Sleep(1100 * time.Millisecond)
+	change["currentPassword"] = "correct horse"
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/password", session.Token, change, nil); status != http.StatusOK {
+		t.Fatalf("change password: got %d", status)
+	}
+
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/refresh", "", refresh, nil); status != http.StatusUnauthorized {
+		t.Errorf("refresh after password change: got %d, want 401", status)
+	}
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/login", "", creds, nil); status != http.StatusUnauthorized {
+		t.Errorf("login with old password: got %d, want 401", status)
+	}
+	creds["password"] = "battery staple"
+	if status := sendAuth(t, h, http.MethodPost, "/api/auth/login", "", creds, nil); status != http.StatusOK {
+		t.Errorf("login with new password: got %d", status)
+	}
+}
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/gorilla/mux"
+)
+
+// ExportHandler registers routes for exporting all of the user's data
+func ExportHandler(
+	router *mux.Router,
+	database services.ExportStore,
+	exporter *services.Exporter,
+	objects services.ObjectStore,
+) {
+	router.HandleFunc("/api/exports", func(w http.ResponseWriter, r *http.Request) {
+		getExports(w, r, database, objects)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/exports", func(w http.ResponseWriter, r *http.Request) {
+		startExport(w, r, exporter)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/exports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		getExport(w, r, database, objects, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+func getExports(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.ExportStore,
+	objects services.ObjectStore,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	exports, err := db.GetExports(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for i := range exports {
+		prepareExportResponse(r, objects, &exports[i])
+	}
+	util.RespondJSON(w, http.StatusOK, exports)
+}
+
+// startExport responds with 202 right away; clients poll GET /api/exports/{id}
+func startExport(w http.ResponseWriter, r *http.Request, exporter *services.Exporter) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := exporter.Start(r.Context(), userID)
+	switch {
+	case errors.Is(err, services.ErrExportInProgress):
+		util.RespondJSON(w, http.StatusConflict, map[string]string{
+			"error": "An export is already in progress",
+		})
+	case errors.Is(err, services.ErrExportLimit):
+		util.RespondJSON(w, http.StatusTooManyRequests, map[string]string{
+			"error": "Export limit reached, try again tomorrow",
+		})
+	case err != nil:
+		util.ServerError(w, r, err)
+	default:
+		util.RespondJSON(w, http.StatusAccepted, export)
+	}
+}
+
+func getExport(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.ExportStore,
+	objects services.ObjectStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := db.GetExport(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if export == nil {
+		util.NotFound(w)
+		return
+	}
+	prepareExportResponse(r, objects, export)
+	util.RespondJSON(w, http.StatusOK, export)
+}
+
+// prepareExportResponse reports interrupted exports as failed and attaches a
+// fresh download URL to completed ones
+func prepareExportResponse(r *http.Request, objects services.ObjectStore, export *types.DataExport) {
+	now := time.Now()
+	services.MarkStale(export, now)
+	if export.Status != types.ExportCompleted || export.Key == "" ||
+		(export.ExpiresAt != nil && !now.Before(*export.ExpiresAt)) {
+		return
+	}
+	url, err := objects.PresignGetURL(r.Context(), export.Key)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to presign export download", "exportId", export.ID, "error", err)
+		return
+	}
+	export.DownloadURL = url
+}
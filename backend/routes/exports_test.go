@@ -0,0 +1,116 @@
+package routes
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// waitForExport polls the export until it is no longer being built
+func (api *testAPI) waitForExport(userID, id string) types.DataExport {
+	api.t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var export types.DataExport
+		if status := api.do(http.MethodGet, "/api/exports/"+id, userID, nil, &export); status != http.StatusOK {
+			api.t.Fatalf("export status: got %d", status)
+		}
+		if !export.Active() {
+			return export
+		}
+		if time.Now().After(deadline) {
+			api.t.Fatalf("export still %s", export.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExports_ArchiveContainsAllData(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createPlant("user1", "Calathea")
+	api.objects.Put("users/user1/p1_leaf.jpg", 512, "image/jpeg")
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1",
+		map[string]any{"photoIds": []string{"users/user1/p1_leaf.jpg"}}, nil)
+	api.do(http.MethodPost, "/api/plants/water", "user1", map[string]any{"plantIds": []string{plant.ID}}, nil)
+	api.do(http.MethodPost, "/api/notifications/tokens", "user1",
+		map[string]any{"token": "fcm-secret", "deviceId": "phone", "deviceType": "android"}, nil)
+
+	var export types.DataExport
+	if status := api.do(http.MethodPost, "/api/exports", "user1", nil, &export); status != http.StatusAccepted {
+		t.Fatalf("start export: got %d", status)
+	}
+	export = api.waitForExport("user1", export.ID)
+	if export.Status != types.ExportCompleted || export.DownloadURL == "" || export.ExpiresAt == nil {
+		t.Fatalf("export: got %+v", export)
+	}
+	if export.PhotosTotal != 1 || export.PhotosDone != 1 {
+		t.Errorf("photo progress: got %d/%d", export.PhotosDone, export.PhotosTotal)
+	}
+	if status := api.do(http.MethodGet, "/api/exports/"+export.ID, "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("foreign export: got %d, want 404", status)
+	}
+
+	body, err := api.objects.GetObject(context.Background(), "users/user1/exports/"+export.ID+".zip")
+	if err != nil {
+		t.Fatalf("archive not stored: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+
+	entries := make(map[string]string)
+	for _, file := range archive.File {
+		rc, _ := file.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[file.Name] = string(content)
+	}
+	for _, name := range []string{
+		"plants/" + plant.Slug + ".json",
+		"notifications.json",
+		"care-events.json",
+		"treatment-plans.json",
+		"photos/" + plant.Slug + "/p1_leaf.jpg",
+	} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("archive lacks %s; has %d entries", name, len(entries))
+		}
+	}
+	if strings.Contains(entries["notifications.json"], "fcm-secret") {
+		t.Error("archive contains a raw device token")
+	}
+	if !strings.Contains(entries["care-events.json"], `"watered"`) {
+		t.Errorf("care events: got %s", entries["care-events.json"])
+	}
+}
+
+func TestExports_DailyLimit(t *testing.T) {
+	api := newTestAPI(t)
+
+	for i := 0; i < 3; i++ {
+		var export types.DataExport
+		if status := api.do(http.MethodPost, "/api/exports", "user1", nil, &export); status != http.StatusAccepted {
+			t.Fatalf("export %d: got %d", i, status)
+		}
+		api.waitForExport("user1", export.ID)
+	}
+	if status := api.do(http.MethodPost, "/api/exports", "user1", nil, nil); status != http.StatusTooManyRequests {
+		t.Errorf("fourth export: got %d, want 429", status)
+	}
+
+	var exports []types.DataExport
+	api.do(http.MethodGet, "/api/exports", "user1", nil, &exports)
+	if len(exports) != 3 {
+		t.Errorf("exports: got %d, want 3", len(exports))
+	}
+}
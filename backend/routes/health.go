@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/gorilla/mux"
+)
+
+// healthCheckTimeout bounds each dependency probe so /readyz answers before kubelet gives up
+const healthCheckTimeout = 3 * time.Second
+
+// CheckResult is the outcome of a single dependency probe
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthHandler registers the unauthenticated /livez and /readyz probes.
+// heartbeat is optional and only set by background workers.
+func HealthHandler(
+	router *mux.Router,
+	checks []services.HealthCheck,
+	heartbeat *services.Heartbeat,
+) {
+	router.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		getLiveness(w, heartbeat)
+	}).Methods(http.MethodGet, http.MethodHead)
+
+	router.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		getReadiness(w, r, checks)
+	}).Methods(http.MethodGet, http.MethodHead)
+}
+
+// NewProbeServer builds the small HTTP listener that background workers use
+// to expose their health probes and Prometheus metrics
+func NewProbeServer(
+	addr string,
+	checks []services.HealthCheck,
+	heartbeat *services.Heartbeat,
+) *http.Server {
+	router := mux.NewRouter()
+	HealthHandler(router, checks, heartbeat)
+	MetricsHandler(router)
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+	}
+}
+
+func getLiveness(w http.ResponseWriter, heartbeat *services.Heartbeat) {
+	if heartbeat == nil {
+		util.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return
+	}
+
+	lastSuccess, alive := heartbeat.Status()
+	response := map[string]any{"status": "ok"}
+	if !lastSuccess.IsZero() {
+		response["lastSuccessfulTick"] = lastSuccess
+	}
+
+	if !alive {
+		response["status"] = "stale"
+		util.RespondJSON(w, http.StatusServiceUnavailable, response)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, response)
+}
+
+func getReadiness(w http.ResponseWriter, r *http.Request, checks []services.HealthCheck) {
+	results := make(map[string]CheckResult, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	// Probe all dependencies in parallel
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check services.HealthCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+			defer cancel()
+
+			result := CheckResult{Status: "ok"}
+			if err := check.Check(ctx); err != nil {
+				result = CheckResult{Status: "failed", Error: err.Error()}
+			}
+
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	overall := "ok"
+	for _, result := range results {
+		if result.Status != "ok" {
+			status = http.StatusServiceUnavailable
+			overall = "unavailable"
+			break
+		}
+	}
+
+	util.RespondJSON(w, status, map[string]any{"status": overall, "checks": results})
+}
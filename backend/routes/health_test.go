@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+
+	"github.com/gorilla/mux"
+)
+
+func TestReadinessReportsFailingDependency(t *testing.T) {
+	router := mux.NewRouter()
+	HealthHandler(router, []services.HealthCheck{
+		{Name: "mongodb", Check: func(ctx context.Context) error { return nil }},
+		{Name: "s3", Check: func(ctx context.Context) error { return errors.New("bucket missing") }},
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+
+	var body struct {
+		Checks map[string]CheckResult `json:"checks"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Checks["mongodb"].Status != "ok" || body.Checks["s3"].Status != "failed" {
+		t.Errorf("unexpected check results: %+v", body.Checks)
+	}
+}
+
+func TestLivenessDetectsStaleWorker(t *testing.T) {
+	heartbeat := services.NewHeartbeat(10 * time.Millisecond)
+	router := mux.NewRouter()
+	HealthHandler(router, nil, heartbeat)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 during startup grace period, got %d", rec.Code)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for stale worker, got %d", rec.Code)
+	}
+
+	heartbeat.Beat()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after a successful tick, got %d", rec.Code)
+	}
+}
+
+func TestMetricsOnlyOnProbeServer(t *testing.T) {
+	probes := NewProbeServer(":0", nil, nil)
+	rec := httptest.NewRecorder()
+	probes.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("probe server: expected 200, got %d", rec.Code)
+	}
+
+	api := newTestAPI(t)
+	if status := api.do(http.MethodGet, "/metrics", "", nil, nil); status == http.StatusOK {
+		t.Errorf("public router served /metrics without auth")
+	}
+}
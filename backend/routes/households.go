@@ -0,0 +1,589 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// defaultInviteExpiry applies when an invite request sets no expiry
+const defaultInviteExpiry = 7 * 24 * time.Hour
+
+// HouseholdHandler registers routes to manage households, their members and invites
+func HouseholdHandler(
+	router *mux.Router,
+	database services.HouseholdStore,
+	plants services.PlantStore,
+	rooms services.RoomStore,
+) {
+	router.HandleFunc("/api/households", func(w http.ResponseWriter, r *http.Request) {
+		getHouseholds(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/households", func(w http.ResponseWriter, r *http.Request) {
+		createHousehold(w, r, database)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/households/join", func(w http.ResponseWriter, r *http.Request) {
+		joinHousehold(w, r, database)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/households/{id}", func(w http.ResponseWriter, r *http.Request) {
+		getHousehold(w, r, database, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/households/{id}", func(w http.ResponseWriter, r *http.Request) {
+		deleteHousehold(w, r, database, plants, mux.Vars(r)["id"])
+	}).Methods(http.MethodDelete, http.MethodOptions)
+
+	router.HandleFunc(
+		"/api/households/{id}/safety-profile",
+		func(w http.ResponseWriter, r *http.Request) {
+			putHouseholdSafetyProfile(w, r, database, rooms, mux.Vars(r)["id"])
+		},
+	).Methods(http.MethodPut, http.MethodOptions)
+
+	router.HandleFunc("/api/households/{id}/invites", func(w http.ResponseWriter, r *http.Request) {
+		getHouseholdInvites(w, r, database, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/households/{id}/invites", func(w http.ResponseWriter, r *http.Request) {
+		createHouseholdInvite(w, r, database, mux.Vars(r)["id"])
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc(
+		"/api/households/{id}/invites/{inviteId}",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			deleteHouseholdInvite(w, r, database, vars["id"], vars["inviteId"])
+		},
+	).Methods(http.MethodDelete, http.MethodOptions)
+
+	router.HandleFunc(
+		"/api/households/{id}/members/{userId}",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			updateHouseholdMember(w, r, database, vars["id"], vars["userId"])
+		},
+	).Methods(http.MethodPatch, http.MethodOptions)
+
+	router.HandleFunc(
+		"/api/households/{id}/members/{userId}",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			removeHouseholdMember(w, r, database, plants, vars["id"], vars["userId"])
+		},
+	).Methods(http.MethodDelete, http.MethodOptions)
+}
+
+func getHouseholds(w http.ResponseWriter, r *http.Request, db services.HouseholdStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	households, err := db.GetHouseholdsForUser(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, households)
+}
+
+func createHousehold(w http.ResponseWriter, r *http.Request, db services.HouseholdStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.CreateHouseholdRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errors := validation.ValidateCreateHouseholdRequest(req); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+	if !checkHouseholdLimit(w, r, db, userID) {
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	now := time.Now()
+	household, err := db.CreateHousehold(r.Context(), types.Household{
+		ID:   id,
+		Name: strings.TrimSpace(req.Name),
+		Members: []types.HouseholdMember{{
+			UserID:      userID,
+			DisplayName: req.DisplayName,
+			Role:        types.RoleOwner,
+			JoinedAt:    now,
+		}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusCreated, household)
+}
+
+func getHousehold(w http.ResponseWriter, r *http.Request, db services.HouseholdStore, id string) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	household, _, ok := loadHousehold(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, household)
+}
+
+func deleteHousehold(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	plants services.PlantStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, ok := loadOwnedHousehold(w, r, db, userID, id); !ok {
+		return
+	}
+	// Plants go back to being private to their owners
+	if _, err := plants.ClearPlantHousehold(r.Context(), id, ""); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if _, err := db.DeleteHousehold(r.Context(), id); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// putHouseholdSafetyProfile sets the pets and children living with the
+// household's plants. Members who may edit plants may change it, and
+// residents may reach the rooms of any member.
+func putHouseholdSafetyProfile(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	rooms services.RoomStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	household, member, ok := loadHousehold(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	if !member.Role.CanEditPlants() {
+		util.Forbidden(w)
+		return
+	}
+	owners := make([]string, 0, len(household.Members))
+	for _, other := range household.Members {
+		owners = append(owners, other.UserID)
+	}
+	profile, ok := decodeSafetyProfile(w, r, rooms, owners)
+	if !ok {
+		return
+	}
+
+	if _, err := db.SetHouseholdProfile(r.Context(), id, profile); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	household, err := db.GetHousehold(r.Context(), id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, household)
+}
+
+func getHouseholdInvites(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, ok := loadOwnedHousehold(w, r, db, userID, id); !ok {
+		return
+	}
+	invites, err := db.GetHouseholdInvites(r.Context(), id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for i := range invites {
+		invites[i].Link = constants.InviteLinkBaseURL + invites[i].Code
+	}
+	util.RespondJSON(w, http.StatusOK, invites)
+}
+
+func createHouseholdInvite(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.CreateHouseholdInviteRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errors := validation.ValidateCreateHouseholdInviteRequest(req); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+	if _, ok := loadOwnedHousehold(w, r, db, userID, id); !ok {
+		return
+	}
+
+	inviteID, err := gonanoid.New()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	code, err := services.GenerateInviteCode()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	expiry := defaultInviteExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	now := time.Now()
+	invite, err := db.CreateHouseholdInvite(r.Context(), types.HouseholdInvite{
+		ID:          inviteID,
+		HouseholdID: id,
+		Code:        code,
+		Role:        req.Role,
+		CreatedBy:   userID,
+		ExpiresAt:   now.Add(expiry),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	invite.Link = constants.InviteLinkBaseURL + invite.Code
+	util.RespondJSON(w, http.StatusCreated, invite)
+}
+
+func deleteHouseholdInvite(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	id, inviteID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, ok := loadOwnedHousehold(w, r, db, userID, id); !ok {
+		return
+	}
+	deleted, err := db.DeleteHouseholdInvite(r.Context(), id, inviteID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !deleted {
+		util.NotFound(w)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// joinHousehold accepts an invite. Invites are single use.
+func joinHousehold(w http.ResponseWriter, r *http.Request, db services.HouseholdStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.JoinHouseholdRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errors := validation.ValidateJoinHouseholdRequest(req); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	invite, err := db.GetHouseholdInviteByCode(r.Context(), code)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if invite == nil || time.Now().After(invite.ExpiresAt) {
+		util.NotFound(w)
+		return
+	}
+
+	household, err := db.GetHousehold(r.Context(), invite.HouseholdID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if household == nil {
+		util.NotFound(w)
+		return
+	}
+	if _, member := household.Member(userID); member {
+		util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "Already a member"})
+		return
+	}
+	if len(household.Members) >= validation.MaxHouseholdMembers {
+		util.BadRequest(w, "Household member limit exceeded", map[string]interface{}{
+			"limit": validation.MaxHouseholdMembers,
+		})
+		return
+	}
+	if !checkHouseholdLimit(w, r, db, userID) {
+		return
+	}
+
+	// Deleting first lets only one of two concurrent joins use the invite
+	deleted, err := db.DeleteHouseholdInvite(r.Context(), invite.HouseholdID, invite.ID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !deleted {
+		util.NotFound(w)
+		return
+	}
+
+	added, err := db.AddHouseholdMember(r.Context(), household.ID, types.HouseholdMember{
+		UserID:      userID,
+		DisplayName: req.DisplayName,
+		Role:        invite.Role,
+		JoinedAt:    time.Now(),
+	})
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !added {
+		util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "Already a member"})
+		return
+	}
+
+	household, err = db.GetHousehold(r.Context(), household.ID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, household)
+}
+
+func updateHouseholdMember(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	id, memberID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.UpdateHouseholdMemberRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if !validation.IsAssignableRole(req.Role) {
+		util.BadRequest(w, "Validation failed", []types.ValidationError{{
+			Field:   "role",
+			Message: "Role must be editor, caretaker or viewer",
+		}})
+		return
+	}
+
+	household, ok := loadOwnedHousehold(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	member, found := household.Member(memberID)
+	if !found {
+		util.NotFound(w)
+		return
+	}
+	if member.Role == types.RoleOwner {
+		util.BadRequest(w, "The owner's role cannot be changed", nil)
+		return
+	}
+
+	if _, err := db.UpdateHouseholdMemberRole(r.Context(), id, memberID, req.Role); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	household, err := db.GetHousehold(r.Context(), id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, household)
+}
+
+// removeHouseholdMember lets the owner remove a member or a member leave. The
+// member's plants stop being shared with the household.
+func removeHouseholdMember(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	plants services.PlantStore,
+	id, memberID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	household, self, ok := loadHousehold(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	if memberID != userID && self.Role != types.RoleOwner {
+		util.Forbidden(w)
+		return
+	}
+	member, found := household.Member(memberID)
+	if !found {
+		util.NotFound(w)
+		return
+	}
+	if member.Role == types.RoleOwner {
+		util.BadRequest(w, "The owner cannot leave; delete the household instead", nil)
+		return
+	}
+
+	if _, err := db.RemoveHouseholdMember(r.Context(), id, memberID); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if _, err := plants.ClearPlantHousehold(r.Context(), id, memberID); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// loadHousehold loads a household the user is a member of, responding with 404
+// otherwise so non-members cannot probe household IDs
+func loadHousehold(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	userID, id string,
+) (*types.Household, types.HouseholdMember, bool) {
+	household, err := db.GetHousehold(r.Context(), id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return nil, types.HouseholdMember{}, false
+	}
+	if household == nil {
+		util.NotFound(w)
+		return nil, types.HouseholdMember{}, false
+	}
+	member, ok := household.Member(userID)
+	if !ok {
+		util.NotFound(w)
+		return nil, types.HouseholdMember{}, false
+	}
+	return household, member, true
+}
+
+// loadOwnedHousehold is loadHousehold restricted to the owner
+func loadOwnedHousehold(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	userID, id string,
+) (*types.Household, bool) {
+	household, member, ok := loadHousehold(w, r, db, userID, id)
+	if !ok {
+		return nil, false
+	}
+	if member.Role != types.RoleOwner {
+		util.Forbidden(w)
+		return nil, false
+	}
+	return household, true
+}
+
+func checkHouseholdLimit(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.HouseholdStore,
+	userID string,
+) bool {
+	households, err := db.GetHouseholdsForUser(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return false
+	}
+	if len(households) >= validation.MaxHouseholdsPerUser {
+		util.BadRequest(w, "Household limit exceeded", map[string]interface{}{
+			"limit":   validation.MaxHouseholdsPerUser,
+			"current": len(households),
+		})
+		return false
+	}
+	return true
+}
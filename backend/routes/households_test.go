@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// sharedHousehold creates a household owned by "owner" with a shared plant
+// and lets memberID join it with role
+func (api *testAPI) sharedHousehold(memberID string, role types.HouseholdRole) (types.Household, types.Plant) {
+	api.t.Helper()
+
+	var household types.Household
+	if status := api.do(http.MethodPost, "/api/households", "owner",
+		map[string]any{"name": "Home"}, &household); status != http.StatusCreated {
+		api.t.Fatalf("create household: got %d", status)
+	}
+
+	var plant types.Plant
+	status := api.do(http.MethodPost, "/api/plants", "owner",
+		map[string]any{"name": "Monstera", "householdId": household.ID}, &plant)
+	if status != http.StatusCreated {
+		api.t.Fatalf("create shared plant: got %d", status)
+	}
+
+	var invite types.HouseholdInvite
+	if status := api.do(http.MethodPost, "/api/households/"+household.ID+"/invites", "owner",
+		map[string]any{"role": role}, &invite); status != http.StatusCreated {
+		api.t.Fatalf("create invite: got %d", status)
+	}
+	if invite.Link == "" || len(invite.Code) != 8 {
+		api.t.Errorf("invite: got code %q link %q", invite.Code, invite.Link)
+	}
+	if status := api.do(http.MethodPost, "/api/households/join", memberID,
+		map[string]any{"code": invite.Code}, nil); status != http.StatusOK {
+		api.t.Fatalf("join: got %d", status)
+	}
+	return household, plant
+}
+
+func TestHouseholds_CaretakerCanOnlyCare(t *testing.T) {
+	api := newTestAPI(t)
+	_, plant := api.sharedHousehold("sitter", types.RoleCaretaker)
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "sitter", nil, &plants)
+	if len(plants) != 1 || plants[0].ID != plant.ID || plants[0].Role != types.RoleCaretaker {
+		t.Fatalf("caretaker plant list: got %+v", plants)
+	}
+
+	before := time.Now()
+	api.do(http.MethodPost, "/api/plants/water", "sitter", map[string]any{"plantIds": []string{plant.ID}}, nil)
+	var watered types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "owner", nil, &watered)
+	if watered.Watering == nil || watered.Watering.LastWatered.Before(before) {
+		t.Error("caretaker could not water the shared plant")
+	}
+
+	if status := api.do(http.MethodPatch, "/api/plants/"+plant.ID, "sitter",
+		map[string]any{"name": "Renamed"}, nil); status != http.StatusForbidden {
+		t.Errorf("caretaker update: got %d, want 403", status)
+	}
+	if status := api.do(http.MethodDelete, "/api/plants/"+plant.ID, "sitter", nil, nil); status != http.StatusForbidden {
+		t.Errorf("caretaker delete: got %d, want 403", status)
+	}
+}
+
+func TestHouseholds_ViewerCannotWater(t *testing.T) {
+	api := newTestAPI(t)
+	_, plant := api.sharedHousehold("grandma", types.RoleViewer)
+
+	var before types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "grandma", nil, &before)
+	api.do(http.MethodPost, "/api/plants/water", "grandma", map[string]any{"plantIds": []string{plant.ID}}, nil)
+
+	var after types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "owner", nil, &after)
+	if !after.Watering.LastWatered.Equal(*before.Watering.LastWatered) {
+		t.Error("viewer watered the shared plant")
+	}
+}
+
+func TestHouseholds_EditorCanUpdate(t *testing.T) {
+	api := newTestAPI(t)
+	_, plant := api.sharedHousehold("partner", types.RoleEditor)
+
+	var updated types.Plant
+	if status := api.do(http.MethodPatch, "/api/plants/"+plant.ID, "partner",
+		map[string]any{"name": "Monty"}, &updated); status != http.StatusOK {
+		t.Fatalf("editor update: got %d", status)
+	}
+	if updated.Name != "Monty" || updated.UserID != "owner" {
+		t.Errorf("editor update: got name %q owner %q", updated.Name, updated.UserID)
+	}
+}
+
+func TestHouseholds_MembershipIsRequired(t *testing.T) {
+	api := newTestAPI(t)
+	household, plant := api.sharedHousehold("sitter", types.RoleCaretaker)
+
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID, "stranger", nil, nil); status != http.StatusNotFound {
+		t.Errorf("stranger get plant: got %d, want 404", status)
+	}
+	if status := api.do(http.MethodGet, "/api/households/"+household.ID, "stranger", nil, nil); status != http.StatusNotFound {
+		t.Errorf("stranger get household: got %d, want 404", status)
+	}
+	if status := api.do(http.MethodPost, "/api/households/"+household.ID+"/invites", "sitter",
+		map[string]any{"role": "editor"}, nil); status != http.StatusForbidden {
+		t.Errorf("caretaker invite: got %d, want 403", status)
+	}
+	if status := api.do(http.MethodPost, "/api/households/"+household.ID+"/invites", "owner",
+		map[string]any{"role": "owner"}, nil); status != http.StatusBadRequest {
+		t.Errorf("invite as owner: got %d, want 400", status)
+	}
+
+	// Removing the member ends their access
+	if status := api.do(http.MethodDelete, "/api/households/"+household.ID+"/members/sitter", "owner", nil, nil); status != http.StatusOK {
+		t.Fatalf("remove member: got %d", status)
+	}
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID, "sitter", nil, nil); status != http.StatusNotFound {
+		t.Errorf("removed member get plant: got %d, want 404", status)
+	}
+}
+
+func TestHouseholds_InvitesAreSingleUse(t *testing.T) {
+	api := newTestAPI(t)
+
+	var household types.Household
+	api.do(http.MethodPost, "/api/households", "owner", map[string]any{"name": "Home"}, &household)
+	var invite types.HouseholdInvite
+	api.do(http.MethodPost, "/api/households/"+household.ID+"/invites", "owner",
+		map[string]any{"role": "viewer"}, &invite)
+
+	if status := api.do(http.MethodPost, "/api/households/join", "first",
+		map[string]any{"code": invite.Code}, nil); status != http.StatusOK {
+		t.Fatalf("first join: got %d", status)
+	}
+	if status := api.do(http.MethodPost, "/api/households/join", "second",
+		map[string]any{"code": invite.Code}, nil); status != http.StatusNotFound {
+		t.Errorf("second join with same code: got %d, want 404", status)
+	}
+}
+
+func TestHouseholds_DeleteUnsharesPlants(t *testing.T) {
+	api := newTestAPI(t)
+	household, plant := api.sharedHousehold("partner", types.RoleEditor)
+
+	if status := api.do(http.MethodDelete, "/api/households/"+household.ID, "partner", nil, nil); status != http.StatusForbidden {
+		t.Errorf("editor delete household: got %d, want 403", status)
+	}
+	if status := api.do(http.MethodDelete, "/api/households/"+household.ID, "owner", nil, nil); status != http.StatusOK {
+		t.Fatalf("delete household: got %d", status)
+	}
+
+	var own types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "owner", nil, &own)
+	if own.HouseholdID != "" {
+		t.Errorf("plant still shared with %q", own.HouseholdID)
+	}
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID, "partner", nil, nil); status != http.StatusNotFound {
+		t.Errorf("former member get plant: got %d, want 404", status)
+	}
+}
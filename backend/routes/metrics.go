@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler registers the Prometheus scrape endpoint
+func MetricsHandler(router *mux.Router) {
+	router.Handle("/metrics", promhttp.Handler())
+}
@@ -1,7 +1,7 @@
 package routes
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -14,13 +14,17 @@ import (
 	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
-func NotificationHandler(router *mux.Router, database *services.MongoDB) {
+func NotificationHandler(
+	router *mux.Router,
+	database services.NotificationStore,
+	plants *services.PlantAccess,
+) {
 	router.HandleFunc("/api/notifications", func(w http.ResponseWriter, r *http.Request) {
 		getNotificationConfig(w, r, database)
 	}).Methods(http.MethodGet, http.MethodOptions)
 
 	router.HandleFunc("/api/notifications", func(w http.ResponseWriter, r *http.Request) {
-		upsertNotificationConfig(w, r, database)
+		upsertNotificationConfig(w, r, database, plants)
 	}).Methods(http.MethodPut, http.MethodOptions)
 
 	router.HandleFunc("/api/notifications", func(w http.ResponseWriter, r *http.Request) {
@@ -37,15 +41,13 @@ func NotificationHandler(router *mux.Router, database *services.MongoDB) {
 		Methods(http.MethodDelete, http.MethodOptions)
 }
 
-func getNotificationConfig(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func getNotificationConfig(w http.ResponseWriter, r *http.Request, db services.NotificationStore) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	log.Printf("Getting notification config for user %v", userID)
-
 	config, err := db.GetNotificationConfig(r.Context(), userID)
 	if err != nil {
 		if err == types.ErrNoDocuments {
@@ -54,7 +56,7 @@ func getNotificationConfig(w http.ResponseWriter, r *http.Request, db *services.
 			util.RespondJSON(w, http.StatusOK, defaultConfig)
 			return
 		}
-		log.Printf("Failed to retrieve notification config: %v", err)
+		slog.ErrorContext(r.Context(), "failed to retrieve notification config", "error", err)
 		http.Error(w, "Failed to retrieve notification config", http.StatusInternalServerError)
 		return
 	}
@@ -62,7 +64,12 @@ func getNotificationConfig(w http.ResponseWriter, r *http.Request, db *services.
 	util.RespondJSON(w, http.StatusOK, config)
 }
 
-func upsertNotificationConfig(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func upsertNotificationConfig(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.NotificationStore,
+	plants *services.PlantAccess,
+) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -82,11 +89,11 @@ func upsertNotificationConfig(w http.ResponseWriter, r *http.Request, db *servic
 		return
 	}
 
-	// Verify that all muted plant IDs belong to the user
+	// Verify that all muted plant IDs belong to the user or are shared with them
 	if len(config.MutedPlantIDs) > 0 {
-		userPlants, err := db.GetPlants(r.Context(), userID)
+		userPlants, err := plants.ListPlants(r.Context(), userID)
 		if err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 
@@ -122,7 +129,7 @@ func upsertNotificationConfig(w http.ResponseWriter, r *http.Request, db *servic
 	// Check if config exists
 	existing, err := db.GetNotificationConfig(r.Context(), userID)
 	if err != nil && err != types.ErrNoDocuments {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
@@ -131,7 +138,7 @@ func upsertNotificationConfig(w http.ResponseWriter, r *http.Request, db *servic
 		config.ID = existing.ID
 		updatedConfig, err := db.UpdateNotificationConfig(r.Context(), config)
 		if err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 		util.RespondJSON(w, http.StatusOK, updatedConfig)
@@ -139,7 +146,7 @@ func upsertNotificationConfig(w http.ResponseWriter, r *http.Request, db *servic
 		// Create new config
 		id, err := gonanoid.New()
 		if err != nil {
-			log.Printf("Failed to generate notification config ID: %v", err)
+			slog.ErrorContext(r.Context(), "failed to generate notification config ID", "error", err)
 			http.Error(w, "Failed to generate ID", http.StatusInternalServerError)
 			return
 		}
@@ -147,14 +154,14 @@ func upsertNotificationConfig(w http.ResponseWriter, r *http.Request, db *servic
 
 		createdConfig, err := db.CreateNotificationConfig(r.Context(), config)
 		if err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 		util.RespondJSON(w, http.StatusCreated, createdConfig)
 	}
 }
 
-func deleteNotificationConfig(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func deleteNotificationConfig(w http.ResponseWriter, r *http.Request, db services.NotificationStore) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -163,7 +170,7 @@ func deleteNotificationConfig(w http.ResponseWriter, r *http.Request, db *servic
 
 	deleted, err := db.DeleteNotificationConfig(r.Context(), userID)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
@@ -194,7 +201,7 @@ func createDefaultConfig(userID string) types.NotificationConfig {
 	}
 }
 
-func registerDeviceToken(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func registerDeviceToken(w http.ResponseWriter, r *http.Request, db services.NotificationStore) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -220,7 +227,7 @@ func registerDeviceToken(w http.ResponseWriter, r *http.Request, db *services.Mo
 	// Get or create notification config
 	config, err := db.GetNotificationConfig(r.Context(), userID)
 	if err != nil && err != types.ErrNoDocuments {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
@@ -229,14 +236,14 @@ func registerDeviceToken(w http.ResponseWriter, r *http.Request, db *services.Mo
 		defaultConfig := createDefaultConfig(userID)
 		id, err := gonanoid.New()
 		if err != nil {
-			log.Printf("Failed to generate notification config ID: %v", err)
+			slog.ErrorContext(r.Context(), "failed to generate notification config ID", "error", err)
 			http.Error(w, "Failed to generate ID", http.StatusInternalServerError)
 			return
 		}
 		defaultConfig.ID = id
 		config, err = db.CreateNotificationConfig(r.Context(), defaultConfig)
 		if err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 	}
@@ -274,15 +281,15 @@ func registerDeviceToken(w http.ResponseWriter, r *http.Request, db *services.Mo
 	// Update config in database
 	updatedConfig, err := db.UpdateNotificationConfig(r.Context(), *config)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
 	util.RespondJSON(w, http.StatusOK, updatedConfig)
-	log.Printf("Registered device token for user %s, device %s", userID, request.DeviceID)
+	slog.InfoContext(r.Context(), "registered device token", "deviceType", request.DeviceType)
 }
 
-func deleteDeviceToken(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func deleteDeviceToken(w http.ResponseWriter, r *http.Request, db services.NotificationStore) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -304,7 +311,7 @@ func deleteDeviceToken(w http.ResponseWriter, r *http.Request, db *services.Mong
 			util.NotFound(w)
 			return
 		}
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
@@ -330,10 +337,10 @@ func deleteDeviceToken(w http.ResponseWriter, r *http.Request, db *services.Mong
 	// Update config in database
 	updatedConfig, err := db.UpdateNotificationConfig(r.Context(), *config)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
 	util.RespondJSON(w, http.StatusOK, updatedConfig)
-	log.Printf("Removed device token for user %s, device %s", userID, deviceID)
+	slog.InfoContext(r.Context(), "removed device token")
 }
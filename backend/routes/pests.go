@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/gorilla/mux"
+)
+
+// PestHandler registers routes for pest outbreaks and statistics
+func PestHandler(router *mux.Router, outbreaks services.PestOutbreakStore, access *services.PlantAccess) {
+	router.HandleFunc("/api/pests/outbreaks", func(w http.ResponseWriter, r *http.Request) {
+		getOutbreaks(w, r, outbreaks)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/pests/stats", func(w http.ResponseWriter, r *http.Request) {
+		getPestStats(w, r, outbreaks, access)
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+// getOutbreaks lists the outbreaks among the user's own plants, newest first
+func getOutbreaks(w http.ResponseWriter, r *http.Request, db services.PestOutbreakStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	outbreaks, err := db.GetOutbreaks(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, outbreaks)
+}
+
+// getPestStats counts the infections of all accessible plants by pest and
+// status, and the open outbreaks among the user's own plants
+func getPestStats(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PestOutbreakStore,
+	access *services.PlantAccess,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plants, err := access.ListPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	outbreaks, err := db.GetOutbreaks(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, services.PestStatistics(plants, outbreaks))
+}
+
+// recordPests updates the outbreaks and treatment plans after a plant's pest
+// history was saved. The plant is saved already, so failures are logged
+// rather than returned.
+func recordPests(
+	ctx context.Context,
+	pests *services.PestTracker,
+	before *types.Plant,
+	after types.Plant,
+) []types.PestOutbreak {
+	now := time.Now()
+	if before != nil {
+		if err := pests.StopTreatments(ctx, after, now); err != nil {
+			slog.ErrorContext(ctx, "failed to stop treatment plans", "plantId", after.ID, "error", err)
+		}
+	}
+	outbreaks, err := pests.Record(ctx, before, after, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record pest outbreaks", "plantId", after.ID, "error", err)
+		return nil
+	}
+	return outbreaks
+}
@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func pestHistory(id string, pest types.PestType, status types.PestStatus) []map[string]any {
+	return []map[string]any{{
+		"id":         id,
+		"pest":       pest,
+		"status":     status,
+		"detectedAt": "2026-05-01T00:00:00Z",
+		"treatment":  "Neem oil",
+	}}
+}
+
+func TestPests_ActiveInfectionStartsOutbreakInRoom(t *testing.T) {
+	api := newTestAPI(t)
+	kitchen := api.createRoom("user1", "Kitchen")
+	plants := make(map[string]types.Plant)
+	for _, name := range []string{"Basil", "Mint", "Thyme"} {
+		var plant types.Plant
+		api.do(http.MethodPost, "/api/plants", "user1",
+			map[string]any{"name": name, "location": map[string]any{"roomId": kitchen.ID}}, &plant)
+		plants[name] = plant
+	}
+	api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Fern", "location": map[string]any{"room": "Bathroom"}}, nil)
+
+	var basil types.Plant
+	status := api.do(http.MethodPatch, "/api/plants/"+plants["Basil"].ID, "user1",
+		map[string]any{"pestHistory": pestHistory("i1", types.PestAphids, types.PestStatusActive)}, &basil)
+	if status != http.StatusOK {
+		t.Fatalf("update: got %d", status)
+	}
+	if len(basil.Outbreaks) != 1 {
+		t.Fatalf("outbreaks: got %+v", basil.Outbreaks)
+	}
+	outbreak := basil.Outbreaks[0]
+	if outbreak.Room != "Kitchen" || outbreak.Status != types.OutbreakOpen || outbreak.NextInspectionAt == nil {
+		t.Errorf("outbreak: got %+v", outbreak)
+	}
+	if len(outbreak.AtRisk) != 2 || outbreak.AtRisk[0].Name != "Mint" || outbreak.AtRisk[1].Name != "Thyme" {
+		t.Errorf("at risk: got %+v, want Mint and Thyme", outbreak.AtRisk)
+	}
+	if outbreak.Quarantine == "" {
+		t.Error("expected a quarantine suggestion")
+	}
+
+	// The same pest on a neighbour spreads the outbreak instead of starting one
+	var mint types.Plant
+	api.do(http.MethodPatch, "/api/plants/"+plants["Mint"].ID, "user1",
+		map[string]any{"pestHistory": pestHistory("i2", types.PestAphids, types.PestStatusActive)}, &mint)
+	if len(mint.Outbreaks) != 1 || mint.Outbreaks[0].ID != outbreak.ID {
+		t.Fatalf("spread: got %+v", mint.Outbreaks)
+	}
+	if len(mint.Outbreaks[0].Infected) != 2 || len(mint.Outbreaks[0].AtRisk) != 1 {
+		t.Errorf("spread: got infected %+v, at risk %+v", mint.Outbreaks[0].Infected, mint.Outbreaks[0].AtRisk)
+	}
+
+	// Treating one plant keeps the outbreak open, resolving both contains it
+	api.do(http.MethodPatch, "/api/plants/"+plants["Basil"].ID, "user1",
+		map[string]any{"pestHistory": pestHistory("i1", types.PestAphids, types.PestStatusResolved)}, nil)
+	var outbreaks []types.PestOutbreak
+	api.do(http.MethodGet, "/api/pests/outbreaks", "user1", nil, &outbreaks)
+	if len(outbreaks) != 1 || outbreaks[0].Status != types.OutbreakOpen {
+		t.Fatalf("after resolving one plant: got %+v", outbreaks)
+	}
+	api.do(http.MethodPatch, "/api/plants/"+plants["Mint"].ID, "user1",
+		map[string]any{"pestHistory": pestHistory("i2", types.PestAphids, types.PestStatusTreated)}, nil)
+	var contained []types.PestOutbreak
+	api.do(http.MethodGet, "/api/pests/outbreaks", "user1", nil, &contained)
+	if len(contained) != 1 || contained[0].Status != types.OutbreakContained ||
+		contained[0].ContainedAt == nil || contained[0].NextInspectionAt != nil {
+		t.Errorf("after resolving both: got %+v", contained)
+	}
+
+	var other []types.PestOutbreak
+	api.do(http.MethodGet, "/api/pests/outbreaks", "user2", nil, &other)
+	if len(other) != 0 {
+		t.Errorf("other user's outbreaks: got %+v", other)
+	}
+}
+
+func TestPests_PlantWithoutRoomStartsNoOutbreak(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createPlant("user1", "Ficus")
+
+	var updated types.Plant
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1",
+		map[string]any{"pestHistory": pestHistory("i1", types.PestThrips, types.PestStatusActive)}, &updated)
+	if len(updated.Outbreaks) != 0 {
+		t.Errorf("outbreaks: got %+v", updated.Outbreaks)
+	}
+}
+
+func TestPests_StatsByTypeAndStatus(t *testing.T) {
+	api := newTestAPI(t)
+	office := api.createRoom("user1", "Office")
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":     "Pothos",
+		"location": map[string]any{"roomId": office.ID},
+		"pestHistory": []map[string]any{
+			{"id": "a", "pest": types.PestAphids, "status": types.PestStatusActive, "treatment": "Soap"},
+			{"id": "b", "pest": types.PestThrips, "status": types.PestStatusResolved, "treatment": "Soap"},
+		},
+	}, nil)
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":        "Monstera",
+		"pestHistory": pestHistory("c", types.PestAphids, types.PestStatusResolved),
+	}, nil)
+
+	var stats types.PestStats
+	if status := api.do(http.MethodGet, "/api/pests/stats", "user1", nil, &stats); status != http.StatusOK {
+		t.Fatalf("stats: got %d", status)
+	}
+	if stats.Infections != 3 || stats.InfestedPlants != 1 || stats.OpenOutbreaks != 1 {
+		t.Errorf("stats: got %+v", stats)
+	}
+	if stats.ByStatus[types.PestStatusResolved] != 2 || stats.ByStatus[types.PestStatusActive] != 1 {
+		t.Errorf("by status: got %+v", stats.ByStatus)
+	}
+	if len(stats.ByPest) != 2 || stats.ByPest[0].Pest != types.PestAphids || stats.ByPest[0].Total != 2 ||
+		stats.ByPest[0].ByStatus[types.PestStatusActive] != 1 {
+		t.Errorf("by pest: got %+v", stats.ByPest)
+	}
+}
+
+func TestPests_ImportedInfectionStartsOutbreak(t *testing.T) {
+	api := newTestAPI(t)
+	body := map[string]any{"plants": []map[string]any{
+		{
+			"name":        "Rose",
+			"location":    map[string]any{"room": "Balcony"},
+			"pestHistory": pestHistory("i1", types.PestSpiderMites, types.PestStatusActive),
+		},
+		{"name": "Lavender", "location": map[string]any{"room": "Balcony"}},
+	}}
+	if status := api.do(http.MethodPost, "/api/plants/import", "user1", body, nil); status != http.StatusCreated {
+		t.Fatalf("import: got %d", status)
+	}
+
+	var outbreaks []types.PestOutbreak
+	api.do(http.MethodGet, "/api/pests/outbreaks", "user1", nil, &outbreaks)
+	if len(outbreaks) != 1 || outbreaks[0].Pest != types.PestSpiderMites || outbreaks[0].NextInspectionAt == nil {
+		t.Fatalf("outbreaks: got %+v", outbreaks)
+	}
+	if len(outbreaks[0].AtRisk) != 1 || outbreaks[0].AtRisk[0].Name != "Lavender" {
+		t.Errorf("at risk: got %+v", outbreaks[0].AtRisk)
+	}
+}
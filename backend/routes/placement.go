@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+)
+
+// getPlacements scores the rooms of all the user's plants, worst first
+func getPlacements(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	plants services.PlantStore,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rooms, err := db.GetRooms(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	all, err := plants.GetPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	reports := make([]types.PlacementReport, 0, len(all))
+	for _, plant := range all {
+		reports = append(reports, services.Placement(plant, rooms))
+	}
+	services.SortPlacements(reports)
+	util.RespondJSON(w, http.StatusOK, reports)
+}
+
+// getPlantPlacement scores the room of a plant. Suggestions come from the
+// rooms of the plant's owner, also for shared plants.
+func getPlantPlacement(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return
+	}
+	rooms, err := db.GetRooms(r.Context(), plant.UserID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, services.Placement(*plant, rooms))
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestPlacement_FlagsMismatchAndSuggestsBetterRoom(t *testing.T) {
+	api := newTestAPI(t)
+	var hallway, conservatory types.Room
+	api.do(http.MethodPost, "/api/rooms", "user1", map[string]any{
+		"name": "North hallway", "light": "Full Shade", "temperatureCelsius": 14, "humidityPct": 35,
+	}, &hallway)
+	api.do(http.MethodPost, "/api/rooms", "user1", map[string]any{
+		"name": "Conservatory", "light": "Full Sun", "temperatureCelsius": 24, "humidityPct": 50,
+	}, &conservatory)
+	api.do(http.MethodPost, "/api/rooms", "user1", map[string]any{"name": "Attic"}, nil)
+
+	var cactus types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":                "Cactus",
+		"sunlight":            "Full Sun",
+		"preferedTemperature": 25,
+		"location":            map[string]any{"roomId": hallway.ID},
+	}, &cactus)
+
+	var report types.PlacementReport
+	if status := api.do(http.MethodGet, "/api/plants/"+cactus.ID+"/placement", "user1", nil, &report); status != http.StatusOK {
+		t.Fatalf("placement: got %d", status)
+	}
+	if report.Status != types.PlacementPoor || report.Score == nil || len(report.Checks) != 2 {
+		t.Fatalf("report: got %+v", report)
+	}
+	if report.Checks[0].Factor != types.FactorLight || report.Checks[0].Score != 0 {
+		t.Errorf("light check: got %+v", report.Checks[0])
+	}
+	if len(report.Suggestions) != 1 || report.Suggestions[0].RoomID != conservatory.ID || report.Suggestions[0].Score != 100 {
+		t.Errorf("suggestions: got %+v", report.Suggestions)
+	}
+
+	if status := api.do(http.MethodGet, "/api/plants/"+cactus.ID+"/placement", "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("other user: got %d, want 404", status)
+	}
+}
+
+func TestPlacement_ListsWorstFirst(t *testing.T) {
+	api := newTestAPI(t)
+	var bathroom types.Room
+	api.do(http.MethodPost, "/api/rooms", "user1", map[string]any{
+		"name": "Bathroom", "light": "Partial Shade", "humidityPct": 70,
+	}, &bathroom)
+
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":     "Fern",
+		"sunlight": "Partial Shade",
+		"humidity": map[string]any{"targetHumidityPct": 65},
+		"location": map[string]any{"roomId": bathroom.ID},
+	}, nil)
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":     "Aloe",
+		"sunlight": "Indirect Sun",
+		"location": map[string]any{"roomId": bathroom.ID},
+	}, nil)
+	api.createPlant("user1", "Unplaced")
+
+	var reports []types.PlacementReport
+	if status := api.do(http.MethodGet, "/api/rooms/placement", "user1", nil, &reports); status != http.StatusOK {
+		t.Fatalf("placements: got %d", status)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("reports: got %+v", reports)
+	}
+	if reports[0].Name != "Aloe" || *reports[0].Score != 70 || reports[0].Status != types.PlacementFair {
+		t.Errorf("first: got %+v", reports[0])
+	}
+	if reports[1].Name != "Fern" || *reports[1].Score != 100 {
+		t.Errorf("second: got %+v", reports[1])
+	}
+	if reports[2].Name != "Unplaced" || reports[2].Score != nil || reports[2].Status != types.PlacementUnknown {
+		t.Errorf("third: got %+v", reports[2])
+	}
+}
@@ -0,0 +1,257 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+)
+
+// maxImportBytes bounds import request bodies; a full collection of plants
+// with histories stays far below it
+const maxImportBytes = 5 << 20
+
+// exportPlants responds with the user's own plants as JSON or CSV. The JSON
+// format can be imported again as is.
+func exportPlants(w http.ResponseWriter, r *http.Request, db services.PlantStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		util.BadRequest(w, "Format must be json or csv", nil)
+		return
+	}
+
+	plants, err := db.GetPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+
+	filename := "plants-" + time.Now().UTC().Format(time.DateOnly) + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if format == "json" {
+		collection := types.PlantCollection{Plants: make([]types.CreatePlantRequest, 0, len(plants))}
+		for _, plant := range plants {
+			collection.Plants = append(collection.Plants, services.PlantToCreateRequest(plant))
+		}
+		util.RespondJSON(w, http.StatusOK, collection)
+		return
+	}
+
+	// Write to a buffer first so a failure can still be reported as an error
+	var buf bytes.Buffer
+	if err := services.WritePlantsCSV(&buf, plants); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// importPlants creates plants from a JSON collection or a CSV file. The import
+// is all or nothing: if any row is invalid or the rows would not fit within
+// the plant limit, no plant is created. With ?dryRun=true the response
+// previews the result without creating anything.
+func importPlants(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantStore,
+	species services.SpeciesStore,
+	rooms services.RoomStore,
+	pests *services.PestTracker,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	var (
+		requests  []types.CreatePlantRequest
+		rowErrors [][]types.ValidationError
+	)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		var err error
+		requests, rowErrors, err = services.ReadPlantsCSV(r.Body)
+		if err != nil {
+			util.BadRequest(w, err.Error(), nil)
+			return
+		}
+	} else {
+		var collection types.PlantCollection
+		if err := util.DecodeJSON(r, &collection); err != nil {
+			util.BadRequest(w, err.Error(), nil)
+			return
+		}
+		requests = collection.Plants
+		rowErrors = make([][]types.ValidationError, len(requests))
+	}
+	if len(requests) == 0 {
+		util.BadRequest(w, "No plants to import", nil)
+		return
+	}
+
+	existingPlants, err := db.GetPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if len(existingPlants)+len(requests) > validation.MaxPlantsPerUser {
+		util.BadRequest(w, "Plant limit exceeded", map[string]interface{}{
+			"limit":     validation.MaxPlantsPerUser,
+			"current":   len(existingPlants),
+			"requested": len(requests),
+		})
+		return
+	}
+
+	response := types.ImportPlantsResponse{DryRun: dryRun, Rows: make([]types.ImportRowResult, len(requests))}
+	plants := make([]types.Plant, len(requests))
+	valid := true
+	for i, req := range requests {
+		speciesErrors, err := applySpecies(r.Context(), species, &req)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+		errors := append(rowErrors[i], speciesErrors...)
+		errors = append(errors, validation.ValidateCreatePlantRequest(req)...)
+		// Rooms that do not exist yet are created below, once all rows are valid
+		roomErrors, err := resolveRoom(r.Context(), rooms, userID, req.Location, false)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+		errors = append(errors, roomErrors...)
+		if req.HouseholdID != "" {
+			errors = append(errors, types.ValidationError{
+				Field:   "householdId",
+				Message: "Imported plants cannot be added to a household",
+			})
+		}
+		response.Rows[i] = types.ImportRowResult{Row: i + 1, Name: req.Name, Errors: errors}
+		if len(errors) > 0 {
+			valid = false
+			continue
+		}
+		// Slugs must also be unique among the plants of this import
+		plants[i] = importPlantFromRequest(req, userID, existingPlants)
+		existingPlants = append(existingPlants, plants[i])
+		response.Rows[i].Slug = plants[i].Slug
+	}
+	if !valid {
+		util.BadRequest(w, "Validation failed", response.Rows)
+		return
+	}
+	if dryRun {
+		util.RespondJSON(w, http.StatusOK, response)
+		return
+	}
+
+	created := make([]types.Plant, 0, len(plants))
+	newRooms := &createdRooms{RoomStore: rooms}
+	undo := func() {
+		// Undo the plants and rooms created so far, so a retry starts from
+		// scratch
+		for _, plant := range created {
+			_, _ = db.DeletePlant(r.Context(), plant.ID, userID)
+		}
+		for _, room := range newRooms.rooms {
+			_, _ = rooms.DeleteRoom(r.Context(), room.UserID, room.ID)
+		}
+	}
+	for i, plant := range plants {
+		roomErrors, err := resolveRoom(r.Context(), newRooms, userID, plant.Location, true)
+		if err != nil {
+			undo()
+			util.ServerError(w, r, err)
+			return
+		}
+		if len(roomErrors) > 0 {
+			undo()
+			response.Rows[i].Errors = roomErrors
+			util.BadRequest(w, "Validation failed", response.Rows)
+			return
+		}
+		createdPlant, err := db.CreatePlant(r.Context(), plant)
+		if err != nil {
+			undo()
+			util.ServerError(w, r, err)
+			return
+		}
+		created = append(created, *createdPlant)
+		response.Rows[i].ID = createdPlant.ID
+	}
+	// Once all plants exist, so outbreaks see every imported plant in a room
+	for _, plant := range created {
+		recordPests(r.Context(), pests, nil, plant)
+	}
+	response.Imported = len(created)
+	util.RespondJSON(w, http.StatusCreated, response)
+}
+
+// createdRooms records the rooms created through it, so an import that fails
+// half-way can delete them again
+type createdRooms struct {
+	services.RoomStore
+	rooms []types.Room
+}
+
+func (c *createdRooms) CreateRoom(ctx context.Context, room types.Room) (*types.Room, error) {
+	created, err := c.RoomStore.CreateRoom(ctx, room)
+	if err == nil {
+		c.rooms = append(c.rooms, *created)
+	}
+	return created, err
+}
+
+// importPlantFromRequest is createPlantFromRequest for imports: it keeps the
+// imported last care dates and only the photos stored for the user
+func importPlantFromRequest(
+	req types.CreatePlantRequest,
+	userID string,
+	existingPlants []types.Plant,
+) types.Plant {
+	var lastWatered, lastFertilized *time.Time
+	if req.Watering != nil {
+		lastWatered = req.Watering.LastWatered
+	}
+	if req.Fertilizing != nil {
+		lastFertilized = req.Fertilizing.LastFertilized
+	}
+
+	photoIDs := make([]string, 0, len(req.PhotoIDs))
+	for _, id := range req.PhotoIDs {
+		if services.KeyBelongsToUser(id, userID) {
+			photoIDs = append(photoIDs, id)
+		}
+	}
+	req.PhotoIDs = photoIDs
+
+	plant := createPlantFromRequest(req, userID, existingPlants)
+	if lastWatered != nil {
+		plant.Watering.LastWatered = lastWatered
+	}
+	if lastFertilized != nil {
+		plant.Fertilizing.LastFertilized = lastFertilized
+	}
+	return plant
+}
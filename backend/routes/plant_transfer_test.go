@@ -0,0 +1,203 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/validation"
+)
+
+// importCSV posts a CSV file to the import endpoint and returns the recorder
+func (api *testAPI) importCSV(userID, query, csv string) *httptest.ResponseRecorder {
+	api.t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/plants/import"+query, strings.NewReader(csv))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+userID)
+	rec := httptest.NewRecorder()
+	api.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPlantTransfer_CSVRoundTrip(t *testing.T) {
+	api := newTestAPI(t)
+	status := api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":     "Calathea",
+		"location": map[string]any{"room": "Bedroom"},
+		"watering": map[string]any{"intervalDays": 7, "method": "Top", "waterType": "Rain"},
+		"flags":    []string{"No Draught", "Sensitive Roots"},
+		"notes":    []string{"mist daily; no direct sun", "turn weekly"},
+	}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("create: got %d", status)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/plants/export?format=csv", nil)
+	req.Header.Set("Authorization", "Bearer user1")
+	rec := httptest.NewRecorder()
+	api.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("export: got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rec = api.importCSV("user2", "", rec.Body.String())
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("import: got %d %s", rec.Code, rec.Body.String())
+	}
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "user2", nil, &plants)
+	if len(plants) != 1 {
+		t.Fatalf("imported %d plants, want 1", len(plants))
+	}
+	plant := plants[0]
+	if plant.Slug != "calathea" || plant.Location == nil || plant.Location.Room != "Bedroom" {
+		t.Errorf("plant: got slug %q location %+v", plant.Slug, plant.Location)
+	}
+	if plant.Watering == nil || plant.Watering.IntervalDays != 7 || plant.Watering.WaterType != types.WaterRain {
+		t.Errorf("watering: got %+v", plant.Watering)
+	}
+	if len(plant.Flags) != 2 || len(plant.Notes) != 2 || plant.Notes[0] != "mist daily; no direct sun" {
+		t.Errorf("flags %v notes %q", plant.Flags, plant.Notes)
+	}
+}
+
+func TestPlantTransfer_JSONImportKeepsCareDatesAndDedupesSlugs(t *testing.T) {
+	api := newTestAPI(t)
+	api.createPlant("user1", "Ficus")
+
+	var export types.PlantCollection
+	if status := api.do(http.MethodGet, "/api/plants/export", "user1", nil, &export); status != http.StatusOK {
+		t.Fatalf("export: got %d", status)
+	}
+	if len(export.Plants) != 1 || export.Plants[0].Name != "Ficus" {
+		t.Fatalf("export: got %+v", export.Plants)
+	}
+
+	body := map[string]any{"plants": []map[string]any{
+		{"name": "Ficus", "watering": map[string]any{
+			"intervalDays": 5, "method": "Top", "waterType": "Tap", "lastWatered": "2026-01-02T00:00:00Z",
+		}},
+		{"name": "Ficus", "photoIds": []string{"users/user1/a.jpg", "users/other/b.jpg"}},
+	}}
+	var result types.ImportPlantsResponse
+	if status := api.do(http.MethodPost, "/api/plants/import", "user1", body, &result); status != http.StatusCreated {
+		t.Fatalf("import: got %d", status)
+	}
+	if result.Imported != 2 || result.Rows[0].Slug != "ficus-1" || result.Rows[1].Slug != "ficus-2" {
+		t.Fatalf("import: got %+v", result)
+	}
+
+	var watered types.Plant
+	api.do(http.MethodGet, "/api/plants/"+result.Rows[0].ID, "user1", nil, &watered)
+	if watered.Watering.LastWatered == nil || watered.Watering.LastWatered.Year() != 2026 ||
+		watered.Watering.LastWatered.Month() != 1 {
+		t.Errorf("lastWatered: got %v", watered.Watering.LastWatered)
+	}
+	var withPhotos types.Plant
+	api.do(http.MethodGet, "/api/plants/"+result.Rows[1].ID, "user1", nil, &withPhotos)
+	if len(withPhotos.PhotoIDs) != 1 || withPhotos.PhotoIDs[0] != "users/user1/a.jpg" {
+		t.Errorf("photoIds: got %v", withPhotos.PhotoIDs)
+	}
+}
+
+func TestPlantTransfer_ImportIsAllOrNothing(t *testing.T) {
+	api := newTestAPI(t)
+
+	rec := api.importCSV("user1", "", "name,preferedTemperature,isToxic\n"+
+		"Pothos,21,false\n"+
+		",21,false\n"+
+		"Aloe,warm,maybe\n")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid import: got %d", rec.Code)
+	}
+	var response struct {
+		Details []types.ImportRowResult `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	rows := response.Details
+	if len(rows) != 3 || len(rows[0].Errors) != 0 || len(rows[1].Errors) == 0 {
+		t.Fatalf("rows: got %+v", rows)
+	}
+	fields := map[string]bool{}
+	for _, e := range rows[2].Errors {
+		fields[e.Field] = true
+	}
+	if !fields["preferedTemperature"] || !fields["isToxic"] {
+		t.Errorf("row 3 errors: got %+v", rows[2].Errors)
+	}
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "user1", nil, &plants)
+	if len(plants) != 0 {
+		t.Errorf("invalid import created %d plants", len(plants))
+	}
+}
+
+func TestPlantTransfer_FailedImportDeletesCreatedRooms(t *testing.T) {
+	api := newTestAPI(t)
+	for i := 0; i < validation.MaxRoomsPerUser-1; i++ {
+		api.createRoom("user1", fmt.Sprintf("Room %d", i))
+	}
+
+	// The first row takes the last free room, the second exceeds the limit
+	rec := api.importCSV("user1", "", "name,room\nPothos,Attic\nAloe,Cellar\n")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("import: got %d, want 400", rec.Code)
+	}
+
+	var rooms []types.Room
+	api.do(http.MethodGet, "/api/rooms", "user1", nil, &rooms)
+	if len(rooms) != validation.MaxRoomsPerUser-1 {
+		t.Errorf("rooms: got %d, want %d", len(rooms), validation.MaxRoomsPerUser-1)
+	}
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "user1", nil, &plants)
+	if len(plants) != 0 {
+		t.Errorf("failed import left %d plants", len(plants))
+	}
+}
+
+func TestPlantTransfer_DryRunAndPlantLimit(t *testing.T) {
+	api := newTestAPI(t)
+	api.createPlant("user1", "Pothos")
+
+	var preview types.ImportPlantsResponse
+	status := api.do(http.MethodPost, "/api/plants/import?dryRun=true", "user1",
+		map[string]any{"plants": []map[string]any{{"name": "Pothos"}}}, &preview)
+	if status != http.StatusOK || !preview.DryRun || preview.Rows[0].Slug != "pothos-1" {
+		t.Fatalf("dry run: got %d %+v", status, preview)
+	}
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "user1", nil, &plants)
+	if len(plants) != 1 {
+		t.Fatalf("dry run created plants: got %d", len(plants))
+	}
+
+	// One plant more than fits is refused as a whole
+	rows := make([]map[string]any, validation.MaxPlantsPerUser)
+	for i := range rows {
+		rows[i] = map[string]any{"name": fmt.Sprintf("Plant %d", i)}
+	}
+	status = api.do(http.MethodPost, "/api/plants/import", "user1", map[string]any{"plants": rows}, nil)
+	if status != http.StatusBadRequest {
+		t.Fatalf("import over limit: got %d, want 400", status)
+	}
+	api.do(http.MethodGet, "/api/plants", "user1", nil, &plants)
+	if len(plants) != 1 {
+		t.Errorf("import over limit created plants: got %d", len(plants))
+	}
+
+	status = api.do(http.MethodPost, "/api/plants/import", "user1", map[string]any{"plants": rows[1:]}, nil)
+	if status != http.StatusCreated {
+		t.Errorf("import up to limit: got %d, want 201", status)
+	}
+}
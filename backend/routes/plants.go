@@ -2,13 +2,11 @@ package routes
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"regexp"
-	"strings"
 	"time"
 
+	"github.com/qreepex/water-me-app/backend/metrics"
 	"github.com/qreepex/water-me-app/backend/services"
 	"github.com/qreepex/water-me-app/backend/types"
 	"github.com/qreepex/water-me-app/backend/util"
@@ -32,7 +30,16 @@ func getRealIP(r *http.Request) string {
 }
 
 // PlantHandler registers plant-related routes
-func PlantHandler(router *mux.Router, database *services.MongoDB, s3 *services.S3Service) {
+func PlantHandler(
+	router *mux.Router,
+	database services.PlantStore,
+	species services.SpeciesStore,
+	rooms services.RoomStore,
+	access *services.PlantAccess,
+	safety *services.SafetyChecker,
+	pests *services.PestTracker,
+	objects services.ObjectStore,
+) {
 	// Create rate limiter once
 	rateLimiter := services.NewRateLimiter()
 
@@ -42,6 +49,7 @@ func PlantHandler(router *mux.Router, database *services.MongoDB, s3 *services.S
 			ip := getRealIP(r)
 			userID, ok := getUserID(r)
 			if ok && rateLimiter.IsRateLimited(userID, ip) {
+				metrics.RateLimitRejections.Inc()
 				w.Header().Set("Retry-After", "60")
 				http.Error(w, "Too many requests", http.StatusTooManyRequests)
 				return
@@ -51,47 +59,63 @@ func PlantHandler(router *mux.Router, database *services.MongoDB, s3 *services.S
 	})
 
 	router.HandleFunc("/api/plants", func(w http.ResponseWriter, r *http.Request) {
-		getPlants(w, r, database, s3)
+		getPlants(w, r, access, objects)
 	}).Methods(http.MethodGet, http.MethodOptions)
 
 	router.HandleFunc("/api/plants", func(w http.ResponseWriter, r *http.Request) {
-		createPlant(w, r, database)
+		createPlant(w, r, database, species, rooms, access, safety, pests)
 	}).Methods(http.MethodPost, http.MethodOptions)
 
 	router.HandleFunc("/api/plants/water", func(w http.ResponseWriter, r *http.Request) {
-		waterPlants(w, r, database)
+		waterPlants(w, r, access)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/export", func(w http.ResponseWriter, r *http.Request) {
+		exportPlants(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/import", func(w http.ResponseWriter, r *http.Request) {
+		importPlants(w, r, database, species, rooms, pests)
 	}).Methods(http.MethodPost, http.MethodOptions)
 
+	router.HandleFunc("/api/plants/risky", func(w http.ResponseWriter, r *http.Request) {
+		getRiskyPlants(w, r, access, safety)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
 	router.HandleFunc("/api/plants/slug/{slug}", func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		slug := vars["slug"]
-		getPlantBySlug(w, r, database, s3, slug)
+		getPlantBySlug(w, r, access, objects, slug)
 	}).Methods(http.MethodGet, http.MethodOptions)
 
 	router.HandleFunc("/api/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		id := vars["id"]
-		updatePlant(w, r, database, s3, id)
+		updatePlant(w, r, database, species, rooms, access, safety, pests, objects, id)
 	}).Methods(http.MethodPatch, http.MethodOptions)
 
 	router.HandleFunc("/api/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		id := vars["id"]
-		deletePlant(w, r, database, id)
+		deletePlant(w, r, database, access, id)
 	}).Methods(http.MethodDelete, http.MethodOptions)
 
 	router.HandleFunc("/api/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		id := vars["id"]
-		getPlant(w, r, database, s3, id)
+		getPlant(w, r, access, objects, id)
 	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/household", func(w http.ResponseWriter, r *http.Request) {
+		setPlantHousehold(w, r, database, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodPut, http.MethodOptions)
 }
 
 func getPlants(
 	w http.ResponseWriter,
 	r *http.Request,
-	db *services.MongoDB,
-	s3 *services.S3Service,
+	access *services.PlantAccess,
+	objects services.ObjectStore,
 ) {
 	userID, ok := getUserID(r)
 	if !ok {
@@ -99,11 +123,9 @@ func getPlants(
 		return
 	}
 
-	log.Printf("Getting plants for %v", userID)
-
-	plants, err := db.GetPlants(r.Context(), userID)
+	plants, err := access.ListPlants(r.Context(), userID)
 	if err != nil {
-		log.Printf("Failed to retrieve plants: %v", err)
+		slog.ErrorContext(r.Context(), "failed to retrieve plants", "error", err)
 		http.Error(w, "Failed to retrieve plants", http.StatusInternalServerError)
 		return
 	}
@@ -113,7 +135,7 @@ func getPlants(
 	// Enrich with signed photo URLs
 	for i := range plants {
 		normalizePlantResponse(&plants[i])
-		plants[i].PhotoURLs = resolvePhotoURLs(r.Context(), s3, plants[i].PhotoIDs, userID)
+		plants[i].PhotoURLs = resolvePhotoURLs(r.Context(), objects, plants[i].PhotoIDs, plants[i].UserID)
 	}
 	util.RespondJSON(w, http.StatusOK, plants)
 }
@@ -121,8 +143,8 @@ func getPlants(
 func getPlantBySlug(
 	w http.ResponseWriter,
 	r *http.Request,
-	db *services.MongoDB,
-	s3 *services.S3Service,
+	access *services.PlantAccess,
+	objects services.ObjectStore,
 	slug string,
 ) {
 	userID, ok := getUserID(r)
@@ -130,10 +152,9 @@ func getPlantBySlug(
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	log.Printf("Getting plant by slug '%s' for user %v", slug, userID)
-	plant, err := db.GetPlantBySlug(r.Context(), userID, slug)
+	plant, err := access.GetPlantBySlug(r.Context(), userID, slug)
 	if err != nil {
-		log.Printf("Failed to retrieve plant by slug: %v", err)
+		slog.ErrorContext(r.Context(), "failed to retrieve plant by slug", "error", err)
 		http.Error(w, "Failed to retrieve plant", http.StatusInternalServerError)
 		return
 	}
@@ -142,15 +163,15 @@ func getPlantBySlug(
 		return
 	}
 	normalizePlantResponse(plant)
-	plant.PhotoURLs = resolvePhotoURLs(r.Context(), s3, plant.PhotoIDs, userID)
+	plant.PhotoURLs = resolvePhotoURLs(r.Context(), objects, plant.PhotoIDs, plant.UserID)
 	util.RespondJSON(w, http.StatusOK, plant)
 }
 
 func getPlant(
 	w http.ResponseWriter,
 	r *http.Request,
-	db *services.MongoDB,
-	s3 *services.S3Service,
+	access *services.PlantAccess,
+	objects services.ObjectStore,
 	id string,
 ) {
 	userID, ok := getUserID(r)
@@ -158,10 +179,9 @@ func getPlant(
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	log.Printf("Getting plant by ID '%s' for user %v", id, userID)
-	plant, err := db.GetPlant(r.Context(), userID, id)
+	plant, err := access.GetPlant(r.Context(), userID, id)
 	if err != nil {
-		log.Printf("Failed to retrieve plant by ID: %v", err)
+		slog.ErrorContext(r.Context(), "failed to retrieve plant by ID", "plantId", id, "error", err)
 		http.Error(w, "Failed to retrieve plant", http.StatusInternalServerError)
 		return
 	}
@@ -170,11 +190,20 @@ func getPlant(
 		return
 	}
 	normalizePlantResponse(plant)
-	plant.PhotoURLs = resolvePhotoURLs(r.Context(), s3, plant.PhotoIDs, userID)
+	plant.PhotoURLs = resolvePhotoURLs(r.Context(), objects, plant.PhotoIDs, plant.UserID)
 	util.RespondJSON(w, http.StatusOK, plant)
 }
 
-func createPlant(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func createPlant(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantStore,
+	species services.SpeciesStore,
+	rooms services.RoomStore,
+	access *services.PlantAccess,
+	safety *services.SafetyChecker,
+	pests *services.PestTracker,
+) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -185,14 +214,19 @@ func createPlant(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
 		util.BadRequest(w, err.Error(), nil)
 		return
 	}
-	errors := validation.ValidateCreatePlantRequest(req)
+	errors, err := applySpecies(r.Context(), species, &req)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	errors = append(errors, validation.ValidateCreatePlantRequest(req)...)
 	if len(errors) > 0 {
 		util.BadRequest(w, "Validation failed", errors)
 		return
 	}
 	existingPlants, err := db.GetPlants(r.Context(), userID)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 
@@ -205,21 +239,55 @@ func createPlant(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
 		return
 	}
 
+	if req.HouseholdID != "" {
+		role, member, err := access.HouseholdRole(r.Context(), userID, req.HouseholdID)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+		if !member || !role.CanEditPlants() {
+			util.BadRequest(w, "Cannot add plants to this household", nil)
+			return
+		}
+	}
+
+	roomErrors, err := resolveRoom(r.Context(), rooms, userID, req.Location, true)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if len(roomErrors) > 0 {
+		util.BadRequest(w, "Validation failed", roomErrors)
+		return
+	}
+
 	plant := createPlantFromRequest(req, userID, existingPlants)
 	createdPlant, err := db.CreatePlant(r.Context(), plant)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 	normalizePlantResponse(createdPlant)
+	createdPlant.Role = types.RoleOwner
+	createdPlant.SafetyWarnings, err = safety.Risks(r.Context(), *createdPlant)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	createdPlant.Outbreaks = recordPests(r.Context(), pests, nil, *createdPlant)
 	util.RespondJSON(w, http.StatusCreated, createdPlant)
 }
 
 func updatePlant(
 	w http.ResponseWriter,
 	r *http.Request,
-	db *services.MongoDB,
-	s3 *services.S3Service,
+	db services.PlantStore,
+	species services.SpeciesStore,
+	rooms services.RoomStore,
+	access *services.PlantAccess,
+	safety *services.SafetyChecker,
+	pests *services.PestTracker,
+	objects services.ObjectStore,
 	id string,
 ) {
 	userID, ok := getUserID(r)
@@ -233,13 +301,47 @@ func updatePlant(
 		return
 	}
 	errors := validation.ValidateUpdatePlantRequest(req)
+	var known *types.Species
+	if req.SpeciesID != nil && *req.SpeciesID != "" {
+		var err error
+		known, err = species.GetSpecies(r.Context(), *req.SpeciesID)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+		if known == nil {
+			errors = append(errors, unknownSpeciesError)
+		}
+	}
 	if len(errors) > 0 {
 		util.BadRequest(w, "Validation failed", errors)
 		return
 	}
-	plant, found, err := db.UpdatePlant(r.Context(), id, userID, req)
+	existing, ok := editablePlant(w, r, access, userID, id)
+	if !ok {
+		return
+	}
+	if known != nil && req.Toxicity == nil && existing.Toxicity == nil {
+		req.Toxicity = services.SpeciesToxicity(*known)
+	}
+	if req.Toxicity != nil && req.IsToxic == nil {
+		toxic := req.Toxicity.Toxic()
+		req.IsToxic = &toxic
+	}
+	// Rooms belong to the plant's owner, also when an editor moves the plant
+	roomErrors, err := resolveRoom(r.Context(), rooms, existing.UserID, req.Location, true)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if len(roomErrors) > 0 {
+		util.BadRequest(w, "Validation failed", roomErrors)
+		return
+	}
+	// Household editors update the plant on behalf of its owner
+	plant, found, err := db.UpdatePlant(r.Context(), id, existing.UserID, req)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 	if !found {
@@ -247,19 +349,65 @@ func updatePlant(
 		return
 	}
 	normalizePlantResponse(plant)
-	plant.PhotoURLs = resolvePhotoURLs(r.Context(), s3, plant.PhotoIDs, userID)
+	plant.Role = existing.Role
+	plant.PhotoURLs = resolvePhotoURLs(r.Context(), objects, plant.PhotoIDs, plant.UserID)
+	plant.SafetyWarnings, err = safety.Risks(r.Context(), *plant)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if req.PestHistory != nil {
+		plant.Outbreaks = recordPests(r.Context(), pests, existing, *plant)
+	}
 	util.RespondJSON(w, http.StatusOK, plant)
 }
 
-func deletePlant(w http.ResponseWriter, r *http.Request, db *services.MongoDB, id string) {
+// getRiskyPlants lists the accessible plants that are toxic to pets or
+// children who can reach them
+func getRiskyPlants(
+	w http.ResponseWriter,
+	r *http.Request,
+	access *services.PlantAccess,
+	safety *services.SafetyChecker,
+) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	deleted, err := db.DeletePlant(r.Context(), id, userID)
+
+	plants, err := access.ListPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	risky, err := safety.RiskyPlants(r.Context(), plants)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, risky)
+}
+
+func deletePlant(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	existing, ok := editablePlant(w, r, access, userID, id)
+	if !ok {
+		return
+	}
+	deleted, err := db.DeletePlant(r.Context(), id, existing.UserID)
+	if err != nil {
+		util.ServerError(w, r, err)
 		return
 	}
 	if !deleted {
@@ -269,7 +417,7 @@ func deletePlant(w http.ResponseWriter, r *http.Request, db *services.MongoDB, i
 	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
-func waterPlants(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
+func waterPlants(w http.ResponseWriter, r *http.Request, access *services.PlantAccess) {
 	userID, ok := getUserID(r)
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -286,69 +434,110 @@ func waterPlants(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
 		util.BadRequest(w, "At least one plant ID is required", nil)
 		return
 	}
-	_, err := db.WaterPlants(r.Context(), userID, req.PlantIDs)
+	_, err := access.WaterPlants(r.Context(), userID, req.PlantIDs)
 	if err != nil {
-		util.ServerError(w, err)
+		util.ServerError(w, r, err)
 		return
 	}
 	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
-// slugify converts a string to a URL-friendly slug
-func slugify(s string) string {
-	s = strings.ToLower(s)
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	s = reg.ReplaceAllString(s, "-")
-	reg = regexp.MustCompile(`-+`)
-	s = reg.ReplaceAllString(s, "-")
-	s = strings.Trim(s, "-")
-	return s
-}
+// setPlantHousehold shares a plant with a household or stops sharing it. Only
+// the plant's owner can do this, and only into households they may edit.
+func setPlantHousehold(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req types.SetPlantHouseholdRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
 
-// generateUniqueSlug creates a unique slug for the plant within the user's collection
-func generateUniqueSlug(
-	name string,
-	location *types.Location,
-	existingPlants []types.Plant,
-) string {
-	baseSlug := slugify(name)
-	if baseSlug == "" {
-		baseSlug = "plant"
-	}
-	if !slugExists(baseSlug, existingPlants) {
-		return baseSlug
-	}
-	var locationPart string
-	if location != nil {
-		locationPart = slugify(location.Room)
-		if locationPart == "" {
-			locationPart = slugify(location.Position)
+	if req.HouseholdID != "" {
+		role, member, err := access.HouseholdRole(r.Context(), userID, req.HouseholdID)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
 		}
-	}
-	if locationPart != "" {
-		slugWithLocation := baseSlug + "-" + locationPart
-		if !slugExists(slugWithLocation, existingPlants) {
-			return slugWithLocation
+		if !member || !role.CanEditPlants() {
+			util.BadRequest(w, "Cannot add plants to this household", nil)
+			return
 		}
 	}
-	counter := 1
-	for {
-		numberedSlug := fmt.Sprintf("%s-%d", baseSlug, counter)
-		if !slugExists(numberedSlug, existingPlants) {
-			return numberedSlug
-		}
-		counter++
+
+	found, err := db.SetPlantHousehold(r.Context(), id, userID, req.HouseholdID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !found {
+		util.NotFound(w)
+		return
 	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
-// slugExists checks if a slug already exists in the user's plants
-func slugExists(slug string, plants []types.Plant) bool {
-	for _, plant := range plants {
-		if plant.Slug == slug {
-			return true
-		}
+// editablePlant loads a plant the user may edit. It responds with 404 if the
+// plant is not accessible and 403 if the user's role is read or care only.
+func editablePlant(
+	w http.ResponseWriter,
+	r *http.Request,
+	access *services.PlantAccess,
+	userID, id string,
+) (*types.Plant, bool) {
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return nil, false
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return nil, false
+	}
+	if !plant.Role.CanEditPlants() {
+		util.Forbidden(w)
+		return nil, false
+	}
+	return plant, true
+}
+
+var unknownSpeciesError = types.ValidationError{Field: "speciesId", Message: "Unknown species"}
+
+// applySpecies checks that the species req links to exists, copies its
+// toxicity unless req has its own and, if requested, fills the empty care
+// sections of req from it
+func applySpecies(
+	ctx context.Context,
+	species services.SpeciesStore,
+	req *types.CreatePlantRequest,
+) ([]types.ValidationError, error) {
+	errors := make([]types.ValidationError, 0)
+	if req.SpeciesID == "" {
+		return errors, nil
+	}
+	entry, err := species.GetSpecies(ctx, req.SpeciesID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return append(errors, unknownSpeciesError), nil
+	}
+	if req.Toxicity == nil {
+		req.Toxicity = services.SpeciesToxicity(*entry)
 	}
-	return false
+	if req.AutofillCare {
+		services.ApplySpeciesDefaults(req, *entry)
+	}
+	return errors, nil
 }
 
 func createPlantFromRequest(
@@ -357,13 +546,15 @@ func createPlantFromRequest(
 	existingPlants []types.Plant,
 ) types.Plant {
 	now := time.Now()
-	slug := generateUniqueSlug(req.Name, req.Location, existingPlants)
+	slug := util.GenerateUniqueSlug(req.Name, req.Location, existingPlants)
 	plant := types.Plant{
 		UserID:              userID,
 		Slug:                slug,
 		Name:                req.Name,
 		Species:             req.Species,
+		SpeciesID:           req.SpeciesID,
 		IsToxic:             req.IsToxic,
+		Toxicity:            req.Toxicity,
 		Sunlight:            req.Sunlight,
 		PreferedTemperature: req.PreferedTemperature,
 		Location:            req.Location,
@@ -377,10 +568,15 @@ func createPlantFromRequest(
 		Notes:               req.Notes,
 		PhotoIDs:            req.PhotoIDs,
 		GrowthHistory:       req.GrowthHistory,
+		HouseholdID:         req.HouseholdID,
 		CreatedAt:           now,
 		UpdatedAt:           now,
 	}
 
+	if plant.Toxicity != nil {
+		plant.IsToxic = plant.Toxicity.Toxic()
+	}
+
 	if plant.Watering == nil {
 		plant.Watering = &types.WateringConfig{}
 	}
@@ -441,7 +637,7 @@ func normalizePlantResponse(plant *types.Plant) {
 }
 
 func isEmptyLocation(loc types.Location) bool {
-	return loc.Room == "" && loc.Position == "" && !loc.IsOutdoors
+	return loc.RoomID == "" && loc.Room == "" && loc.Position == "" && !loc.IsOutdoors
 }
 
 func isEmptyHumidity(h types.HumidityConfig) bool {
@@ -463,7 +659,7 @@ func isEmptySeasonality(s types.SeasonalAdjustments) bool {
 // resolvePhotoURLs presigns GET URLs for user's images
 func resolvePhotoURLs(
 	ctx context.Context,
-	s3 *services.S3Service,
+	objects services.ObjectStore,
 	keys []string,
 	userID string,
 ) []string {
@@ -472,7 +668,7 @@ func resolvePhotoURLs(
 		if !services.KeyBelongsToUser(k, userID) {
 			continue
 		}
-		url, err := s3.PresignGetURL(ctx, k)
+		url, err := objects.PresignGetURL(ctx, k)
 		if err == nil && url != "" {
 			urls = append(urls, url)
 		}
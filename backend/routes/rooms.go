@@ -0,0 +1,375 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// RoomHandler registers routes to manage rooms and the plants in them
+func RoomHandler(
+	router *mux.Router,
+	database services.RoomStore,
+	plants services.PlantStore,
+	access *services.PlantAccess,
+	objects services.ObjectStore,
+) {
+	router.HandleFunc("/api/rooms", func(w http.ResponseWriter, r *http.Request) {
+		getRooms(w, r, database, plants)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	// Must be registered before /api/rooms/{id}
+	router.HandleFunc("/api/rooms/placement", func(w http.ResponseWriter, r *http.Request) {
+		getPlacements(w, r, database, plants)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/rooms", func(w http.ResponseWriter, r *http.Request) {
+		createRoom(w, r, database)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/rooms/{id}", func(w http.ResponseWriter, r *http.Request) {
+		getRoom(w, r, database, plants, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/rooms/{id}", func(w http.ResponseWriter, r *http.Request) {
+		updateRoom(w, r, database, plants, mux.Vars(r)["id"])
+	}).Methods(http.MethodPatch, http.MethodOptions)
+
+	router.HandleFunc("/api/rooms/{id}", func(w http.ResponseWriter, r *http.Request) {
+		deleteRoom(w, r, database, plants, mux.Vars(r)["id"])
+	}).Methods(http.MethodDelete, http.MethodOptions)
+
+	router.HandleFunc("/api/rooms/{id}/plants", func(w http.ResponseWriter, r *http.Request) {
+		getRoomPlants(w, r, database, plants, objects, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/rooms/{id}/plants", func(w http.ResponseWriter, r *http.Request) {
+		movePlantsToRoom(w, r, database, plants, mux.Vars(r)["id"])
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/placement", func(w http.ResponseWriter, r *http.Request) {
+		getPlantPlacement(w, r, database, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+func getRooms(w http.ResponseWriter, r *http.Request, db services.RoomStore, plants services.PlantStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rooms, err := db.GetRooms(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	counts, err := roomPlantCounts(r.Context(), plants, userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for i := range rooms {
+		rooms[i].PlantCount = counts[rooms[i].ID]
+	}
+	util.RespondJSON(w, http.StatusOK, rooms)
+}
+
+func createRoom(w http.ResponseWriter, r *http.Request, db services.RoomStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.CreateRoomRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errs := validation.ValidateCreateRoomRequest(req); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	existing, err := db.GetRooms(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if len(existing) >= validation.MaxRoomsPerUser {
+		util.BadRequest(w, "Room limit exceeded", map[string]interface{}{
+			"limit":   validation.MaxRoomsPerUser,
+			"current": len(existing),
+		})
+		return
+	}
+
+	room, err := services.NewRoom(userID, req, time.Now())
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	created, err := db.CreateRoom(r.Context(), room)
+	if errors.Is(err, services.ErrRoomNameTaken) {
+		respondRoomNameTaken(w)
+		return
+	}
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusCreated, created)
+}
+
+func getRoom(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	plants services.PlantStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	room, ok := loadRoom(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	counts, err := roomPlantCounts(r.Context(), plants, userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	room.PlantCount = counts[room.ID]
+	util.RespondJSON(w, http.StatusOK, room)
+}
+
+// updateRoom changes a room; a new name or outdoor flag is copied to the
+// room's plants
+func updateRoom(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	plants services.PlantStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.UpdateRoomRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errs := validation.ValidateUpdateRoomRequest(req); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	room, ok := loadRoom(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	services.ApplyRoomUpdate(room, req, time.Now())
+	found, err := db.UpdateRoom(r.Context(), *room)
+	if errors.Is(err, services.ErrRoomNameTaken) {
+		respondRoomNameTaken(w)
+		return
+	}
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !found {
+		util.NotFound(w)
+		return
+	}
+	if req.Name != nil || req.IsOutdoors != nil {
+		if _, err := plants.SyncRoomPlants(r.Context(), *room); err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+	}
+	util.RespondJSON(w, http.StatusOK, room)
+}
+
+// deleteRoom deletes a room; its plants keep their position but no longer
+// have a room
+func deleteRoom(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	plants services.PlantStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, ok := loadRoom(w, r, db, userID, id); !ok {
+		return
+	}
+	if _, err := plants.ClearPlantRoom(r.Context(), userID, id); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if _, err := db.DeleteRoom(r.Context(), userID, id); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+func getRoomPlants(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	plants services.PlantStore,
+	objects services.ObjectStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, ok := loadRoom(w, r, db, userID, id); !ok {
+		return
+	}
+	all, err := plants.GetPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	inRoom := make([]types.Plant, 0)
+	for _, plant := range all {
+		if plant.Location == nil || plant.Location.RoomID != id {
+			continue
+		}
+		normalizePlantResponse(&plant)
+		plant.Role = types.RoleOwner
+		plant.PhotoURLs = resolvePhotoURLs(r.Context(), objects, plant.PhotoIDs, plant.UserID)
+		inRoom = append(inRoom, plant)
+	}
+	util.RespondJSON(w, http.StatusOK, inRoom)
+}
+
+// movePlantsToRoom moves the user's plants into the room. IDs of plants the
+// user does not own are skipped.
+func movePlantsToRoom(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	plants services.PlantStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.MovePlantsRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if len(req.PlantIDs) == 0 || len(req.PlantIDs) > validation.MaxPlantsPerUser {
+		util.BadRequest(w, "Validation failed", []types.ValidationError{{
+			Field:   "plantIds",
+			Message: fmt.Sprintf("Between 1 and %d plant IDs are required", validation.MaxPlantsPerUser),
+		}})
+		return
+	}
+
+	room, ok := loadRoom(w, r, db, userID, id)
+	if !ok {
+		return
+	}
+	moved, err := plants.MovePlantsToRoom(r.Context(), userID, req.PlantIDs, *room)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]int64{"moved": moved})
+}
+
+// loadRoom loads a room of the user, responding with 404 otherwise
+func loadRoom(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.RoomStore,
+	userID, id string,
+) (*types.Room, bool) {
+	room, err := db.GetRoom(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return nil, false
+	}
+	if room == nil {
+		util.NotFound(w)
+		return nil, false
+	}
+	return room, true
+}
+
+func roomPlantCounts(ctx context.Context, plants services.PlantStore, userID string) (map[string]int, error) {
+	all, err := plants.GetPlants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, plant := range all {
+		if plant.Location != nil && plant.Location.RoomID != "" {
+			counts[plant.Location.RoomID]++
+		}
+	}
+	return counts, nil
+}
+
+func respondRoomNameTaken(w http.ResponseWriter) {
+	util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "A room with this name already exists"})
+}
+
+// resolveRoom links loc to a room of ownerID as services.ResolveRoom does and
+// reports unknown rooms and the room limit as validation errors
+func resolveRoom(
+	ctx context.Context,
+	rooms services.RoomStore,
+	ownerID string,
+	loc *types.Location,
+	create bool,
+) ([]types.ValidationError, error) {
+	err := services.ResolveRoom(ctx, rooms, ownerID, loc, create, validation.MaxRoomsPerUser)
+	switch {
+	case errors.Is(err, services.ErrUnknownRoom):
+		return []types.ValidationError{{Field: "location.roomId", Message: "Unknown room"}}, nil
+	case errors.Is(err, services.ErrRoomLimit):
+		return []types.ValidationError{{
+			Field:   "location.room",
+			Message: "Room limit exceeded; pick one of the existing rooms",
+		}}, nil
+	case err != nil:
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,162 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func (api *testAPI) createRoom(userID, name string) types.Room {
+	api.t.Helper()
+	var room types.Room
+	if status := api.do(http.MethodPost, "/api/rooms", userID, map[string]any{"name": name}, &room); status != http.StatusCreated {
+		api.t.Fatalf("create room %s: got %d", name, status)
+	}
+	return room
+}
+
+func TestRooms_CRUDAndRenamePropagatesToPlants(t *testing.T) {
+	api := newTestAPI(t)
+	room := api.createRoom("user1", " Living   room ")
+	if room.Name != "Living room" {
+		t.Errorf("name: got %q", room.Name)
+	}
+
+	status := api.do(http.MethodPost, "/api/rooms", "user1", map[string]any{"name": "LIVING ROOM"}, nil)
+	if status != http.StatusConflict {
+		t.Errorf("duplicate name: got %d, want 409", status)
+	}
+	status = api.do(http.MethodPost, "/api/rooms", "user1", map[string]any{"name": "Hall", "light": "Bright"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("invalid light: got %d, want 400", status)
+	}
+
+	var plant types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Ficus", "location": map[string]any{"roomId": room.ID}}, &plant)
+	if plant.Location == nil || plant.Location.Room != "Living room" {
+		t.Fatalf("plant location: got %+v", plant.Location)
+	}
+
+	var updated types.Room
+	api.do(http.MethodPatch, "/api/rooms/"+room.ID, "user1",
+		map[string]any{"name": "Lounge", "isOutdoors": true, "humidityPct": 55}, &updated)
+	if updated.Name != "Lounge" || !updated.IsOutdoors || *updated.HumidityPct != 55 {
+		t.Errorf("update: got %+v", updated)
+	}
+	var fetched types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "user1", nil, &fetched)
+	if fetched.Location.Room != "Lounge" || !fetched.Location.IsOutdoors {
+		t.Errorf("plant after rename: got %+v", fetched.Location)
+	}
+
+	var rooms []types.Room
+	api.do(http.MethodGet, "/api/rooms", "user1", nil, &rooms)
+	if len(rooms) != 1 || rooms[0].PlantCount != 1 {
+		t.Errorf("rooms: got %+v", rooms)
+	}
+	if status := api.do(http.MethodGet, "/api/rooms/"+room.ID, "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("other user's room: got %d, want 404", status)
+	}
+
+	if status := api.do(http.MethodDelete, "/api/rooms/"+room.ID, "user1", nil, nil); status != http.StatusOK {
+		t.Fatalf("delete: got %d", status)
+	}
+	var unlinked types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "user1", nil, &unlinked)
+	if unlinked.Location != nil && (unlinked.Location.RoomID != "" || unlinked.Location.Room != "") {
+		t.Errorf("plant after room delete: got %+v", unlinked.Location)
+	}
+}
+
+func TestRooms_FreeTextRoomLinksToRoom(t *testing.T) {
+	api := newTestAPI(t)
+	kitchen := api.createRoom("user1", "Kitchen")
+
+	var plant types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Basil", "location": map[string]any{"room": "kitchen "}}, &plant)
+	if plant.Location.RoomID != kitchen.ID || plant.Location.Room != "Kitchen" {
+		t.Errorf("existing room: got %+v", plant.Location)
+	}
+
+	var other types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Fern", "location": map[string]any{"room": "Bathroom"}}, &other)
+	if other.Location.RoomID == "" {
+		t.Fatalf("new room: got %+v", other.Location)
+	}
+	var rooms []types.Room
+	api.do(http.MethodGet, "/api/rooms", "user1", nil, &rooms)
+	if len(rooms) != 2 || rooms[0].Name != "Bathroom" {
+		t.Errorf("rooms: got %+v", rooms)
+	}
+
+	status := api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Cactus", "location": map[string]any{"roomId": "missing"}}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("unknown room: got %d, want 400", status)
+	}
+	status = api.do(http.MethodPost, "/api/plants", "user2",
+		map[string]any{"name": "Cactus", "location": map[string]any{"roomId": kitchen.ID}}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("other user's room: got %d, want 400", status)
+	}
+}
+
+func TestRooms_MovePlants(t *testing.T) {
+	api := newTestAPI(t)
+	office := api.createRoom("user1", "Office")
+	first := api.createPlant("user1", "Pothos")
+	second := api.createPlant("user1", "Ivy")
+	foreign := api.createPlant("user2", "Aloe")
+
+	var result map[string]int64
+	status := api.do(http.MethodPost, "/api/rooms/"+office.ID+"/plants", "user1",
+		map[string]any{"plantIds": []string{first.ID, second.ID, foreign.ID}}, &result)
+	if status != http.StatusOK || result["moved"] != 2 {
+		t.Fatalf("move: got %d %v", status, result)
+	}
+
+	var inRoom []types.Plant
+	api.do(http.MethodGet, "/api/rooms/"+office.ID+"/plants", "user1", nil, &inRoom)
+	if len(inRoom) != 2 || inRoom[0].Location.Room != "Office" {
+		t.Errorf("room plants: got %+v", inRoom)
+	}
+
+	var aloe types.Plant
+	api.do(http.MethodGet, "/api/plants/"+foreign.ID, "user2", nil, &aloe)
+	if aloe.Location != nil && aloe.Location.RoomID != "" {
+		t.Errorf("foreign plant moved: got %+v", aloe.Location)
+	}
+
+	status = api.do(http.MethodPost, "/api/rooms/"+office.ID+"/plants", "user1",
+		map[string]any{"plantIds": []string{}}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("no plants: got %d, want 400", status)
+	}
+}
+
+func TestRooms_ResidentsReachRoomsByID(t *testing.T) {
+	api := newTestAPI(t)
+	kitchen := api.createRoom("owner", "Kitchen")
+	api.do(http.MethodPut, "/api/safety-profile", "owner",
+		map[string]any{"residents": []map[string]any{{"kind": "dog", "roomIds": []string{kitchen.ID}}}}, nil)
+
+	api.do(http.MethodPost, "/api/plants", "owner",
+		map[string]any{"name": "Pothos", "location": map[string]any{"roomId": kitchen.ID}, "isToxic": true}, nil)
+	api.do(http.MethodPost, "/api/plants", "owner",
+		map[string]any{"name": "Ivy", "location": map[string]any{"room": "Hall"}, "isToxic": true}, nil)
+
+	// A renamed room stays reachable, and a new room with its old name is not
+	api.do(http.MethodPatch, "/api/rooms/"+kitchen.ID, "owner", map[string]any{"name": "Galley"}, nil)
+	api.do(http.MethodPost, "/api/plants", "owner",
+		map[string]any{"name": "Dieffenbachia", "location": map[string]any{"room": "Kitchen"}, "isToxic": true}, nil)
+
+	var risky []types.RiskyPlant
+	api.do(http.MethodGet, "/api/plants/risky", "owner", nil, &risky)
+	if len(risky) != 1 || risky[0].Name != "Pothos" || risky[0].Room != "Galley" {
+		t.Errorf("risky plants: got %+v", risky)
+	}
+}
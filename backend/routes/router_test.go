@@ -0,0 +1,310 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/middlewares"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/services/memstore"
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"github.com/gorilla/mux"
+)
+
+// testAPI is the full API router backed by in-memory stores. Requests
+// authenticate with "Authorization: Bearer <userID>" or a personal API token.
+type testAPI struct {
+	t       *testing.T
+	handler http.Handler
+	store   *memstore.Store
+	objects *memstore.ObjectStore
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	store := memstore.New()
+	objects := memstore.NewObjectStore()
+
+	r := mux.NewRouter()
+	r.Use(middlewares.RequestIDMiddleware)
+	RegisterRoutes(r, store, objects)
+	r.Use(middlewares.AuthMiddleware(userIDAuthenticator{}, services.NewAPITokens(store)))
+
+	return &testAPI{t: t, handler: r, store: store, objects: objects}
+}
+
+// userIDAuthenticator accepts any token and treats it as the user ID
+type userIDAuthenticator struct{}
+
+func (userIDAuthenticator) Authenticate(_ context.Context, token string) (string, error) {
+	return token, nil
+}
+
+// do sends a request as userID and decodes the JSON response into out, if given
+func (api *testAPI) do(method, path, userID string, body any, out any) int {
+	api.t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			api.t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("Authorization", "Bearer "+userID)
+	}
+	rec := httptest.NewRecorder()
+	api.handler.ServeHTTP(rec, req)
+
+	if out != nil && rec.Code < 300 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			api.t.Fatalf("%s %s: decode response %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func (api *testAPI) createPlant(userID, name string) types.Plant {
+	api.t.Helper()
+
+	var plant types.Plant
+	status := api.do(http.MethodPost, "/api/plants", userID, map[string]any{"name": name}, &plant)
+	if status != http.StatusCreated {
+		api.t.Fatalf("create plant: got status %d", status)
+	}
+	return plant
+}
+
+func TestRouter_RequiresAuthentication(t *testing.T) {
+	api := newTestAPI(t)
+
+	for _, path := range []string{"/api/plants", "/api/notifications"} {
+		if status := api.do(http.MethodGet, path, "", nil, nil); status != http.StatusUnauthorized {
+			t.Errorf("GET %s without token: got %d, want 401", path, status)
+		}
+	}
+}
+
+func TestRouter_PlantLifecycle(t *testing.T) {
+	api := newTestAPI(t)
+
+	plant := api.createPlant("user1", "Monstera Deliciosa")
+	if plant.Slug != "monstera-deliciosa" {
+		t.Errorf("slug: got %q", plant.Slug)
+	}
+	if second := api.createPlant("user1", "Monstera Deliciosa"); second.Slug == plant.Slug {
+		t.Errorf("second plant reused slug %q", second.Slug)
+	}
+
+	var plants []types.Plant
+	if status := api.do(http.MethodGet, "/api/plants", "user1", nil, &plants); status != http.StatusOK {
+		t.Fatalf("list: got %d", status)
+	}
+	if len(plants) != 2 {
+		t.Fatalf("list: got %d plants, want 2", len(plants))
+	}
+
+	var bySlug types.Plant
+	if status := api.do(http.MethodGet, "/api/plants/slug/"+plant.Slug, "user1", nil, &bySlug); status != http.StatusOK {
+		t.Fatalf("get by slug: got %d", status)
+	}
+	if bySlug.ID != plant.ID {
+		t.Errorf("get by slug: got plant %s, want %s", bySlug.ID, plant.ID)
+	}
+
+	var updated types.Plant
+	status := api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1",
+		map[string]any{"name": "Monty", "notes": []string{"likes light"}}, &updated)
+	if status != http.StatusOK {
+		t.Fatalf("update: got %d", status)
+	}
+	if updated.Name != "Monty" || len(updated.Notes) != 1 {
+		t.Errorf("update: got name %q notes %v", updated.Name, updated.Notes)
+	}
+
+	status = api.do(http.MethodPost, "/api/plants/water", "user1",
+		map[string]any{"plantIds": []string{plant.ID}}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("water: got %d", status)
+	}
+
+	if status := api.do(http.MethodDelete, "/api/plants/"+plant.ID, "user1", nil, nil); status != http.StatusOK {
+		t.Fatalf("delete: got %d", status)
+	}
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID, "user1", nil, nil); status != http.StatusNotFound {
+		t.Errorf("get after delete: got %d, want 404", status)
+	}
+}
+
+func TestRouter_PlantValidation(t *testing.T) {
+	api := newTestAPI(t)
+
+	status := api.do(http.MethodPost, "/api/plants", "user1", map[string]any{"name": ""}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("create without name: got %d, want 400", status)
+	}
+}
+
+func TestRouter_PlantsAreIsolatedPerUser(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createPlant("user1", "Ficus")
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "user2", nil, &plants)
+	if len(plants) != 0 {
+		t.Errorf("user2 sees %d of user1's plants", len(plants))
+	}
+
+	checks := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, "/api/plants/" + plant.ID, nil},
+		{http.MethodGet, "/api/plants/slug/" + plant.Slug, nil},
+		{http.MethodPatch, "/api/plants/" + plant.ID, map[string]any{"name": "Stolen"}},
+		{http.MethodDelete, "/api/plants/" + plant.ID, nil},
+	}
+	for _, check := range checks {
+		if status := api.do(check.method, check.path, "user2", check.body, nil); status != http.StatusNotFound {
+			t.Errorf("%s %s as user2: got %d, want 404", check.method, check.path, status)
+		}
+	}
+
+	// Watering someone else's plant is silently ignored
+	api.do(http.MethodPost, "/api/plants/water", "user2",
+		map[string]any{"plantIds": []string{plant.ID}}, nil)
+	var own types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "user1", nil, &own)
+	if own.Name != "Ficus" {
+		t.Errorf("user1's plant was modified: %q", own.Name)
+	}
+}
+
+func TestRouter_NotificationConfigAndTokens(t *testing.T) {
+	api := newTestAPI(t)
+
+	var config types.NotificationConfig
+	status := api.do(http.MethodPost, "/api/notifications/tokens", "user1",
+		map[string]any{"token": "fcm-1", "deviceId": "phone", "deviceType": "android"}, &config)
+	if status != http.StatusOK {
+		t.Fatalf("register token: got %d", status)
+	}
+	if len(config.DeviceTokens) != 1 || config.DeviceTokens[0].Token != "fcm-1" {
+		t.Fatalf("register token: got %+v", config.DeviceTokens)
+	}
+
+	// Another user only ever sees their own (default) config
+	var other types.NotificationConfig
+	api.do(http.MethodGet, "/api/notifications", "user2", nil, &other)
+	if other.UserID != "user2" || len(other.DeviceTokens) != 0 {
+		t.Errorf("user2 config: got %+v", other)
+	}
+	if status := api.do(http.MethodDelete, "/api/notifications/tokens/phone", "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("user2 deleting user1's device: got %d, want 404", status)
+	}
+
+	if status := api.do(http.MethodDelete, "/api/notifications/tokens/phone", "user1", nil, &config); status != http.StatusOK {
+		t.Fatalf("delete token: got %d", status)
+	}
+	if len(config.DeviceTokens) != 0 {
+		t.Errorf("delete token: %d tokens left", len(config.DeviceTokens))
+	}
+}
+
+func TestRouter_NotificationConfigRejectsForeignMutedPlants(t *testing.T) {
+	api := newTestAPI(t)
+	foreign := api.createPlant("user1", "Ficus")
+	own := api.createPlant("user2", "Pothos")
+
+	body := map[string]any{
+		"isEnabled":     true,
+		"preferredTime": "08:00",
+		"mutedPlantIds": []string{foreign.ID},
+	}
+	if status := api.do(http.MethodPut, "/api/notifications", "user2", body, nil); status != http.StatusBadRequest {
+		t.Errorf("mute foreign plant: got %d, want 400", status)
+	}
+
+	body["mutedPlantIds"] = []string{own.ID}
+	if status := api.do(http.MethodPut, "/api/notifications", "user2", body, nil); status != http.StatusCreated {
+		t.Errorf("mute own plant: got %d, want 201", status)
+	}
+}
+
+func TestRouter_UploadFlow(t *testing.T) {
+	api := newTestAPI(t)
+
+	var presigned struct {
+		Key string `json:"key"`
+		URL string `json:"url"`
+	}
+	status := api.do(http.MethodPost, "/api/uploads/presign", "user1", map[string]any{
+		"filename":    "leaf.jpg",
+		"contentType": "image/jpeg",
+		"sizeBytes":   1024,
+	}, &presigned)
+	if status != http.StatusOK {
+		t.Fatalf("presign: got %d", status)
+	}
+	if !strings.HasPrefix(presigned.Key, "users/user1/") {
+		t.Fatalf("presign: key %q not scoped to user", presigned.Key)
+	}
+
+	// Registering before the object exists fails
+	register := map[string]any{"key": presigned.Key}
+	if status := api.do(http.MethodPost, "/api/uploads/register", "user1", register, nil); status != http.StatusBadRequest {
+		t.Errorf("register missing object: got %d, want 400", status)
+	}
+
+	api.objects.Put(presigned.Key, 1024, "image/jpeg")
+	if status := api.do(http.MethodPost, "/api/uploads/register", "user1", register, nil); status != http.StatusOK {
+		t.Fatalf("register: got %d", status)
+	}
+	if status := api.do(http.MethodPost, "/api/uploads/register", "user2", register, nil); status != http.StatusBadRequest {
+		t.Errorf("register foreign key: got %d, want 400", status)
+	}
+
+	// Attached photos come back as presigned URLs, foreign keys are dropped
+	plant := api.createPlant("user1", "Calathea")
+	var updated types.Plant
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1", map[string]any{
+		"photoIds": []string{presigned.Key, "users/user2/x_other.jpg"},
+	}, &updated)
+	if len(updated.PhotoURLs) != 1 {
+		t.Errorf("photo URLs: got %v, want 1", updated.PhotoURLs)
+	}
+
+	path := "/api/uploads/" + strings.ReplaceAll(presigned.Key, "/", "%2F")
+	if status := api.do(http.MethodDelete, path, "user1", nil, nil); status != http.StatusOK {
+		t.Fatalf("delete upload: got %d", status)
+	}
+	if api.objects.Has(presigned.Key) {
+		t.Error("object still exists after delete")
+	}
+}
+
+func TestRouter_UploadRejectsOversizedObject(t *testing.T) {
+	api := newTestAPI(t)
+
+	key := "users/user1/big.png"
+	api.objects.Put(key, 10*1024*1024, "image/png")
+	status := api.do(http.MethodPost, "/api/uploads/register", "user1", map[string]any{"key": key}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("register oversized object: got %d, want 400", status)
+	}
+}
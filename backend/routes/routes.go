@@ -9,11 +9,30 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func RegisterRoutes(router *mux.Router, database *services.MongoDB, s3service *services.S3Service) {
-	PlantHandler(router, database, s3service)
-	UploadHandler(router, database, s3service)
-	NotificationHandler(router, database)
-	StatsHandler(router, database)
+// RegisterRoutes registers all API routes on the router
+func RegisterRoutes(router *mux.Router, store services.Store, objects services.ObjectStore) {
+	access := services.NewPlantAccess(store, store, store, store)
+
+	safety := services.NewSafetyChecker(store, store)
+	pests := services.NewPestTracker(store, store, store)
+
+	WateringHandler(router, store, store, access)
+	AnalyticsHandler(router, store, access)
+	PlantHandler(router, store, store, store, access, safety, pests, objects)
+	UploadHandler(router, store, objects)
+	NotificationHandler(router, store, access)
+	StatsHandler(router, store)
+	APITokenHandler(router, store)
+	HouseholdHandler(router, store, store, store)
+	VacationHandler(router, store, store, store)
+	PlantShareHandler(router, store, store, access, objects)
+	ExportHandler(router, store, services.NewExporter(store, objects), objects)
+	AccountHandler(router, services.NewAccountEraser(store, objects))
+	SpeciesHandler(router, store)
+	SafetyHandler(router, store, store)
+	RoomHandler(router, store, store, access, objects)
+	PestHandler(router, store, access)
+	TreatmentHandler(router, store, access)
 }
 
 func getUserID(r *http.Request) (string, bool) {
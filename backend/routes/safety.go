@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// SafetyHandler registers routes for the user's own safety profile, which
+// applies to plants outside a household with a profile
+func SafetyHandler(router *mux.Router, database services.SafetyProfileStore, rooms services.RoomStore) {
+	router.HandleFunc("/api/safety-profile", func(w http.ResponseWriter, r *http.Request) {
+		getSafetyProfile(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/safety-profile", func(w http.ResponseWriter, r *http.Request) {
+		putSafetyProfile(w, r, database, rooms)
+	}).Methods(http.MethodPut, http.MethodOptions)
+}
+
+// getSafetyProfile returns an empty profile if the user has not set one
+func getSafetyProfile(w http.ResponseWriter, r *http.Request, db services.SafetyProfileStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := db.GetSafetyProfile(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if profile == nil {
+		profile = &types.SafetyProfile{Residents: []types.Resident{}}
+	}
+	util.RespondJSON(w, http.StatusOK, profile)
+}
+
+func putSafetyProfile(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.SafetyProfileStore,
+	rooms services.RoomStore,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	profile, ok := decodeSafetyProfile(w, r, rooms, []string{userID})
+	if !ok {
+		return
+	}
+	profile.UserID = userID
+	if err := db.PutSafetyProfile(r.Context(), profile); err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, profile)
+}
+
+// decodeSafetyProfile reads and validates an UpdateSafetyProfileRequest,
+// responding with 400 if it is invalid. Residents may reach the rooms of
+// owners, the users whose plants the profile applies to.
+func decodeSafetyProfile(
+	w http.ResponseWriter,
+	r *http.Request,
+	rooms services.RoomStore,
+	owners []string,
+) (types.SafetyProfile, bool) {
+	var req types.UpdateSafetyProfileRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return types.SafetyProfile{}, false
+	}
+	if errors := validation.ValidateSafetyProfileRequest(req); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return types.SafetyProfile{}, false
+	}
+
+	known := make(map[string]bool)
+	for _, owner := range owners {
+		ownRooms, err := rooms.GetRooms(r.Context(), owner)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return types.SafetyProfile{}, false
+		}
+		for _, room := range ownRooms {
+			known[room.ID] = true
+		}
+	}
+
+	residents := make([]types.Resident, 0, len(req.Residents))
+	for _, resident := range req.Residents {
+		resident.Name = strings.TrimSpace(resident.Name)
+		roomIDs := make([]string, 0, len(resident.RoomIDs))
+		for _, id := range resident.RoomIDs {
+			if !known[id] {
+				util.BadRequest(w, "Validation failed", []types.ValidationError{
+					{Field: "residents.roomIds", Message: "Unknown room"},
+				})
+				return types.SafetyProfile{}, false
+			}
+			if !slices.Contains(roomIDs, id) {
+				roomIDs = append(roomIDs, id)
+			}
+		}
+		resident.RoomIDs = nil
+		if len(roomIDs) > 0 {
+			resident.RoomIDs = roomIDs
+		}
+		residents = append(residents, resident)
+	}
+	return types.SafetyProfile{Residents: residents, UpdatedAt: time.Now()}, true
+}
@@ -0,0 +1,170 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestSafety_WarnsWhenToxicPlantIsWithinReach(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedSpecies()
+	nursery := api.createRoom("user1", "Nursery")
+
+	status := api.do(http.MethodPut, "/api/safety-profile", "user1", map[string]any{
+		"residents": []map[string]any{
+			{"kind": "cat", "name": "Luna"},
+			{"kind": "child", "name": "Mia", "roomIds": []string{nursery.ID}},
+		},
+	}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("put profile: got %d", status)
+	}
+
+	// Toxicity comes from the species even without autofill
+	var plant types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":      "Monstera",
+		"speciesId": "monstera-deliciosa",
+		"location":  map[string]any{"room": "Living room"},
+	}, &plant)
+	if plant.Toxicity == nil || plant.Toxicity.Cats != types.ToxicityModerate || !plant.IsToxic {
+		t.Fatalf("toxicity: got %+v toxic %v", plant.Toxicity, plant.IsToxic)
+	}
+	if len(plant.SafetyWarnings) != 1 || plant.SafetyWarnings[0].Resident != types.ResidentCat ||
+		plant.SafetyWarnings[0].Names[0] != "Luna" {
+		t.Fatalf("create warnings: got %+v", plant.SafetyWarnings)
+	}
+
+	var moved types.Plant
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1",
+		map[string]any{"location": map[string]any{"room": "nursery"}}, &moved)
+	if len(moved.SafetyWarnings) != 2 || moved.SafetyWarnings[0].Severity != types.ToxicityModerate ||
+		moved.SafetyWarnings[1].Resident != types.ResidentChild {
+		t.Errorf("update warnings: got %+v", moved.SafetyWarnings)
+	}
+
+	var safe types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Spider", "speciesId": "chlorophytum-comosum"}, &safe)
+	if safe.IsToxic || len(safe.SafetyWarnings) != 0 {
+		t.Errorf("non-toxic plant: got toxic %v warnings %+v", safe.IsToxic, safe.SafetyWarnings)
+	}
+
+	// Toxicity sent with the plant wins over the species
+	var custom types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":      "Dumb cane",
+		"speciesId": "monstera-deliciosa",
+		"toxicity":  map[string]any{"cats": "severe", "dogs": "none", "humans": "none"},
+	}, &custom)
+	if custom.Toxicity.Cats != types.ToxicitySevere || custom.Toxicity.Humans != types.ToxicityNone {
+		t.Errorf("custom toxicity: got %+v", custom.Toxicity)
+	}
+
+	status = api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Bad", "toxicity": map[string]any{"cats": "deadly"}}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("invalid toxicity: got %d, want 400", status)
+	}
+}
+
+func TestSafety_RiskyPlantsMostSevereFirst(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedSpecies()
+	kitchen := api.createRoom("user1", "Kitchen")
+	api.do(http.MethodPut, "/api/safety-profile", "user1", map[string]any{
+		"residents": []map[string]any{{"kind": "dog", "roomIds": []string{kitchen.ID}}},
+	}, nil)
+
+	for _, plant := range []map[string]any{
+		{"name": "Pothos", "speciesId": "epipremnum-aureum", "location": map[string]any{"room": "Kitchen"}},
+		{"name": "Dieffenbachia", "speciesId": "dieffenbachia-seguine", "location": map[string]any{"room": "Kitchen"}},
+		{"name": "Ivy", "speciesId": "hedera-helix", "location": map[string]any{"room": "Office"}},
+		{"name": "Unknown", "isToxic": true},
+		{"name": "Fern", "speciesId": "nephrolepis-exaltata"},
+	} {
+		if status := api.do(http.MethodPost, "/api/plants", "user1", plant, nil); status != http.StatusCreated {
+			t.Fatalf("create %s: got %d", plant["name"], status)
+		}
+	}
+
+	var risky []types.RiskyPlant
+	if status := api.do(http.MethodGet, "/api/plants/risky", "user1", nil, &risky); status != http.StatusOK {
+		t.Fatalf("risky: got %d", status)
+	}
+	var names []string
+	for _, plant := range risky {
+		names = append(names, plant.Name+":"+string(plant.Severity))
+	}
+	want := []string{"Dieffenbachia:severe", "Pothos:moderate", "Unknown:unknown"}
+	if len(names) != len(want) {
+		t.Fatalf("risky plants: got %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("risky plants: got %v, want %v", names, want)
+		}
+	}
+	if len(risky[0].Symptoms) == 0 || risky[2].Symptoms == nil {
+		t.Errorf("symptoms: got %v and %v", risky[0].Symptoms, risky[2].Symptoms)
+	}
+
+	var none []types.RiskyPlant
+	api.do(http.MethodGet, "/api/plants/risky", "user2", nil, &none)
+	if len(none) != 0 {
+		t.Errorf("user without profile: got %+v", none)
+	}
+}
+
+func TestSafety_HouseholdProfileAppliesToSharedPlants(t *testing.T) {
+	api := newTestAPI(t)
+	household, plant := api.sharedHousehold("grandma", types.RoleViewer)
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "owner",
+		map[string]any{"toxicity": map[string]any{"cats": "mild", "dogs": "severe", "humans": "none"}}, nil)
+
+	// The owner's own profile has no dog, the household's has
+	api.do(http.MethodPut, "/api/safety-profile", "owner",
+		map[string]any{"residents": []map[string]any{{"kind": "cat"}}}, nil)
+	path := "/api/households/" + household.ID + "/safety-profile"
+	body := map[string]any{"residents": []map[string]any{{"kind": "dog", "name": "Rex"}}}
+	if status := api.do(http.MethodPut, path, "grandma", body, nil); status != http.StatusForbidden {
+		t.Errorf("viewer sets profile: got %d, want 403", status)
+	}
+	var updated types.Household
+	if status := api.do(http.MethodPut, path, "owner", body, &updated); status != http.StatusOK {
+		t.Fatalf("owner sets profile: got %d", status)
+	}
+	if updated.Profile == nil || len(updated.Profile.Residents) != 1 {
+		t.Errorf("household profile: got %+v", updated.Profile)
+	}
+
+	var risky []types.RiskyPlant
+	api.do(http.MethodGet, "/api/plants/risky", "grandma", nil, &risky)
+	if len(risky) != 1 || risky[0].Severity != types.ToxicitySevere || risky[0].Risks[0].Resident != types.ResidentDog {
+		t.Errorf("shared plant risks: got %+v", risky)
+	}
+
+	status := api.do(http.MethodPut, "/api/safety-profile", "owner",
+		map[string]any{"residents": []map[string]any{{"kind": "hamster"}}}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("unknown resident kind: got %d, want 400", status)
+	}
+
+	// Residents reach rooms of household members, not of other users
+	memberRoom := api.createRoom("grandma", "Porch")
+	strangerRoom := api.createRoom("stranger", "Porch")
+	body = map[string]any{"residents": []map[string]any{{"kind": "dog", "roomIds": []string{memberRoom.ID}}}}
+	if status := api.do(http.MethodPut, path, "owner", body, nil); status != http.StatusOK {
+		t.Errorf("member's room: got %d, want 200", status)
+	}
+	body = map[string]any{"residents": []map[string]any{{"kind": "dog", "roomIds": []string{strangerRoom.ID}}}}
+	if status := api.do(http.MethodPut, path, "owner", body, nil); status != http.StatusBadRequest {
+		t.Errorf("stranger's room: got %d, want 400", status)
+	}
+	status = api.do(http.MethodPut, "/api/safety-profile", "owner", body, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("other user's room in own profile: got %d, want 400", status)
+	}
+}
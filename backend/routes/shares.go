@@ -0,0 +1,219 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/metrics"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// PlantShareHandler registers routes for managing public plant share links
+// and the public, unauthenticated plant page
+func PlantShareHandler(
+	router *mux.Router,
+	database services.PlantShareStore,
+	plants services.PlantStore,
+	access *services.PlantAccess,
+	objects services.ObjectStore,
+) {
+	// Public pages have no user, so they are limited per IP only
+	publicLimiter := services.NewRateLimiter()
+
+	router.HandleFunc("/api/plants/{id}/shares", func(w http.ResponseWriter, r *http.Request) {
+		getPlantShares(w, r, database, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/shares", func(w http.ResponseWriter, r *http.Request) {
+		createPlantShare(w, r, database, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/shares/{shareId}", func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		deletePlantShare(w, r, database, access, vars["id"], vars["shareId"])
+	}).Methods(http.MethodDelete, http.MethodOptions)
+
+	router.HandleFunc(services.PublicSharePath+"{token}", func(w http.ResponseWriter, r *http.Request) {
+		if publicLimiter.IsRateLimited("public-share", getRealIP(r)) {
+			metrics.RateLimitRejections.Inc()
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		getSharedPlant(w, r, database, plants, objects, mux.Vars(r)["token"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+func getPlantShares(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantShareStore,
+	access *services.PlantAccess,
+	plantID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if _, ok := editablePlant(w, r, access, userID, plantID); !ok {
+		return
+	}
+
+	shares, err := db.GetPlantShares(r.Context(), plantID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for i := range shares {
+		shares[i].Link = constants.ShareLinkBaseURL + shares[i].Token
+	}
+	util.RespondJSON(w, http.StatusOK, shares)
+}
+
+func createPlantShare(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantShareStore,
+	access *services.PlantAccess,
+	plantID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	plant, ok := editablePlant(w, r, access, userID, plantID)
+	if !ok {
+		return
+	}
+
+	var req types.CreatePlantShareRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errors := validation.ValidateCreatePlantShareRequest(req, *plant); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+
+	existing, err := db.GetPlantShares(r.Context(), plantID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if len(existing) >= validation.MaxSharesPerPlant {
+		util.RespondJSON(w, http.StatusConflict, map[string]string{
+			"error": "Share link limit reached for this plant",
+		})
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	token, err := services.GenerateShareToken()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	photoIDs := req.PhotoIDs
+	if photoIDs == nil {
+		photoIDs = []string{}
+	}
+	share, err := db.CreatePlantShare(r.Context(), types.PlantShare{
+		ID:              id,
+		PlantID:         plant.ID,
+		OwnerID:         plant.UserID,
+		CreatedBy:       userID,
+		Token:           token,
+		PhotoIDs:        photoIDs,
+		ShowLocation:    req.ShowLocation,
+		ShowNotes:       req.ShowNotes,
+		ShowPestHistory: req.ShowPestHistory,
+		CreatedAt:       time.Now(),
+	})
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	share.Link = constants.ShareLinkBaseURL + share.Token
+	util.RespondJSON(w, http.StatusCreated, share)
+}
+
+// deletePlantShare revokes a share link; its token stops working immediately
+func deletePlantShare(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantShareStore,
+	access *services.PlantAccess,
+	plantID, shareID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if _, ok := editablePlant(w, r, access, userID, plantID); !ok {
+		return
+	}
+
+	deleted, err := db.DeletePlantShare(r.Context(), plantID, shareID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !deleted {
+		util.NotFound(w)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// getSharedPlant serves the public view of a shared plant. Unknown tokens and
+// deleted plants both respond with 404.
+func getSharedPlant(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantShareStore,
+	plants services.PlantStore,
+	objects services.ObjectStore,
+	token string,
+) {
+	share, err := db.GetPlantShareByToken(r.Context(), token)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if share == nil {
+		util.NotFound(w)
+		return
+	}
+
+	plant, err := plants.GetPlantByID(r.Context(), share.PlantID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil || plant.UserID != share.OwnerID {
+		util.NotFound(w)
+		return
+	}
+
+	view := services.PublicPlantView(*plant, *share)
+	view.PhotoURLs = resolvePhotoURLs(r.Context(), objects, services.SharedPhotoIDs(*plant, *share), plant.UserID)
+
+	// Presigned photo URLs expire and revoked links must stop working
+	w.Header().Set("Cache-Control", "no-store")
+	util.RespondJSON(w, http.StatusOK, view)
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestShares_PublicViewHidesPrivateFields(t *testing.T) {
+	api := newTestAPI(t)
+
+	var plant types.Plant
+	status := api.do(http.MethodPost, "/api/plants", "owner", map[string]any{
+		"name":     "Monstera",
+		"species":  "Monstera deliciosa",
+		"isToxic":  true,
+		"location": map[string]any{"room": "Bedroom"},
+		"notes":    []string{"Gift from grandma"},
+		"pestHistory": []map[string]any{
+			{"id": "i1", "pest": "Thrips", "status": "Resolved", "treatment": "Neem oil"},
+		},
+	}, &plant)
+	if status != http.StatusCreated {
+		t.Fatalf("create plant: got %d", status)
+	}
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "owner", map[string]any{
+		"photoIds": []string{"users/owner/a.jpg", "users/owner/b.jpg"},
+	}, nil)
+
+	var share types.PlantShare
+	status = api.do(http.MethodPost, "/api/plants/"+plant.ID+"/shares", "owner",
+		map[string]any{"photoIds": []string{"users/owner/b.jpg"}}, &share)
+	if status != http.StatusCreated {
+		t.Fatalf("create share: got %d", status)
+	}
+	if len(share.Token) < 40 || share.Link == "" {
+		t.Errorf("share: got token %q link %q", share.Token, share.Link)
+	}
+
+	var view map[string]any
+	if status := api.do(http.MethodGet, services.PublicSharePath+share.Token, "", nil, &view); status != http.StatusOK {
+		t.Fatalf("public view: got %d", status)
+	}
+	if view["name"] != "Monstera" || view["isToxic"] != true {
+		t.Errorf("public view: got %v", view)
+	}
+	for _, field := range []string{"location", "notes", "pestHistory", "userId", "id"} {
+		if _, ok := view[field]; ok {
+			t.Errorf("public view exposes %s", field)
+		}
+	}
+	if urls, _ := view["photoUrls"].([]any); len(urls) != 1 {
+		t.Errorf("photo URLs: got %v, want the one selected photo", view["photoUrls"])
+	}
+
+	// Revoked links stop working
+	if status := api.do(http.MethodDelete, "/api/plants/"+plant.ID+"/shares/"+share.ID, "owner",
+		nil, nil); status != http.StatusOK {
+		t.Fatalf("revoke: got %d", status)
+	}
+	if status := api.do(http.MethodGet, services.PublicSharePath+share.Token, "", nil, nil); status != http.StatusNotFound {
+		t.Errorf("revoked link: got %d, want 404", status)
+	}
+}
+
+func TestShares_OptInAndPermissions(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createPlant("owner", "Fern")
+
+	if status := api.do(http.MethodPost, "/api/plants/"+plant.ID+"/shares", "stranger",
+		map[string]any{}, nil); status != http.StatusNotFound {
+		t.Errorf("stranger share: got %d, want 404", status)
+	}
+	if status := api.do(http.MethodPost, "/api/plants/"+plant.ID+"/shares", "owner",
+		map[string]any{"photoIds": []string{"users/other/x.jpg"}}, nil); status != http.StatusBadRequest {
+		t.Errorf("foreign photo: got %d, want 400", status)
+	}
+
+	var share types.PlantShare
+	api.do(http.MethodPost, "/api/plants/"+plant.ID+"/shares", "owner",
+		map[string]any{"showLocation": true}, &share)
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "owner",
+		map[string]any{"location": map[string]any{"room": "Kitchen"}}, nil)
+
+	var view types.PublicPlant
+	api.do(http.MethodGet, services.PublicSharePath+share.Token, "", nil, &view)
+	if view.Location == nil || view.Location.Room != "Kitchen" {
+		t.Errorf("opted-in location: got %+v", view.Location)
+	}
+
+	if status := api.do(http.MethodGet, services.PublicSharePath+"unknown", "", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown token: got %d, want 404", status)
+	}
+}
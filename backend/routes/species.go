@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultSpeciesResults is the number of typeahead suggestions without ?limit
+const defaultSpeciesResults = 10
+
+// SpeciesHandler registers routes for searching the species catalog
+func SpeciesHandler(router *mux.Router, database services.SpeciesStore) {
+	router.HandleFunc("/api/species", func(w http.ResponseWriter, r *http.Request) {
+		searchSpecies(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/species/{id}", func(w http.ResponseWriter, r *http.Request) {
+		getSpecies(w, r, database, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+// searchSpecies matches ?q= against the start of any word of the scientific
+// and common names, in all languages
+func searchSpecies(w http.ResponseWriter, r *http.Request, db services.SpeciesStore) {
+	if _, ok := getUserID(r); !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit := defaultSpeciesResults
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > services.MaxSpeciesResults {
+			util.BadRequest(w, fmt.Sprintf("Limit must be between 1 and %d", services.MaxSpeciesResults), nil)
+			return
+		}
+		limit = n
+	}
+
+	species, err := db.SearchSpecies(r.Context(), r.URL.Query().Get("q"), limit)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, species)
+}
+
+func getSpecies(w http.ResponseWriter, r *http.Request, db services.SpeciesStore, id string) {
+	if _, ok := getUserID(r); !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	species, err := db.GetSpecies(r.Context(), id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if species == nil {
+		util.NotFound(w)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, species)
+}
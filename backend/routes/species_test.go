@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/catalog"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// seedSpecies loads the bundled species catalog into the test store
+func (api *testAPI) seedSpecies() {
+	api.t.Helper()
+
+	species, err := catalog.Species()
+	if err != nil {
+		api.t.Fatal(err)
+	}
+	for _, entry := range species {
+		if err := api.store.UpsertSpecies(context.Background(), entry); err != nil {
+			api.t.Fatal(err)
+		}
+	}
+}
+
+func TestSpecies_SearchMatchesNamesInAllLanguages(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedSpecies()
+
+	checks := map[string]string{
+		"monst":   "monstera-deliciosa",
+		"CHEESE":  "monstera-deliciosa",
+		"fenster": "monstera-deliciosa",
+		"lengua":  "dracaena-trifasciata",
+	}
+	for query, want := range checks {
+		var species []types.Species
+		if status := api.do(http.MethodGet, "/api/species?q="+query, "user1", nil, &species); status != http.StatusOK {
+			t.Fatalf("search %q: got %d", query, status)
+		}
+		if len(species) == 0 || species[0].ID != want {
+			t.Errorf("search %q: got %+v, want %s first", query, species, want)
+		}
+	}
+
+	// Only word starts match, not the middle of a word
+	var species []types.Species
+	api.do(http.MethodGet, "/api/species?q=stera", "user1", nil, &species)
+	if len(species) != 0 {
+		t.Errorf("search mid-word: got %d results", len(species))
+	}
+
+	api.do(http.MethodGet, "/api/species?limit=3", "user1", nil, &species)
+	if len(species) != 3 {
+		t.Errorf("limit: got %d results", len(species))
+	}
+	if status := api.do(http.MethodGet, "/api/species?limit=500", "user1", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("limit too high: got %d, want 400", status)
+	}
+	if status := api.do(http.MethodGet, "/api/species/unknown", "user1", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown species: got %d, want 404", status)
+	}
+}
+
+func TestSpecies_AutofillFillsOnlyEmptySections(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedSpecies()
+
+	var plant types.Plant
+	status := api.do(http.MethodPost, "/api/plants", "user1", map[string]any{
+		"name":         "Monty",
+		"speciesId":    "monstera-deliciosa",
+		"autofillCare": true,
+		"watering":     map[string]any{"intervalDays": 3, "method": "Bottom", "waterType": "Rain"},
+	}, &plant)
+	if status != http.StatusCreated {
+		t.Fatalf("create: got %d", status)
+	}
+	if plant.SpeciesID != "monstera-deliciosa" || plant.Species != "Monstera deliciosa" || !plant.IsToxic {
+		t.Errorf("species: got %q %q toxic %v", plant.SpeciesID, plant.Species, plant.IsToxic)
+	}
+	if plant.Watering.IntervalDays != 3 || plant.Watering.Method != types.MethodBottomWatering {
+		t.Errorf("watering was overwritten: %+v", plant.Watering)
+	}
+	if plant.Fertilizing == nil || plant.Fertilizing.IntervalDays != 14 || plant.Fertilizing.NPKRatio != "3-1-2" {
+		t.Errorf("fertilizing: got %+v", plant.Fertilizing)
+	}
+	if plant.Humidity == nil || !plant.Humidity.RequiresMisting || plant.Humidity.TargetHumidityPct != 60 {
+		t.Errorf("humidity: got %+v", plant.Humidity)
+	}
+	if plant.Seasonality == nil || !plant.Seasonality.WinterRestPeriod || plant.Sunlight == nil {
+		t.Errorf("seasonality %+v sunlight %v", plant.Seasonality, plant.Sunlight)
+	}
+
+	// Linking a species without autofill keeps the plant as sent
+	var linked types.Plant
+	api.do(http.MethodPost, "/api/plants", "user1",
+		map[string]any{"name": "Spider", "speciesId": "chlorophytum-comosum"}, &linked)
+	if linked.SpeciesID != "chlorophytum-comosum" || linked.Species != "" || linked.Humidity != nil {
+		t.Errorf("link without autofill: got %+v", linked)
+	}
+
+	checks := []map[string]any{
+		{"name": "Ghost", "speciesId": "no-such-species"},
+		{"name": "Ghost", "autofillCare": true},
+	}
+	for _, body := range checks {
+		if status := api.do(http.MethodPost, "/api/plants", "user1", body, nil); status != http.StatusBadRequest {
+			t.Errorf("create %v: got %d, want 400", body, status)
+		}
+	}
+	status = api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1",
+		map[string]any{"speciesId": "no-such-species"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("update to unknown species: got %d, want 400", status)
+	}
+}
@@ -2,7 +2,7 @@ package routes
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -19,7 +19,7 @@ type StatsResponse struct {
 }
 
 // StatsHandler registers stats-related routes
-func StatsHandler(router *mux.Router, database *services.MongoDB) {
+func StatsHandler(router *mux.Router, database services.PlantStore) {
 	router.HandleFunc("/api/stats", func(w http.ResponseWriter, r *http.Request) {
 		getStats(w, r, database)
 	}).Methods(http.MethodGet, http.MethodOptions)
@@ -30,14 +30,12 @@ var (
 	cachedStatsTimestamp time.Time
 )
 
-func getStats(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
-	log.Printf("Getting stats")
-
+func getStats(w http.ResponseWriter, r *http.Request, db services.PlantStore) {
 	if cachedStats != nil && time.Since(cachedStatsTimestamp) < 5*time.Minute {
-		log.Printf("Returning cached stats")
+		slog.DebugContext(r.Context(), "returning cached stats")
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(cachedStats); err != nil {
-			log.Printf("Failed to encode cached stats: %v", err)
+			slog.ErrorContext(r.Context(), "failed to encode cached stats", "error", err)
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 		return
@@ -48,7 +46,7 @@ func getStats(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
 	// Get count of unique users
 	users, err := db.CountActiveUsers(ctx)
 	if err != nil {
-		log.Printf("Failed to count users: %v", err)
+		slog.ErrorContext(ctx, "failed to count users", "error", err)
 		http.Error(w, "Failed to retrieve stats", http.StatusInternalServerError)
 		return
 	}
@@ -56,7 +54,7 @@ func getStats(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
 	// Get count of plants
 	plants, err := db.CountPlants(ctx)
 	if err != nil {
-		log.Printf("Failed to count plants: %v", err)
+		slog.ErrorContext(ctx, "failed to count plants", "error", err)
 		http.Error(w, "Failed to retrieve stats", http.StatusInternalServerError)
 		return
 	}
@@ -69,7 +67,7 @@ func getStats(w http.ResponseWriter, r *http.Request, db *services.MongoDB) {
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(stats); err != nil {
-		log.Printf("Failed to encode stats: %v", err)
+		slog.ErrorContext(ctx, "failed to encode stats", "error", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 	cachedStats = &stats
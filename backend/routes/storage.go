@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/services"
+
+	"github.com/gorilla/mux"
+)
+
+// StorageHandler serves objects of the local storage backend. Requests are
+// authorized by the URL signature from PresignPutURL/PresignGetURL instead of
+// a user token, like presigned S3 URLs.
+func StorageHandler(router *mux.Router, storage *services.LocalStorage) {
+	path := services.LocalStoragePath + "{key:.+}"
+
+	router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		putObject(w, r, storage)
+	}).Methods(http.MethodPut, http.MethodOptions)
+
+	router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		getObject(w, r, storage)
+	}).Methods(http.MethodGet, http.MethodHead)
+}
+
+func putObject(w http.ResponseWriter, r *http.Request, storage *services.LocalStorage) {
+	key := mux.Vars(r)["key"]
+	query := r.URL.Query()
+	contentType := r.Header.Get("Content-Type")
+
+	err := storage.Verify(services.StorageOpPut, key, contentType, query.Get("exp"), query.Get("sig"))
+	if err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if !constants.AllowedImageContentTypes[contentType] {
+		http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.ContentLength > constants.MaxUploadBytes {
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	_, err = storage.Write(key, contentType, r.Body)
+	switch {
+	case errors.Is(err, services.ErrObjectTooLarge):
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrContentTypeMismatch):
+		http.Error(w, "Content does not match content type", http.StatusUnsupportedMediaType)
+	case errors.Is(err, services.ErrInvalidObjectKey):
+		http.Error(w, "Invalid key", http.StatusBadRequest)
+	case err != nil:
+		slog.ErrorContext(r.Context(), "failed to store object", "error", err)
+		http.Error(w, "Failed to store object", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func getObject(w http.ResponseWriter, r *http.Request, storage *services.LocalStorage) {
+	key := mux.Vars(r)["key"]
+	query := r.URL.Query()
+
+	err := storage.Verify(services.StorageOpGet, key, "", query.Get("exp"), query.Get("sig"))
+	if err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	file, info, err := storage.Open(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
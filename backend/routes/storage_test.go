@@ -0,0 +1,147 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/services"
+
+	"github.com/gorilla/mux"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newStorageRouter(t *testing.T) (*services.LocalStorage, http.Handler) {
+	t.Helper()
+
+	storage, err := services.NewLocalStorage(t.TempDir(), "http://api.test", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := mux.NewRouter()
+	StorageHandler(r, storage)
+	return storage, r
+}
+
+func storageRequest(
+	t *testing.T,
+	handler http.Handler,
+	method, rawURL, contentType string,
+	body []byte,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, u.RequestURI(), bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLocalStorage_UploadAndDownload(t *testing.T) {
+	storage, handler := newStorageRouter(t)
+	ctx := context.Background()
+	key := services.GenerateObjectKey("user1", "my leaf.png")
+
+	putURL, _, err := storage.PresignPutURL(ctx, key, "image/png", "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(putURL, "http://api.test/storage/users/user1/") {
+		t.Fatalf("unexpected URL %s", putURL)
+	}
+	if rec := storageRequest(t, handler, http.MethodPut, putURL, "image/png", pngHeader); rec.Code != http.StatusOK {
+		t.Fatalf("put: got %d %s", rec.Code, rec.Body.String())
+	}
+
+	size, contentType, err := storage.HeadObjectInfo(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(pngHeader)) || contentType != "image/png" {
+		t.Errorf("head: got %d bytes of %s", size, contentType)
+	}
+
+	getURL, err := storage.PresignGetURL(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := storageRequest(t, handler, http.MethodGet, getURL, "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get: got %d", rec.Code)
+	}
+	if body, _ := io.ReadAll(rec.Body); !bytes.Equal(body, pngHeader) {
+		t.Errorf("get: body mismatch")
+	}
+
+	if err := storage.DeleteObject(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if rec := storageRequest(t, handler, http.MethodGet, getURL, "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete: got %d, want 404", rec.Code)
+	}
+}
+
+func TestLocalStorage_RejectsInvalidUploads(t *testing.T) {
+	storage, handler := newStorageRouter(t)
+	ctx := context.Background()
+	key := "users/user1/abc_leaf.png"
+
+	putURL, _, err := storage.PresignPutURL(ctx, key, "image/png", "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	getURL, _ := storage.PresignGetURL(ctx, key)
+	tooLarge := append(append([]byte{}, pngHeader...), make([]byte, constants.MaxUploadBytes)...)
+
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        []byte
+		want        int
+	}{
+		{"tampered signature", strings.Replace(putURL, "sig=", "sig=0", 1), "image/png", pngHeader, http.StatusForbidden},
+		{"other key", strings.Replace(putURL, "abc_leaf", "abd_leaf", 1), "image/png", pngHeader, http.StatusForbidden},
+		{"get URL used for put", getURL, "image/png", pngHeader, http.StatusForbidden},
+		{"different content type", putURL, "image/jpeg", pngHeader, http.StatusForbidden},
+		{"content not an image", putURL, "image/png", []byte("<html></html>"), http.StatusUnsupportedMediaType},
+		{"too large", putURL, "image/png", tooLarge, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := storageRequest(t, handler, http.MethodPut, tt.url, tt.contentType, tt.body)
+			if rec.Code != tt.want {
+				t.Errorf("got %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := storage.HeadObjectInfo(ctx, key); err == nil {
+		t.Error("rejected upload was stored")
+	}
+}
+
+func TestLocalStorage_RejectsKeysOutsideUserPrefix(t *testing.T) {
+	storage, _ := newStorageRouter(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"../secret", "users/../../etc/passwd", "other/file.png", "users/u1//x.png"} {
+		if _, _, err := storage.PresignPutURL(ctx, key, "image/png", "u1"); err == nil {
+			t.Errorf("key %q was accepted", key)
+		}
+	}
+}
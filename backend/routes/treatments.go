@@ -0,0 +1,210 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// logTreatmentAttempts is how often an application is retried when the plan
+// changed while it was being logged
+const logTreatmentAttempts = 3
+
+// TreatmentHandler registers routes for the treatment plans of pest
+// infections
+func TreatmentHandler(router *mux.Router, database services.TreatmentPlanStore, access *services.PlantAccess) {
+	router.HandleFunc("/api/plants/{id}/treatments", func(w http.ResponseWriter, r *http.Request) {
+		getTreatmentPlans(w, r, database, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/treatments", func(w http.ResponseWriter, r *http.Request) {
+		createTreatmentPlan(w, r, database, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc(
+		"/api/plants/{id}/treatments/{planId}/applications",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			logTreatment(w, r, database, access, vars["id"], vars["planId"])
+		},
+	).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// getTreatmentPlans lists the treatment plans of a plant, newest first
+func getTreatmentPlans(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.TreatmentPlanStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return
+	}
+	plans, err := db.GetTreatmentPlans(r.Context(), plant.ID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, plans)
+}
+
+// createTreatmentPlan starts a plan against an infection of the plant that
+// is not resolved. An infection has at most one active plan.
+func createTreatmentPlan(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.TreatmentPlanStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.CreateTreatmentPlanRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errs := validation.ValidateCreateTreatmentPlanRequest(req); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	plant, ok := editablePlant(w, r, access, userID, id)
+	if !ok {
+		return
+	}
+	infection := services.FindInfection(*plant, req.InfectionID)
+	if infection == nil {
+		util.BadRequest(w, "Validation failed", []types.ValidationError{
+			{Field: "infectionId", Message: "Plant has no pest infection with this ID"},
+		})
+		return
+	}
+	if infection.Status == types.PestStatusResolved {
+		util.BadRequest(w, "Infection is resolved", nil)
+		return
+	}
+
+	plans, err := db.GetTreatmentPlans(r.Context(), plant.ID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for _, plan := range plans {
+		if plan.InfectionID == infection.ID && plan.Status == types.TreatmentActive {
+			util.RespondJSON(w, http.StatusConflict, map[string]string{
+				"error": "Infection already has an active treatment plan",
+			})
+			return
+		}
+	}
+
+	plan, err := services.NewTreatmentPlan(*plant, *infection, req, time.Now())
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	created, err := db.CreateTreatmentPlan(r.Context(), plan)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusCreated, created)
+}
+
+// logTreatment logs an application of a treatment plan. Anyone who can care
+// for the plant can log one.
+func logTreatment(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.TreatmentPlanStore,
+	access *services.PlantAccess,
+	id, planID string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.LogTreatmentRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	now := time.Now()
+	if errs := validation.ValidateLogTreatmentRequest(req, now); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return
+	}
+	if !plant.Role.CanCare() {
+		util.Forbidden(w)
+		return
+	}
+	at := now
+	if req.At != nil {
+		at = *req.At
+	}
+	// The plan is only saved if nobody logged an application or ended it
+	// since it was read, so reread it and try again if it was not
+	for range logTreatmentAttempts {
+		plan, err := db.GetTreatmentPlan(r.Context(), planID)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+		if plan == nil || plan.PlantID != plant.ID {
+			util.NotFound(w)
+			return
+		}
+		if plan.Status != types.TreatmentActive {
+			util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "Treatment plan has ended"})
+			return
+		}
+
+		services.ApplyTreatment(plan, userID, at, req.Notes, now)
+		saved, err := db.LogTreatmentApplication(r.Context(), *plan)
+		if err != nil {
+			util.ServerError(w, r, err)
+			return
+		}
+		if saved {
+			util.RespondJSON(w, http.StatusOK, plan)
+			return
+		}
+	}
+	util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "Treatment plan was changed, try again"})
+}
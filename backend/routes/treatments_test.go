@@ -0,0 +1,141 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func (api *testAPI) infestedPlant(userID, name string, status types.PestStatus) types.Plant {
+	api.t.Helper()
+	var plant types.Plant
+	if code := api.do(http.MethodPost, "/api/plants", userID, map[string]any{
+		"name":        name,
+		"pestHistory": pestHistory("thrips", types.PestThrips, status),
+	}, &plant); code != http.StatusCreated {
+		api.t.Fatalf("create plant %s: got %d", name, code)
+	}
+	return plant
+}
+
+func TestTreatments_LogApplicationsUntilDone(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.infestedPlant("user1", "Ficus", types.PestStatusActive)
+	path := "/api/plants/" + plant.ID + "/treatments"
+	req := map[string]any{
+		"infectionId":  "thrips",
+		"product":      "Spinosad",
+		"method":       types.TreatmentSpray,
+		"intervalDays": 5,
+		"repetitions":  2,
+	}
+
+	var plan types.TreatmentPlan
+	if status := api.do(http.MethodPost, path, "user1", req, &plan); status != http.StatusCreated {
+		t.Fatalf("create: got %d", status)
+	}
+	if plan.Status != types.TreatmentActive || plan.Pest != types.PestThrips || plan.NextDueAt == nil {
+		t.Errorf("plan: got %+v", plan)
+	}
+	if status := api.do(http.MethodPost, path, "user1", req, nil); status != http.StatusConflict {
+		t.Errorf("second plan for the infection: got %d, want 409", status)
+	}
+
+	var applied types.TreatmentPlan
+	api.do(http.MethodPost, path+"/"+plan.ID+"/applications", "user1",
+		map[string]any{"at": "2026-05-01T10:00:00Z", "notes": "Undersides too"}, &applied)
+	if len(applied.Applications) != 1 || applied.Status != types.TreatmentActive ||
+		applied.NextDueAt == nil || applied.NextDueAt.Format("2006-01-02") != "2026-05-06" {
+		t.Errorf("first application: got %+v", applied)
+	}
+
+	var done types.TreatmentPlan
+	api.do(http.MethodPost, path+"/"+plan.ID+"/applications", "user1", map[string]any{}, &done)
+	if done.Status != types.TreatmentCompleted || done.NextDueAt != nil || done.EndedAt == nil {
+		t.Errorf("last application: got %+v", done)
+	}
+	status := api.do(http.MethodPost, path+"/"+plan.ID+"/applications", "user1", map[string]any{}, nil)
+	if status != http.StatusConflict {
+		t.Errorf("application after the plan ended: got %d, want 409", status)
+	}
+
+	var plans []types.TreatmentPlan
+	api.do(http.MethodGet, path, "user1", nil, &plans)
+	if len(plans) != 1 || len(plans[0].Applications) != 2 {
+		t.Errorf("plans: got %+v", plans)
+	}
+	if status := api.do(http.MethodGet, path, "user2", nil, nil); status != http.StatusNotFound {
+		t.Errorf("other user's plans: got %d, want 404", status)
+	}
+}
+
+func TestTreatments_RejectsUnknownAndResolvedInfections(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.infestedPlant("user1", "Ficus", types.PestStatusResolved)
+	req := map[string]any{"product": "Neem oil", "method": types.TreatmentWipe, "intervalDays": 7, "repetitions": 3}
+
+	req["infectionId"] = "unknown"
+	if status := api.do(http.MethodPost, "/api/plants/"+plant.ID+"/treatments", "user1", req, nil); status != http.StatusBadRequest {
+		t.Errorf("unknown infection: got %d, want 400", status)
+	}
+	req["infectionId"] = "thrips"
+	if status := api.do(http.MethodPost, "/api/plants/"+plant.ID+"/treatments", "user1", req, nil); status != http.StatusBadRequest {
+		t.Errorf("resolved infection: got %d, want 400", status)
+	}
+	req["method"] = "prayer"
+	if status := api.do(http.MethodPost, "/api/plants/"+plant.ID+"/treatments", "user1", req, nil); status != http.StatusBadRequest {
+		t.Errorf("invalid method: got %d, want 400", status)
+	}
+}
+
+func TestTreatments_ResolvingInfectionStopsPlan(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.infestedPlant("user1", "Ficus", types.PestStatusActive)
+	path := "/api/plants/" + plant.ID + "/treatments"
+	var plan types.TreatmentPlan
+	api.do(http.MethodPost, path, "user1", map[string]any{
+		"infectionId":  "thrips",
+		"product":      "Spinosad",
+		"method":       types.TreatmentSpray,
+		"intervalDays": 5,
+		"repetitions":  4,
+	}, &plan)
+
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "user1",
+		map[string]any{"pestHistory": pestHistory("thrips", types.PestThrips, types.PestStatusResolved)}, nil)
+
+	var plans []types.TreatmentPlan
+	api.do(http.MethodGet, path, "user1", nil, &plans)
+	if len(plans) != 1 || plans[0].Status != types.TreatmentStopped || plans[0].NextDueAt != nil {
+		t.Errorf("plan after resolving: got %+v", plans)
+	}
+}
+
+func TestTreatments_HouseholdMembersLogApplications(t *testing.T) {
+	api := newTestAPI(t)
+	_, plant := api.sharedHousehold("carer", types.RoleCaretaker)
+	api.do(http.MethodPatch, "/api/plants/"+plant.ID, "owner",
+		map[string]any{"pestHistory": pestHistory("mites", types.PestSpiderMites, types.PestStatusActive)}, nil)
+	path := "/api/plants/" + plant.ID + "/treatments"
+	req := map[string]any{
+		"infectionId":  "mites",
+		"product":      "Predatory mites",
+		"method":       types.TreatmentBiological,
+		"intervalDays": 14,
+		"repetitions":  2,
+	}
+
+	if status := api.do(http.MethodPost, path, "carer", req, nil); status != http.StatusForbidden {
+		t.Errorf("caretaker creating a plan: got %d, want 403", status)
+	}
+	var plan types.TreatmentPlan
+	api.do(http.MethodPost, path, "owner", req, &plan)
+	var applied types.TreatmentPlan
+	if status := api.do(http.MethodPost, path+"/"+plan.ID+"/applications", "carer", map[string]any{}, &applied); status != http.StatusOK {
+		t.Fatalf("caretaker logging: got %d", status)
+	}
+	if len(applied.Applications) != 1 || applied.Applications[0].UserID != "carer" || applied.UserID != "owner" {
+		t.Errorf("application: got %+v", applied)
+	}
+}
@@ -11,7 +11,11 @@ import (
 )
 
 // UploadHandler registers upload-related routes
-func UploadHandler(router *mux.Router, database *services.MongoDB, s3 *services.S3Service) {
+func UploadHandler(
+	router *mux.Router,
+	database services.UploadStore,
+	objects services.ObjectStore,
+) {
 	router.HandleFunc("/api/uploads/presign", func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := getUserID(r)
 		if !ok {
@@ -53,7 +57,7 @@ func UploadHandler(router *mux.Router, database *services.MongoDB, s3 *services.
 
 		count, err := database.GetUserUploadCount(r.Context(), userID)
 		if err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 		if count >= 20 {
@@ -61,10 +65,10 @@ func UploadHandler(router *mux.Router, database *services.MongoDB, s3 *services.
 			return
 		}
 
-		key := s3.GenerateObjectKey(userID, req.Filename)
-		url, headers, err := s3.PresignPutURL(r.Context(), key, req.ContentType, userID)
+		key := services.GenerateObjectKey(userID, req.Filename)
+		url, headers, err := objects.PresignPutURL(r.Context(), key, req.ContentType, userID)
 		if err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 		util.RespondJSON(w, http.StatusOK, map[string]interface{}{
@@ -93,7 +97,7 @@ func UploadHandler(router *mux.Router, database *services.MongoDB, s3 *services.
 			return
 		}
 
-		size, contentType, err := s3.HeadObjectInfo(r.Context(), req.Key)
+		size, contentType, err := objects.HeadObjectInfo(r.Context(), req.Key)
 		if err != nil {
 			util.BadRequest(w, "Uploaded object not found", nil)
 			return
@@ -121,14 +125,15 @@ func UploadHandler(router *mux.Router, database *services.MongoDB, s3 *services.
 		}
 
 		if err := database.RegisterUpload(r.Context(), userID, req.Key, size); err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 		util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
 	}).Methods(http.MethodPost, http.MethodOptions)
 
-	// DELETE /api/uploads/{key} - Delete an upload and remove from S3
-	router.HandleFunc("/api/uploads/{key}", func(w http.ResponseWriter, r *http.Request) {
+	// DELETE /api/uploads/{key} - Delete an upload and remove from S3.
+	// Keys contain slashes (users/<id>/...), so the variable spans the rest of the path.
+	router.HandleFunc("/api/uploads/{key:.+}", func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := getUserID(r)
 		if !ok {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -141,9 +146,9 @@ func UploadHandler(router *mux.Router, database *services.MongoDB, s3 *services.
 			return
 		}
 
-		uploadSvc := services.NewUploadService(database, s3)
+		uploadSvc := services.NewUploadService(database, objects)
 		if err := uploadSvc.DeleteUpload(r.Context(), key, userID); err != nil {
-			util.ServerError(w, err)
+			util.ServerError(w, r, err)
 			return
 		}
 
@@ -0,0 +1,280 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// VacationHandler registers routes for vacation mode and the caretaker hand-off
+func VacationHandler(
+	router *mux.Router,
+	database services.VacationStore,
+	plants services.PlantStore,
+	careEvents services.CareEventStore,
+) {
+	router.HandleFunc("/api/vacations", func(w http.ResponseWriter, r *http.Request) {
+		getVacations(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/vacations", func(w http.ResponseWriter, r *http.Request) {
+		createVacation(w, r, database)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/vacations/join", func(w http.ResponseWriter, r *http.Request) {
+		joinVacation(w, r, database)
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/vacations/caretaking", func(w http.ResponseWriter, r *http.Request) {
+		getCaretakerVacations(w, r, database)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/vacations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		endVacation(w, r, database, mux.Vars(r)["id"])
+	}).Methods(http.MethodDelete, http.MethodOptions)
+
+	router.HandleFunc("/api/vacations/{id}/summary", func(w http.ResponseWriter, r *http.Request) {
+		getVacationSummary(w, r, database, plants, careEvents, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+}
+
+func getVacations(w http.ResponseWriter, r *http.Request, db services.VacationStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vacations, err := db.GetVacations(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for i := range vacations {
+		vacations[i].InviteLink = constants.VacationLinkBaseURL + vacations[i].InviteCode
+	}
+	util.RespondJSON(w, http.StatusOK, vacations)
+}
+
+func createVacation(w http.ResponseWriter, r *http.Request, db services.VacationStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.CreateVacationRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	now := time.Now()
+	if errors := validation.ValidateCreateVacationRequest(req, now); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+
+	// One vacation at a time keeps the reminder redirect unambiguous
+	existing, err := db.GetVacations(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for _, vacation := range existing {
+		if vacation.EndsAt.After(now) {
+			util.RespondJSON(w, http.StatusConflict, map[string]string{
+				"error": "A vacation is already planned",
+			})
+			return
+		}
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	code, err := services.GenerateInviteCode()
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	vacation, err := db.CreateVacation(r.Context(), types.Vacation{
+		ID:         id,
+		UserID:     userID,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		InviteCode: code,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	vacation.InviteLink = constants.VacationLinkBaseURL + vacation.InviteCode
+	util.RespondJSON(w, http.StatusCreated, vacation)
+}
+
+// endVacation cancels a vacation that has not started yet or ends a running
+// one early, which also ends the caretaker's access
+func endVacation(w http.ResponseWriter, r *http.Request, db services.VacationStore, id string) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vacation, err := db.GetVacation(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if vacation == nil {
+		util.NotFound(w)
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case now.Before(vacation.StartsAt):
+		_, err = db.DeleteVacation(r.Context(), userID, id)
+	case now.Before(vacation.EndsAt):
+		_, err = db.EndVacation(r.Context(), userID, id, now)
+	default:
+		// Already over
+	}
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// joinVacation makes the user caretaker of the vacation the code belongs to
+func joinVacation(w http.ResponseWriter, r *http.Request, db services.VacationStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.JoinVacationRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errors := validation.ValidateJoinVacationRequest(req); len(errors) > 0 {
+		util.BadRequest(w, "Validation failed", errors)
+		return
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	vacation, err := db.GetVacationByCode(r.Context(), code)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if vacation == nil || !time.Now().Before(vacation.EndsAt) {
+		util.NotFound(w)
+		return
+	}
+	if vacation.UserID == userID {
+		util.BadRequest(w, "You cannot be your own caretaker", nil)
+		return
+	}
+	if vacation.CaretakerID == userID {
+		util.RespondJSON(w, http.StatusOK, caretakerView(*vacation))
+		return
+	}
+
+	set, err := db.SetVacationCaretaker(r.Context(), vacation.ID, userID, req.DisplayName)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !set {
+		util.RespondJSON(w, http.StatusConflict, map[string]string{
+			"error": "This vacation already has a caretaker",
+		})
+		return
+	}
+	vacation.CaretakerID = userID
+	vacation.CaretakerName = req.DisplayName
+	util.RespondJSON(w, http.StatusOK, caretakerView(*vacation))
+}
+
+func getCaretakerVacations(w http.ResponseWriter, r *http.Request, db services.VacationStore) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vacations, err := db.GetCaretakerVacations(r.Context(), userID, time.Now())
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	for i := range vacations {
+		vacations[i] = caretakerView(vacations[i])
+	}
+	util.RespondJSON(w, http.StatusOK, vacations)
+}
+
+func getVacationSummary(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.VacationStore,
+	plants services.PlantStore,
+	careEvents services.CareEventStore,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vacation, err := db.GetVacation(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if vacation == nil {
+		util.NotFound(w)
+		return
+	}
+
+	// A running vacation is summarized so far
+	to := vacation.EndsAt
+	if now := time.Now(); now.Before(to) {
+		to = now
+	}
+	events, err := careEvents.GetCareEvents(r.Context(), userID, vacation.StartsAt, to)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	userPlants, err := plants.GetPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, services.BuildVacationSummary(*vacation, userPlants, events))
+}
+
+// caretakerView hides the invite code from the caretaker
+func caretakerView(vacation types.Vacation) types.Vacation {
+	vacation.InviteCode = ""
+	vacation.InviteLink = ""
+	return vacation
+}
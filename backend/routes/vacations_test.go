@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// startVacation plans a vacation for "owner" that is already running and lets
+// caretakerID join it
+func (api *testAPI) startVacation(caretakerID string) types.Vacation {
+	api.t.Helper()
+
+	var vacation types.Vacation
+	status := api.do(http.MethodPost, "/api/vacations", "owner", map[string]any{
+		"startsAt": time.Now().Add(-time.Minute),
+		"endsAt":   time.Now().Add(7 * 24 * time.Hour),
+	}, &vacation)
+	if status != http.StatusCreated {
+		api.t.Fatalf("create vacation: got %d", status)
+	}
+	if vacation.InviteLink == "" || len(vacation.InviteCode) != 8 {
+		api.t.Errorf("vacation: got code %q link %q", vacation.InviteCode, vacation.InviteLink)
+	}
+	if status := api.do(http.MethodPost, "/api/vacations/join", caretakerID,
+		map[string]any{"code": vacation.InviteCode, "displayName": "Neighbour"}, nil); status != http.StatusOK {
+		api.t.Fatalf("join vacation: got %d", status)
+	}
+	return vacation
+}
+
+func TestVacations_CaretakerCaresAndOwnerGetsSummary(t *testing.T) {
+	api := newTestAPI(t)
+	fern := api.createPlant("owner", "Fern")
+	api.createPlant("owner", "Cactus")
+	vacation := api.startVacation("neighbour")
+
+	var plants []types.Plant
+	api.do(http.MethodGet, "/api/plants", "neighbour", nil, &plants)
+	if len(plants) != 2 || plants[0].Role != types.RoleCaretaker {
+		t.Fatalf("caretaker plant list: got %+v", plants)
+	}
+
+	api.do(http.MethodPost, "/api/plants/water", "neighbour", map[string]any{"plantIds": []string{fern.ID}}, nil)
+	if status := api.do(http.MethodPatch, "/api/plants/"+fern.ID, "neighbour",
+		map[string]any{"name": "Renamed"}, nil); status != http.StatusForbidden {
+		t.Errorf("caretaker update: got %d, want 403", status)
+	}
+
+	var summary types.VacationSummary
+	if status := api.do(http.MethodGet, "/api/vacations/"+vacation.ID+"/summary", "owner",
+		nil, &summary); status != http.StatusOK {
+		t.Fatalf("summary: got %d", status)
+	}
+	if summary.TotalEvents != 1 || summary.ByType[types.CareWatered] != 1 || summary.CaretakerName != "Neighbour" {
+		t.Errorf("summary: got %+v", summary)
+	}
+	if len(summary.NotCaredFor) != 1 || summary.NotCaredFor[0] != "Cactus" {
+		t.Errorf("not cared for: got %v", summary.NotCaredFor)
+	}
+	if status := api.do(http.MethodGet, "/api/vacations/"+vacation.ID+"/summary", "neighbour",
+		nil, nil); status != http.StatusNotFound {
+		t.Errorf("caretaker summary: got %d, want 404", status)
+	}
+}
+
+func TestVacations_AccessEndsWithVacation(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createPlant("owner", "Fern")
+	vacation := api.startVacation("neighbour")
+
+	if status := api.do(http.MethodPost, "/api/vacations/join", "stranger",
+		map[string]any{"code": vacation.InviteCode}, nil); status != http.StatusConflict {
+		t.Errorf("second caretaker: got %d, want 409", status)
+	}
+
+	if status := api.do(http.MethodDelete, "/api/vacations/"+vacation.ID, "owner", nil, nil); status != http.StatusOK {
+		t.Fatalf("end vacation: got %d", status)
+	}
+	if status := api.do(http.MethodGet, "/api/plants/"+plant.ID, "neighbour", nil, nil); status != http.StatusNotFound {
+		t.Errorf("caretaker after vacation: got %d, want 404", status)
+	}
+	if status := api.do(http.MethodPost, "/api/vacations/join", "stranger",
+		map[string]any{"code": vacation.InviteCode}, nil); status != http.StatusNotFound {
+		t.Errorf("join ended vacation: got %d, want 404", status)
+	}
+}
+
+func TestVacations_OnlyOneAtATime(t *testing.T) {
+	api := newTestAPI(t)
+	body := map[string]any{
+		"startsAt": time.Now().Add(24 * time.Hour),
+		"endsAt":   time.Now().Add(48 * time.Hour),
+	}
+	if status := api.do(http.MethodPost, "/api/vacations", "owner", body, nil); status != http.StatusCreated {
+		t.Fatalf("create: got %d", status)
+	}
+	if status := api.do(http.MethodPost, "/api/vacations", "owner", body, nil); status != http.StatusConflict {
+		t.Errorf("second vacation: got %d, want 409", status)
+	}
+	if status := api.do(http.MethodPost, "/api/vacations/join", "owner",
+		map[string]any{"code": "NOPE1234"}, nil); status != http.StatusNotFound {
+		t.Errorf("unknown code: got %d, want 404", status)
+	}
+}
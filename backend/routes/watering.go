@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+	"github.com/qreepex/water-me-app/backend/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// WateringHandler registers routes for snoozing watering reminders and for
+// watering interval recommendations. It registers static /api/plants paths,
+// so it must come before PlantHandler.
+func WateringHandler(
+	router *mux.Router,
+	plants services.PlantStore,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+) {
+	router.HandleFunc("/api/plants/watering-recommendations", func(w http.ResponseWriter, r *http.Request) {
+		getWateringRecommendations(w, r, careEvents, access)
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/snooze", func(w http.ResponseWriter, r *http.Request) {
+		snoozeWatering(w, r, plants, careEvents, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodPost, http.MethodOptions)
+
+	router.HandleFunc("/api/plants/{id}/watering-recommendation", func(w http.ResponseWriter, r *http.Request) {
+		getWateringRecommendation(w, r, careEvents, access, mux.Vars(r)["id"])
+	}).Methods(http.MethodGet, http.MethodOptions)
+
+	router.HandleFunc(
+		"/api/plants/{id}/watering-recommendation/accept",
+		func(w http.ResponseWriter, r *http.Request) {
+			acceptWateringRecommendation(w, r, plants, careEvents, access, mux.Vars(r)["id"])
+		},
+	).Methods(http.MethodPost, http.MethodOptions)
+}
+
+// getWateringRecommendations lists the accessible plants whose interval
+// should change, most confident first
+func getWateringRecommendations(
+	w http.ResponseWriter,
+	r *http.Request,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plants, err := access.ListPlants(r.Context(), userID)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	now := time.Now()
+	eventsByOwner := make(map[string][]types.CareEvent)
+	recommendations := make([]types.WateringRecommendation, 0)
+	for _, plant := range plants {
+		events, ok := eventsByOwner[plant.UserID]
+		if !ok {
+			events, err = recentCareEvents(r.Context(), careEvents, plant.UserID, now)
+			if err != nil {
+				util.ServerError(w, r, err)
+				return
+			}
+			eventsByOwner[plant.UserID] = events
+		}
+		if rec := services.RecommendWatering(plant, events, now); rec.Change {
+			recommendations = append(recommendations, rec)
+		}
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Confidence > recommendations[j].Confidence
+	})
+	util.RespondJSON(w, http.StatusOK, recommendations)
+}
+
+func getWateringRecommendation(
+	w http.ResponseWriter,
+	r *http.Request,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return
+	}
+	now := time.Now()
+	events, err := recentCareEvents(r.Context(), careEvents, plant.UserID, now)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	util.RespondJSON(w, http.StatusOK, services.RecommendWatering(*plant, events, now))
+}
+
+// acceptWateringRecommendation recomputes the recommendation for a plant,
+// sets its watering interval to the suggestion and logs the change
+func acceptWateringRecommendation(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantStore,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	plant, ok := editablePlant(w, r, access, userID, id)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	events, err := recentCareEvents(r.Context(), careEvents, plant.UserID, now)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	rec := services.RecommendWatering(*plant, events, now)
+	if !rec.Change {
+		util.RespondJSON(w, http.StatusConflict, map[string]string{"error": "No interval change is recommended"})
+		return
+	}
+
+	found, err := db.SetWateringInterval(r.Context(), plant.ID, plant.UserID, rec.SuggestedIntervalDays)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !found {
+		util.NotFound(w)
+		return
+	}
+	event, err := services.NewCareEvent(*plant, userID, types.CareIntervalChanged, now)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	event.Interval = &types.IntervalChange{FromDays: rec.CurrentIntervalDays, ToDays: rec.SuggestedIntervalDays}
+	logCareEvent(r.Context(), careEvents, event)
+
+	updated, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if updated == nil {
+		util.NotFound(w)
+		return
+	}
+	normalizePlantResponse(updated)
+	util.RespondJSON(w, http.StatusOK, types.AcceptWateringRecommendationResponse{
+		Plant:          updated,
+		Recommendation: rec,
+	})
+}
+
+// snoozeWatering holds back the watering reminders of a plant for some days
+func snoozeWatering(
+	w http.ResponseWriter,
+	r *http.Request,
+	db services.PlantStore,
+	careEvents services.CareEventStore,
+	access *services.PlantAccess,
+	id string,
+) {
+	userID, ok := getUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req types.SnoozeRequest
+	if err := util.DecodeJSON(r, &req); err != nil {
+		util.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if errs := validation.ValidateSnoozeRequest(req); len(errs) > 0 {
+		util.BadRequest(w, "Validation failed", errs)
+		return
+	}
+
+	plant, err := access.GetPlant(r.Context(), userID, id)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if plant == nil {
+		util.NotFound(w)
+		return
+	}
+	if !plant.Role.CanCare() {
+		util.Forbidden(w)
+		return
+	}
+	if plant.Watering == nil || plant.Watering.IntervalDays <= 0 {
+		util.BadRequest(w, "Plant has no watering interval", nil)
+		return
+	}
+
+	now := time.Now()
+	until := now.AddDate(0, 0, req.Days)
+	found, err := db.SnoozeWatering(r.Context(), plant.ID, plant.UserID, until)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	if !found {
+		util.NotFound(w)
+		return
+	}
+	event, err := services.NewCareEvent(*plant, userID, types.CareSnoozed, now)
+	if err != nil {
+		util.ServerError(w, r, err)
+		return
+	}
+	event.Days = req.Days
+	logCareEvent(r.Context(), careEvents, event)
+
+	util.RespondJSON(w, http.StatusOK, map[string]time.Time{"snoozedUntil": until})
+}
+
+func recentCareEvents(
+	ctx context.Context,
+	careEvents services.CareEventStore,
+	ownerID string,
+	now time.Time,
+) ([]types.CareEvent, error) {
+	return careEvents.GetCareEvents(ctx, ownerID, now.Add(-services.RecommendationWindow), now.Add(time.Second))
+}
+
+// logCareEvent logs event; the change it records stands either way and a lost
+// log entry only affects history
+func logCareEvent(ctx context.Context, careEvents services.CareEventStore, event types.CareEvent) {
+	if err := careEvents.LogCareEvents(ctx, []types.CareEvent{event}); err != nil {
+		slog.ErrorContext(ctx, "failed to log care event", "type", event.Type, "error", err)
+	}
+}
@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func (api *testAPI) createWateredPlant(userID, name string, intervalDays int) types.Plant {
+	api.t.Helper()
+	var plant types.Plant
+	status := api.do(http.MethodPost, "/api/plants", userID, map[string]any{
+		"name": name,
+		"watering": map[string]any{
+			"intervalDays": intervalDays,
+			"method":       types.MethodTopWatering,
+			"waterType":    types.WaterTap,
+		},
+	}, &plant)
+	if status != http.StatusCreated {
+		api.t.Fatalf("create plant %s: got %d", name, status)
+	}
+	return plant
+}
+
+// logWaterings records waterings of plant every intervalDays, the last one
+// a day ago
+func (api *testAPI) logWaterings(plant types.Plant, count, intervalDays int) {
+	api.t.Helper()
+	start := time.Now().AddDate(0, 0, -1-(count-1)*intervalDays)
+	events := make([]types.CareEvent, 0, count)
+	for i := range count {
+		events = append(events, types.CareEvent{
+			ID:      plant.ID + "-" + string(rune('a'+i)),
+			PlantID: plant.ID,
+			OwnerID: plant.UserID,
+			UserID:  plant.UserID,
+			Type:    types.CareWatered,
+			At:      start.AddDate(0, 0, i*intervalDays),
+		})
+	}
+	if err := api.store.LogCareEvents(context.Background(), events); err != nil {
+		api.t.Fatalf("log waterings: %v", err)
+	}
+}
+
+func TestWatering_RecommendAndAcceptInterval(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createWateredPlant("user1", "Calathea", 7)
+	api.logWaterings(plant, 6, 10)
+
+	var rec types.WateringRecommendation
+	path := "/api/plants/" + plant.ID + "/watering-recommendation"
+	if status := api.do(http.MethodGet, path, "user1", nil, &rec); status != http.StatusOK {
+		t.Fatalf("recommendation: got %d", status)
+	}
+	if !rec.Change || rec.SuggestedIntervalDays != 10 || rec.CurrentIntervalDays != 7 {
+		t.Fatalf("recommendation: got %+v", rec)
+	}
+	if rec.Confidence <= 0.5 || rec.Explanation == "" || *rec.ObservedIntervalDays != 10 {
+		t.Errorf("confidence and explanation: got %+v", rec)
+	}
+
+	var all []types.WateringRecommendation
+	api.do(http.MethodGet, "/api/plants/watering-recommendations", "user1", nil, &all)
+	if len(all) != 1 || all[0].PlantID != plant.ID {
+		t.Errorf("recommendations: got %+v", all)
+	}
+
+	var accepted types.AcceptWateringRecommendationResponse
+	if status := api.do(http.MethodPost, path+"/accept", "user1", nil, &accepted); status != http.StatusOK {
+		t.Fatalf("accept: got %d", status)
+	}
+	if accepted.Plant.Watering.IntervalDays != 10 {
+		t.Errorf("accepted interval: got %+v", accepted.Plant.Watering)
+	}
+	events, _ := api.store.GetCareEvents(context.Background(), "user1", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if len(events) != 1 || events[0].Type != types.CareIntervalChanged ||
+		events[0].Interval.FromDays != 7 || events[0].Interval.ToDays != 10 {
+		t.Errorf("logged change: got %+v", events)
+	}
+
+	if status := api.do(http.MethodPost, path+"/accept", "user1", nil, nil); status != http.StatusConflict {
+		t.Errorf("accept again: got %d, want 409", status)
+	}
+}
+
+func TestWatering_NotEnoughHistory(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createWateredPlant("user1", "Pilea", 7)
+	api.logWaterings(plant, 2, 12)
+
+	var rec types.WateringRecommendation
+	api.do(http.MethodGet, "/api/plants/"+plant.ID+"/watering-recommendation", "user1", nil, &rec)
+	if rec.Change || rec.Confidence != 0 || rec.ObservedIntervalDays != nil || rec.SuggestedIntervalDays != 7 {
+		t.Errorf("recommendation: got %+v", rec)
+	}
+	status := api.do(http.MethodPost, "/api/plants/"+plant.ID+"/watering-recommendation/accept", "user1", nil, nil)
+	if status != http.StatusConflict {
+		t.Errorf("accept: got %d, want 409", status)
+	}
+}
+
+func TestWatering_SnoozeUntilWatered(t *testing.T) {
+	api := newTestAPI(t)
+	plant := api.createWateredPlant("user1", "Ficus", 7)
+
+	path := "/api/plants/" + plant.ID + "/snooze"
+	if status := api.do(http.MethodPost, path, "user1", map[string]any{"days": 30}, nil); status != http.StatusBadRequest {
+		t.Errorf("too long: got %d, want 400", status)
+	}
+	if status := api.do(http.MethodPost, path, "user2", map[string]any{"days": 2}, nil); status != http.StatusNotFound {
+		t.Errorf("other user: got %d, want 404", status)
+	}
+	if status := api.do(http.MethodPost, path, "user1", map[string]any{"days": 2}, nil); status != http.StatusOK {
+		t.Fatalf("snooze: got %d", status)
+	}
+
+	var snoozed types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "user1", nil, &snoozed)
+	if snoozed.Watering.SnoozedUntil == nil || snoozed.Watering.SnoozedUntil.Before(time.Now().AddDate(0, 0, 1)) {
+		t.Fatalf("snoozed until: got %v", snoozed.Watering.SnoozedUntil)
+	}
+	events, _ := api.store.GetCareEvents(context.Background(), "user1", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if len(events) != 1 || events[0].Type != types.CareSnoozed || events[0].Days != 2 {
+		t.Errorf("logged snooze: got %+v", events)
+	}
+
+	api.do(http.MethodPost, "/api/plants/water", "user1", map[string]any{"plantIds": []string{plant.ID}}, nil)
+	var watered types.Plant
+	api.do(http.MethodGet, "/api/plants/"+plant.ID, "user1", nil, &watered)
+	if watered.Watering.SnoozedUntil != nil {
+		t.Errorf("watering keeps snooze: got %v", watered.Watering.SnoozedUntil)
+	}
+
+	dry := api.createPlant("user1", "Cactus")
+	status := api.do(http.MethodPost, "/api/plants/"+dry.ID+"/snooze", "user1", map[string]any{"days": 2}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("no watering interval: got %d, want 400", status)
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// PlantAccess resolves the plants a user can reach: their own plants, on which
+// they act as owner, the plants shared with households they belong to, on
+// which their household role applies, and the plants of users on vacation who
+// made them caretaker.
+type PlantAccess struct {
+	plants     PlantStore
+	households HouseholdStore
+	vacations  VacationStore
+	careEvents CareEventStore
+}
+
+// NewPlantAccess creates a PlantAccess
+func NewPlantAccess(
+	plants PlantStore,
+	households HouseholdStore,
+	vacations VacationStore,
+	careEvents CareEventStore,
+) *PlantAccess {
+	return &PlantAccess{
+		plants:     plants,
+		households: households,
+		vacations:  vacations,
+		careEvents: careEvents,
+	}
+}
+
+// roles maps household ID to the role userID has in it
+func (a *PlantAccess) roles(ctx context.Context, userID string) (map[string]types.HouseholdRole, error) {
+	households, err := a.households.GetHouseholdsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]types.HouseholdRole, len(households))
+	for _, household := range households {
+		if member, ok := household.Member(userID); ok {
+			roles[household.ID] = member.Role
+		}
+	}
+	return roles, nil
+}
+
+// ListPlants returns the user's own plants followed by the plants other
+// members share through the user's households and the plants the user looks
+// after as vacation caretaker, each with Role set
+func (a *PlantAccess) ListPlants(ctx context.Context, userID string) ([]types.Plant, error) {
+	plants, err := a.plants.GetPlants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range plants {
+		plants[i].Role = types.RoleOwner
+	}
+
+	roles, err := a.roles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(plants))
+	for i, plant := range plants {
+		index[plant.ID] = i
+	}
+
+	if len(roles) > 0 {
+		householdIDs := make([]string, 0, len(roles))
+		for id := range roles {
+			householdIDs = append(householdIDs, id)
+		}
+		shared, err := a.plants.GetHouseholdPlants(ctx, householdIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, plant := range shared {
+			if _, listed := index[plant.ID]; listed {
+				continue // Own plant
+			}
+			plant.Role = roles[plant.HouseholdID]
+			index[plant.ID] = len(plants)
+			plants = append(plants, plant)
+		}
+	}
+
+	vacations, err := a.vacations.GetCaretakerVacations(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, vacation := range vacations {
+		away, err := a.plants.GetPlants(ctx, vacation.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, plant := range away {
+			if i, listed := index[plant.ID]; listed {
+				// A household viewer still gets to care while caretaking
+				if !plants[i].Role.CanCare() {
+					plants[i].Role = types.RoleCaretaker
+				}
+				continue
+			}
+			plant.Role = types.RoleCaretaker
+			index[plant.ID] = len(plants)
+			plants = append(plants, plant)
+		}
+	}
+	return plants, nil
+}
+
+// GetPlant returns a plant the user can access with Role set, or nil
+func (a *PlantAccess) GetPlant(ctx context.Context, userID, id string) (*types.Plant, error) {
+	plant, err := a.plants.GetPlantByID(ctx, id)
+	if err != nil || plant == nil {
+		return nil, err
+	}
+	return a.withRole(ctx, userID, plant)
+}
+
+// GetPlantBySlug looks the slug up among the user's own plants first, then
+// among the plants shared with them
+func (a *PlantAccess) GetPlantBySlug(ctx context.Context, userID, slug string) (*types.Plant, error) {
+	plant, err := a.plants.GetPlantBySlug(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+	if plant != nil {
+		plant.Role = types.RoleOwner
+		return plant, nil
+	}
+
+	plants, err := a.ListPlants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, shared := range plants {
+		if shared.Slug == slug {
+			return &shared, nil
+		}
+	}
+	return nil, nil
+}
+
+// HouseholdRole returns the user's role in a household
+func (a *PlantAccess) HouseholdRole(
+	ctx context.Context,
+	userID, householdID string,
+) (types.HouseholdRole, bool, error) {
+	household, err := a.households.GetHousehold(ctx, householdID)
+	if err != nil || household == nil {
+		return "", false, err
+	}
+	member, ok := household.Member(userID)
+	return member.Role, ok, nil
+}
+
+// WaterPlants waters the given plants the user may care for, skips the rest
+// and logs a care event per watered plant
+func (a *PlantAccess) WaterPlants(ctx context.Context, userID string, plantIDs []string) (int64, error) {
+	requested := make(map[string]bool, len(plantIDs))
+	for _, id := range plantIDs {
+		requested[id] = true
+	}
+
+	// Resolve access once instead of per plant
+	plants, err := a.ListPlants(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	byOwner := make(map[string][]string)
+	for _, plant := range plants {
+		if !requested[plant.ID] || !plant.Role.CanCare() {
+			continue
+		}
+		byOwner[plant.UserID] = append(byOwner[plant.UserID], plant.ID)
+	}
+
+	var watered int64
+	now := time.Now()
+	events := make([]types.CareEvent, 0, len(plantIDs))
+	for ownerID, ids := range byOwner {
+		n, err := a.plants.WaterPlants(ctx, ownerID, ids)
+		if err != nil {
+			return watered, err
+		}
+		watered += n
+
+		for _, id := range ids {
+			eventID, err := gonanoid.New()
+			if err != nil {
+				return watered, fmt.Errorf("generate care event id: %w", err)
+			}
+			events = append(events, types.CareEvent{
+				ID:      eventID,
+				PlantID: id,
+				OwnerID: ownerID,
+				UserID:  userID,
+				Type:    types.CareWatered,
+				At:      now,
+			})
+		}
+	}
+
+	// The plants are watered either way; a lost log entry only affects history
+	if err := a.careEvents.LogCareEvents(ctx, events); err != nil {
+		slog.ErrorContext(ctx, "failed to log care events", "error", err)
+	}
+	return watered, nil
+}
+
+func (a *PlantAccess) withRole(
+	ctx context.Context,
+	userID string,
+	plant *types.Plant,
+) (*types.Plant, error) {
+	if plant.UserID == userID {
+		plant.Role = types.RoleOwner
+		return plant, nil
+	}
+	if plant.HouseholdID != "" {
+		role, ok, err := a.HouseholdRole(ctx, userID, plant.HouseholdID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			plant.Role = role
+		}
+	}
+	if plant.Role.CanCare() {
+		return plant, nil
+	}
+
+	caretaker, err := a.isCaretakerFor(ctx, userID, plant.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if caretaker {
+		plant.Role = types.RoleCaretaker
+	}
+	if plant.Role == "" {
+		return nil, nil
+	}
+	return plant, nil
+}
+
+// isCaretakerFor reports whether userID is currently caretaker for ownerID's vacation
+func (a *PlantAccess) isCaretakerFor(ctx context.Context, userID, ownerID string) (bool, error) {
+	vacations, err := a.vacations.GetCaretakerVacations(ctx, userID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	for _, vacation := range vacations {
+		if vacation.UserID == ownerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
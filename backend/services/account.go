@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// AccountEraser deletes everything stored about a user. Each step is
+// idempotent and the steps run in a fixed order, so an erasure that fails
+// part-way is resumed by running it again.
+type AccountEraser struct {
+	store   Store
+	objects ObjectStore
+}
+
+// NewAccountEraser creates an AccountEraser
+func NewAccountEraser(store Store, objects ObjectStore) *AccountEraser {
+	return &AccountEraser{store: store, objects: objects}
+}
+
+type erasureStep struct {
+	name string
+	run  func(ctx context.Context, userID string) (int64, error)
+}
+
+// Erase deletes the user's data and returns the completed audit record
+func (e *AccountEraser) Erase(ctx context.Context, userID string) (*types.AccountErasure, error) {
+	userHash := logging.HashUserID(userID)
+	if err := e.store.StartAccountErasure(ctx, userHash, time.Now()); err != nil {
+		return nil, fmt.Errorf("record erasure start: %w", err)
+	}
+
+	// Access and push notifications go first so nothing new is written or
+	// sent while the rest is deleted. The account itself goes last, so a
+	// local auth user can still sign in to retry.
+	steps := []erasureStep{
+		{"notification config", e.deleteNotificationConfig},
+		{"api tokens", e.store.DeleteUserAPITokens},
+		{"households", e.leaveHouseholds},
+		{"vacations", e.store.DeleteUserVacations},
+		{"plant shares", e.store.DeleteUserPlantShares},
+		{"exports", e.store.DeleteUserExports},
+		{"safety profile", e.store.DeleteUserSafetyProfile},
+		{"rooms", e.store.DeleteUserRooms},
+		{"pest outbreaks", e.store.DeleteUserOutbreaks},
+		{"treatment plans", e.store.DeleteUserTreatmentPlans},
+		{"care events", e.store.DeleteUserCareEvents},
+		{"objects", e.deleteObjects},
+		{"upload records", e.store.DeleteUserUploads},
+		{"plants", e.store.DeleteUserPlants},
+		{"user", e.deleteUser},
+	}
+	for _, step := range steps {
+		deleted, err := step.run(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("erase %s: %w", step.name, err)
+		}
+		slog.InfoContext(ctx, "erased account data", "step", step.name, "deleted", deleted)
+	}
+
+	erasure, err := e.store.CompleteAccountErasure(ctx, userHash, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("record erasure completion: %w", err)
+	}
+	return erasure, nil
+}
+
+// deleteNotificationConfig also revokes all of the user's device tokens,
+// which are stored in the config
+func (e *AccountEraser) deleteNotificationConfig(ctx context.Context, userID string) (int64, error) {
+	deleted, err := e.store.DeleteNotificationConfig(ctx, userID)
+	return boolCount(deleted), err
+}
+
+// leaveHouseholds deletes the households the user owns, unsharing the plants
+// of the other members, and removes the user from all others
+func (e *AccountEraser) leaveHouseholds(ctx context.Context, userID string) (int64, error) {
+	households, err := e.store.GetHouseholdsForUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, household := range households {
+		if member, _ := household.Member(userID); member.Role == types.RoleOwner {
+			if _, err := e.store.ClearPlantHousehold(ctx, household.ID, ""); err != nil {
+				return count, err
+			}
+			if _, err := e.store.DeleteHousehold(ctx, household.ID); err != nil {
+				return count, err
+			}
+		} else {
+			if _, err := e.store.ClearPlantHousehold(ctx, household.ID, userID); err != nil {
+				return count, err
+			}
+			if _, err := e.store.RemoveHouseholdMember(ctx, household.ID, userID); err != nil {
+				return count, err
+			}
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (e *AccountEraser) deleteObjects(ctx context.Context, userID string) (int64, error) {
+	deleted, err := e.objects.DeletePrefix(ctx, UserPrefix(userID))
+	return int64(deleted), err
+}
+
+func (e *AccountEraser) deleteUser(ctx context.Context, userID string) (int64, error) {
+	deleted, err := e.store.DeleteUser(ctx, userID)
+	return boolCount(deleted), err
+}
+
+func boolCount(ok bool) int64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// regularityWindow is how long before a growth log entry waterings are
+	// compared with the health recorded in it
+	regularityWindow = 30 * 24 * time.Hour
+	// lateAfterDays is the grace period after a watering was due
+	lateAfterDays = 1
+)
+
+// healthScores turns health statuses into scores of the composite
+var healthScores = map[types.HealthStatus]int{
+	types.HealthExcellent: 100,
+	types.HealthGood:      75,
+	types.HealthFair:      45,
+	types.HealthPoor:      15,
+}
+
+var trendScores = map[types.HealthTrend]int{
+	types.HealthImproving: 100,
+	types.HealthStable:    70,
+	types.HealthDeclining: 20,
+}
+
+// AnalyzePlant computes growth and health series, care regularity and the
+// composite health score of plant from its growth log and the watering
+// events in events
+func AnalyzePlant(plant types.Plant, events []types.CareEvent) types.PlantAnalytics {
+	logs := append([]types.GrowthLog(nil), plant.GrowthHistory...)
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Date.Before(logs[j].Date) })
+
+	analytics := types.PlantAnalytics{
+		PlantID:          plant.ID,
+		Name:             plant.Name,
+		Growth:           growthSeries(logs),
+		Health:           make([]types.HealthPoint, 0, len(logs)),
+		HealthTrend:      PlantHealthTrend(plant),
+		Components:       []types.ScoreComponent{},
+		DecliningReasons: []string{},
+	}
+	for _, log := range logs {
+		if log.Health != "" {
+			analytics.Health = append(analytics.Health, types.HealthPoint{Date: log.Date, Health: log.Health})
+		}
+	}
+
+	waterings := rateWaterings(plant, events)
+	analytics.Care = careRegularity(waterings, logs)
+	analytics.Components = scoreComponents(logs, analytics)
+	analytics.HealthScore = compositeScore(analytics.Components)
+	analytics.DecliningReasons = decliningReasons(logs, analytics.HealthTrend)
+	analytics.Declining = len(analytics.DecliningReasons) > 0
+	return analytics
+}
+
+func growthSeries(logs []types.GrowthLog) []types.GrowthPoint {
+	series := make([]types.GrowthPoint, 0, len(logs))
+	for i, log := range logs {
+		point := types.GrowthPoint{Date: log.Date, HeightCm: log.HeightCm, LeafCount: log.LeafCount}
+		if i > 0 {
+			previous := logs[i-1]
+			weeks := log.Date.Sub(previous.Date).Hours() / (24 * 7)
+			// 0 means not measured
+			if weeks > 0 && log.HeightCm > 0 && previous.HeightCm > 0 {
+				rate := round2((log.HeightCm - previous.HeightCm) / weeks)
+				point.HeightCmPerWeek = &rate
+			}
+			if weeks > 0 && log.LeafCount > 0 && previous.LeafCount > 0 {
+				rate := round2(float64(log.LeafCount-previous.LeafCount) / weeks)
+				point.LeavesPerWeek = &rate
+			}
+		}
+		series = append(series, point)
+	}
+	return series
+}
+
+// ratedWatering is a watering with whether it came on time
+type ratedWatering struct {
+	at        time.Time
+	late      bool
+	delayDays float64
+}
+
+// rateWaterings compares the gap before each watering of plant with its
+// interval, stretched in winter by the winter water factor. The first
+// watering has nothing to compare with and is not rated.
+func rateWaterings(plant types.Plant, events []types.CareEvent) []ratedWatering {
+	if plant.Watering == nil || plant.Watering.IntervalDays <= 0 {
+		return nil
+	}
+	var times []time.Time
+	for _, event := range events {
+		if event.PlantID == plant.ID && event.Type == types.CareWatered {
+			times = append(times, event.At)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	factor := winterFactor(plant)
+	rated := make([]ratedWatering, 0, len(times))
+	for i := 1; i < len(times); i++ {
+		due := float64(plant.Watering.IntervalDays)
+		if IsWinter(times[i]) {
+			due /= factor
+		}
+		delay := times[i].Sub(times[i-1]).Hours()/24 - due
+		rated = append(rated, ratedWatering{
+			at:        times[i],
+			late:      delay > lateAfterDays,
+			delayDays: math.Max(delay, 0),
+		})
+	}
+	return rated
+}
+
+func careRegularity(waterings []ratedWatering, logs []types.GrowthLog) types.CareRegularity {
+	care := types.CareRegularity{Waterings: len(waterings)}
+	if len(waterings) == 0 {
+		care.Message = "Not enough waterings were logged to tell how regular they are"
+		return care
+	}
+	totalDelay := 0.0
+	for _, watering := range waterings {
+		if watering.late {
+			care.Late++
+			totalDelay += watering.delayDays
+		} else {
+			care.OnTime++
+		}
+	}
+	rate := round2(float64(care.OnTime) / float64(len(waterings)))
+	care.OnTimeRate = &rate
+	if care.Late > 0 {
+		care.AverageDelayDays = round2(totalDelay / float64(care.Late))
+	}
+
+	// Pair the health of each entry with the punctuality of the month before
+	var rates, healths []float64
+	for _, log := range logs {
+		score, ok := healthScores[log.Health]
+		if !ok {
+			continue
+		}
+		onTime, total := 0, 0
+		for _, watering := range waterings {
+			if watering.at.After(log.Date) || log.Date.Sub(watering.at) > regularityWindow {
+				continue
+			}
+			total++
+			if !watering.late {
+				onTime++
+			}
+		}
+		if total > 0 {
+			rates = append(rates, float64(onTime)/float64(total))
+			healths = append(healths, float64(score))
+		}
+	}
+	if correlation, ok := pearson(rates, healths); ok {
+		correlation = round2(correlation)
+		care.Correlation = &correlation
+		switch {
+		case correlation >= 0.3:
+			care.Message = "Health was better when waterings were on time"
+		case correlation <= -0.3:
+			care.Message = "Health was worse when waterings were on time; the plant may get too much water"
+		default:
+			care.Message = "There is no clear link between watering on time and health"
+		}
+		return care
+	}
+	care.Message = fmt.Sprintf("%d of %d waterings were on time", care.OnTime, len(waterings))
+	return care
+}
+
+// pearson returns the correlation coefficient of xs and ys, or false with
+// fewer than three pairs or when either side does not vary
+func pearson(xs, ys []float64) (float64, bool) {
+	if len(xs) < 3 || len(xs) != len(ys) {
+		return 0, false
+	}
+	meanX, deviationX := meanAndDeviation(xs)
+	meanY, deviationY := meanAndDeviation(ys)
+	if deviationX == 0 || deviationY == 0 {
+		return 0, false
+	}
+	covariance := 0.0
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	covariance /= float64(len(xs))
+	return covariance / (deviationX * deviationY), true
+}
+
+// scoreComponents scores the latest health, its trend, the latest growth and
+// watering punctuality; parts without data are left out
+func scoreComponents(logs []types.GrowthLog, analytics types.PlantAnalytics) []types.ScoreComponent {
+	components := make([]types.ScoreComponent, 0, 4)
+	for i := len(logs) - 1; i >= 0; i-- {
+		if score, ok := healthScores[logs[i].Health]; ok {
+			components = append(components, types.ScoreComponent{Name: "health", Score: score, Weight: 0.4})
+			break
+		}
+	}
+	if score, ok := trendScores[analytics.HealthTrend]; ok {
+		components = append(components, types.ScoreComponent{Name: "trend", Score: score, Weight: 0.2})
+	}
+	if len(logs) > 0 && logs[len(logs)-1].Health != types.HealthDormant {
+		// Dormant plants are not expected to grow
+		if score, ok := growthScore(analytics.Growth[len(analytics.Growth)-1]); ok {
+			components = append(components, types.ScoreComponent{Name: "growth", Score: score, Weight: 0.15})
+		}
+	}
+	if analytics.Care.OnTimeRate != nil {
+		components = append(components, types.ScoreComponent{
+			Name:   "care",
+			Score:  int(math.Round(*analytics.Care.OnTimeRate * 100)),
+			Weight: 0.25,
+		})
+	}
+	return components
+}
+
+// growthScore rates the latest growth: growing 100, unchanged 60, shrinking 20
+func growthScore(point types.GrowthPoint) (int, bool) {
+	if point.HeightCmPerWeek == nil && point.LeavesPerWeek == nil {
+		return 0, false
+	}
+	shrinking, growing := false, false
+	for _, rate := range []*float64{point.HeightCmPerWeek, point.LeavesPerWeek} {
+		if rate == nil {
+			continue
+		}
+		shrinking = shrinking || *rate < 0
+		growing = growing || *rate > 0
+	}
+	switch {
+	case shrinking:
+		return 20, true
+	case growing:
+		return 100, true
+	default:
+		return 60, true
+	}
+}
+
+func compositeScore(components []types.ScoreComponent) *int {
+	total, weights := 0.0, 0.0
+	for _, component := range components {
+		total += float64(component.Score) * component.Weight
+		weights += component.Weight
+	}
+	if weights == 0 {
+		return nil
+	}
+	score := int(math.Round(total / weights))
+	return &score
+}
+
+// decliningReasons explains why a plant is getting worse: a worse health in
+// the latest entry, or lost height or leaves since the entry before
+func decliningReasons(logs []types.GrowthLog, trend types.HealthTrend) []string {
+	reasons := make([]string, 0)
+	if trend == types.HealthDeclining {
+		var rated []types.GrowthLog
+		for _, log := range logs {
+			if healthRanks[log.Health] > 0 {
+				rated = append(rated, log)
+			}
+		}
+		reasons = append(reasons, fmt.Sprintf(
+			"Health went from %s to %s", rated[len(rated)-2].Health, rated[len(rated)-1].Health,
+		))
+	}
+	if len(logs) < 2 {
+		return reasons
+	}
+	latest, previous := logs[len(logs)-1], logs[len(logs)-2]
+	if latest.HeightCm > 0 && previous.HeightCm > 0 && latest.HeightCm < previous.HeightCm {
+		reasons = append(reasons, fmt.Sprintf(
+			"Height dropped from %.1f to %.1f cm", previous.HeightCm, latest.HeightCm,
+		))
+	}
+	// Losing a leaf now and then is normal
+	if latest.LeafCount > 0 && previous.LeafCount > 0 &&
+		float64(latest.LeafCount) < 0.9*float64(previous.LeafCount) {
+		reasons = append(reasons, fmt.Sprintf(
+			"Leaves dropped from %d to %d", previous.LeafCount, latest.LeafCount,
+		))
+	}
+	return reasons
+}
+
+// SummarizeAnalytics sums up the analytics of plants; declining plants come
+// lowest score first
+func SummarizeAnalytics(plants []types.Plant, analytics []types.PlantAnalytics) types.AnalyticsSummary {
+	summary := types.AnalyticsSummary{
+		Plants:    len(plants),
+		Declining: make([]types.DecliningPlant, 0),
+	}
+	total := 0
+	for i, entry := range analytics {
+		if entry.HealthScore != nil {
+			summary.Scored++
+			total += *entry.HealthScore
+		}
+		if entry.Declining {
+			summary.Declining = append(summary.Declining, types.DecliningPlant{
+				PlantID:     entry.PlantID,
+				Name:        entry.Name,
+				Slug:        plants[i].Slug,
+				HealthScore: entry.HealthScore,
+				Reasons:     entry.DecliningReasons,
+			})
+		}
+	}
+	if summary.Scored > 0 {
+		average := int(math.Round(float64(total) / float64(summary.Scored)))
+		summary.AverageHealthScore = &average
+	}
+	sort.SliceStable(summary.Declining, func(i, j int) bool {
+		a, b := summary.Declining[i].HealthScore, summary.Declining[j].HealthScore
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a < *b
+	})
+	return summary
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestCareRegularity_CorrelatesPunctualityWithHealth(t *testing.T) {
+	start := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	waterings := []ratedWatering{
+		{at: day(5)}, {at: day(20)},
+		{at: day(40), late: true, delayDays: 3}, {at: day(55), late: true, delayDays: 5},
+		{at: day(70)}, {at: day(85), late: true, delayDays: 2},
+	}
+	logs := []types.GrowthLog{
+		{Date: day(30), Health: types.HealthExcellent},
+		{Date: day(60), Health: types.HealthPoor},
+		{Date: day(90), Health: types.HealthGood},
+		{Date: day(91), Health: types.HealthDormant},
+	}
+
+	care := careRegularity(waterings, logs)
+	if care.OnTime != 3 || care.Late != 3 || care.AverageDelayDays != 3.33 {
+		t.Errorf("counts: got %+v", care)
+	}
+	if care.Correlation == nil || *care.Correlation < 0.9 {
+		t.Fatalf("correlation: got %v", care.Correlation)
+	}
+	if care.Message != "Health was better when waterings were on time" {
+		t.Errorf("message: got %q", care.Message)
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+
+	"github.com/golang-jwt/jwt/v5"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// Auth modes selectable with AUTH_MODE
+const (
+	AuthModeFirebase = "firebase"
+	AuthModeLocal    = "local"
+)
+
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+	tokenIssuer      = "water-me"
+)
+
+var (
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailTaken         = errors.New("email already registered")
+)
+
+// Authenticator verifies a bearer token and returns the ID of the user it
+// was issued to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+var (
+	_ Authenticator = (*FirebaseService)(nil)
+	_ Authenticator = (*LocalAuth)(nil)
+)
+
+// Authenticate verifies a Firebase ID token
+func (fs *FirebaseService) Authenticate(ctx context.Context, idToken string) (string, error) {
+	token, err := fs.VerifyIDToken(ctx, idToken)
+	if err != nil {
+		return "", err
+	}
+	return token.UID, nil
+}
+
+// LocalAuth is the self-contained email/password auth mode. It issues
+// short-lived JWT access tokens and longer-lived refresh tokens; changing the
+// password invalidates all refresh tokens issued before.
+type LocalAuth struct {
+	users      UserStore
+	secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// NewLocalAuth creates a LocalAuth that signs tokens with secret
+func NewLocalAuth(users UserStore, secret []byte) *LocalAuth {
+	return &LocalAuth{
+		users:      users,
+		secret:     secret,
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 30 * 24 * time.Hour,
+	}
+}
+
+// NewLocalAuthFromEnv creates a LocalAuth signing with JWT_SECRET
+func NewLocalAuthFromEnv(users UserStore) (*LocalAuth, error) {
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	if len(secret) == 0 {
+		// Fine for a single local process; all sessions end on restart
+		slog.Warn("JWT_SECRET not set, using a random key")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate jwt secret: %w", err)
+		}
+	}
+	return NewLocalAuth(users, secret), nil
+}
+
+// Signup creates an account and logs it in. The request must be validated.
+func (a *LocalAuth) Signup(
+	ctx context.Context,
+	req types.SignupRequest,
+) (*types.LoginResponse, error) {
+	email := normalizeEmail(req.Email)
+
+	existing, err := a.users.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrEmailTaken
+	}
+
+	hash, err := util.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+	id, err := gonanoid.New()
+	if err != nil {
+		return nil, fmt.Errorf("generate user id: %w", err)
+	}
+
+	now := time.Now()
+	user, err := a.users.CreateUser(ctx, types.User{
+		ID:                id,
+		Email:             email,
+		PasswordHash:      hash,
+		PasswordChangedAt: now,
+		CreatedAt:         now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.issueTokens(*user)
+}
+
+// Login verifies the credentials and returns a new token pair
+func (a *LocalAuth) Login(
+	ctx context.Context,
+	req types.LoginRequest,
+) (*types.LoginResponse, error) {
+	user, err := a.users.GetUserByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		// Spend the same time as a wrong password so emails can't be probed
+		_ = util.VerifyPassword(dummyPasswordHash, req.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if err := util.VerifyPassword(user.PasswordHash, req.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return a.issueTokens(*user)
+}
+
+// Refresh exchanges a valid refresh token for a new token pair
+func (a *LocalAuth) Refresh(ctx context.Context, refreshToken string) (*types.LoginResponse, error) {
+	claims, err := a.parse(refreshToken, tokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.users.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || claims.IssuedAt == nil ||
+		claims.IssuedAt.Before(user.PasswordChangedAt.Truncate(time.Second)) {
+		return nil, ErrInvalidToken
+	}
+	return a.issueTokens(*user)
+}
+
+// ChangePassword replaces the password after verifying the current one.
+// The request must be validated.
+func (a *LocalAuth) ChangePassword(
+	ctx context.Context,
+	userID string,
+	req types.ChangePasswordRequest,
+) error {
+	user, err := a.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidCredentials
+	}
+	if err := util.VerifyPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	hash, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	return a.users.UpdateUserPassword(ctx, userID, hash, time.Now())
+}
+
+// Authenticate verifies an access token
+func (a *LocalAuth) Authenticate(_ context.Context, token string) (string, error) {
+	claims, err := a.parse(token, tokenTypeAccess)
+	if err != nil {
+		return "", err
+	}
+	return claims.UserID, nil
+}
+
+func (a *LocalAuth) parse(token, tokenType string) (*types.Claims, error) {
+	claims, err := util.ParseJWT(token, a.secret)
+	if err != nil || claims.TokenType != tokenType || claims.Issuer != tokenIssuer {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (a *LocalAuth) issueTokens(user types.User) (*types.LoginResponse, error) {
+	now := time.Now()
+	accessExpiry := now.Add(a.AccessTTL)
+
+	access, err := util.GenerateJWT(a.claims(user.ID, tokenTypeAccess, now, accessExpiry), a.secret)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := util.GenerateJWT(
+		a.claims(user.ID, tokenTypeRefresh, now, now.Add(a.RefreshTTL)),
+		a.secret,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.LoginResponse{
+		Token:        access,
+		ExpiresAt:    accessExpiry,
+		RefreshToken: refresh,
+		User:         user,
+	}, nil
+}
+
+func (a *LocalAuth) claims(userID, tokenType string, issuedAt, expiresAt time.Time) types.Claims {
+	return types.Claims{
+		UserID:    userID,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// dummyPasswordHash is a bcrypt hash compared against when a login email is unknown
+const dummyPasswordHash = "$2a$12$GTdLHsMFQIBiXaxgeJYYNONYkEKqHl0RMGoYa61sZvLQfyFca0YGO"
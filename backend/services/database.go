@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/qreepex/water-me-app/backend/constants"
@@ -9,6 +10,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -74,9 +76,23 @@ func (m *MongoDB) UpdatePlant(
 	if update.Species != nil {
 		updateDoc["species"] = *update.Species
 	}
+	if update.SpeciesID != nil {
+		if *update.SpeciesID == "" {
+			unsetDoc["speciesId"] = ""
+		} else {
+			updateDoc["speciesId"] = *update.SpeciesID
+		}
+	}
 	if update.IsToxic != nil {
 		updateDoc["isToxic"] = *update.IsToxic
 	}
+	if update.Toxicity != nil {
+		if isEmptyToxicity(*update.Toxicity) {
+			unsetDoc["toxicity"] = ""
+		} else {
+			updateDoc["toxicity"] = *update.Toxicity
+		}
+	}
 	if update.Sunlight != nil {
 		updateDoc["sunlight"] = *update.Sunlight
 	}
@@ -191,8 +207,102 @@ func (m *MongoDB) UpdatePlant(
 	return &plant, true, nil
 }
 
+// ApplyPlantUpdate applies a PATCH request to a plant in memory with the same
+// semantics as UpdatePlant: empty sub-documents and lists clear the field.
+func ApplyPlantUpdate(plant *types.Plant, update types.UpdatePlantRequest, now time.Time) {
+	if update.Name != nil {
+		plant.Name = *update.Name
+	}
+	if update.Species != nil {
+		plant.Species = *update.Species
+	}
+	if update.SpeciesID != nil {
+		plant.SpeciesID = *update.SpeciesID
+	}
+	if update.IsToxic != nil {
+		plant.IsToxic = *update.IsToxic
+	}
+	if update.Toxicity != nil {
+		plant.Toxicity = nil
+		if !isEmptyToxicity(*update.Toxicity) {
+			toxicity := *update.Toxicity
+			plant.Toxicity = &toxicity
+		}
+	}
+	if update.Sunlight != nil {
+		sunlight := *update.Sunlight
+		plant.Sunlight = &sunlight
+	}
+	if update.PreferedTemperature != nil {
+		temperature := *update.PreferedTemperature
+		plant.PreferedTemperature = &temperature
+	}
+	if update.Location != nil {
+		plant.Location = nil
+		if !isEmptyLocation(*update.Location) {
+			location := *update.Location
+			plant.Location = &location
+		}
+	}
+	if update.Watering != nil {
+		plant.Watering = nil
+		if !isEmptyWatering(*update.Watering) {
+			watering := *update.Watering
+			plant.Watering = &watering
+		}
+	}
+	if update.Fertilizing != nil {
+		plant.Fertilizing = nil
+		if !isEmptyFertilizing(*update.Fertilizing) {
+			fertilizing := *update.Fertilizing
+			plant.Fertilizing = &fertilizing
+		}
+	}
+	if update.Humidity != nil {
+		plant.Humidity = nil
+		if !isEmptyHumidity(*update.Humidity) {
+			humidity := *update.Humidity
+			plant.Humidity = &humidity
+		}
+	}
+	if update.Soil != nil {
+		plant.Soil = nil
+		if !isEmptySoil(*update.Soil) {
+			soil := *update.Soil
+			plant.Soil = &soil
+		}
+	}
+	if update.Seasonality != nil {
+		plant.Seasonality = nil
+		if !isEmptySeasonality(*update.Seasonality) {
+			seasonality := *update.Seasonality
+			plant.Seasonality = &seasonality
+		}
+	}
+	if update.PestHistory != nil {
+		plant.PestHistory = *update.PestHistory
+	}
+	if update.Flags != nil {
+		plant.Flags = *update.Flags
+	}
+	if update.Notes != nil {
+		plant.Notes = *update.Notes
+	}
+	if update.PhotoIDs != nil {
+		plant.PhotoIDs = *update.PhotoIDs
+	}
+	if update.GrowthHistory != nil {
+		plant.GrowthHistory = *update.GrowthHistory
+	}
+	plant.UpdatedAt = now
+}
+
 func isEmptyLocation(loc types.Location) bool {
-	return loc.Room == "" && loc.Position == "" && !loc.IsOutdoors
+	return loc.RoomID == "" && loc.Room == "" && loc.Position == "" && !loc.IsOutdoors
+}
+
+func isEmptyToxicity(t types.Toxicity) bool {
+	return t.Cats == "" && t.Dogs == "" && t.Humans == "" && len(t.Symptoms) == 0
 }
 
 func isEmptyWatering(w types.WateringConfig) bool {
@@ -328,6 +438,7 @@ func (m *MongoDB) WaterPlants(
 				"watering.lastWatered": now,
 				"updatedAt":            now,
 			},
+			"$unset": bson.M{"watering.snoozedUntil": ""},
 		},
 	)
 	if err != nil {
@@ -337,6 +448,202 @@ func (m *MongoDB) WaterPlants(
 	return result.ModifiedCount, nil
 }
 
+func (m *MongoDB) GetPlantByID(ctx context.Context, id string) (*types.Plant, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var plant types.Plant
+	if err := collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&plant); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plant, nil
+}
+
+func (m *MongoDB) GetHouseholdPlants(ctx context.Context, householdIDs []string) ([]types.Plant, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+	if len(householdIDs) == 0 {
+		return []types.Plant{}, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"householdId": bson.M{"$in": householdIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	plants := make([]types.Plant, 0)
+	if err := cursor.All(ctx, &plants); err != nil {
+		return nil, err
+	}
+	return plants, nil
+}
+
+func (m *MongoDB) SnoozeWatering(ctx context.Context, id, userID string, until time.Time) (bool, error) {
+	return m.setWateringField(ctx, id, userID, "watering.snoozedUntil", until)
+}
+
+func (m *MongoDB) SetWateringInterval(ctx context.Context, id, userID string, days int) (bool, error) {
+	return m.setWateringField(ctx, id, userID, "watering.intervalDays", days)
+}
+
+// setWateringField sets one field of the watering config of a plant with a
+// watering interval
+func (m *MongoDB) setWateringField(ctx context.Context, id, userID, field string, value any) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "userId": userID, "watering.intervalDays": bson.M{"$gt": 0}},
+		bson.M{"$set": bson.M{field: value, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) SetPlantHousehold(ctx context.Context, id, userID, householdID string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+
+	update := bson.M{"$set": bson.M{"householdId": householdID, "updatedAt": time.Now()}}
+	if householdID == "" {
+		update = bson.M{
+			"$unset": bson.M{"householdId": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		}
+	}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": objectID, "userId": userID}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) ClearPlantHousehold(ctx context.Context, householdID, userID string) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	filter := bson.M{"householdId": householdID}
+	if userID != "" {
+		filter["userId"] = userID
+	}
+	result, err := collection.UpdateMany(ctx, filter, bson.M{
+		"$unset": bson.M{"householdId": ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (m *MongoDB) MovePlantsToRoom(
+	ctx context.Context,
+	userID string,
+	plantIDs []string,
+	room types.Room,
+) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	objectIDs := make([]primitive.ObjectID, 0, len(plantIDs))
+	for _, id := range plantIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	if len(objectIDs) == 0 {
+		return 0, nil
+	}
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": objectIDs}, "userId": userID},
+		bson.M{"$set": roomLocationFields(room)},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (m *MongoDB) SyncRoomPlants(ctx context.Context, room types.Room) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"userId": room.UserID, "location.roomId": room.ID},
+		bson.M{"$set": roomLocationFields(room)},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (m *MongoDB) ClearPlantRoom(ctx context.Context, userID, roomID string) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "location.roomId": roomID},
+		bson.M{
+			"$unset": bson.M{"location.roomId": ""},
+			"$set":   bson.M{"location.room": "", "updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func roomLocationFields(room types.Room) bson.M {
+	return bson.M{
+		"location.roomId":     room.ID,
+		"location.room":       room.Name,
+		"location.isOutdoors": room.IsOutdoors,
+		"updatedAt":           time.Now(),
+	}
+}
+
 // --- Uploads ---
 
 // GetUserUploadCount returns number of uploads for a user
@@ -364,6 +671,93 @@ func (m *MongoDB) RegisterUpload(ctx context.Context, userID string, key string,
 	return err
 }
 
+// GetUploadsCreatedBefore returns all uploads registered before cutoff
+func (m *MongoDB) GetUploadsCreatedBefore(
+	ctx context.Context,
+	cutoff time.Time,
+) ([]types.Upload, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Uploads)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"createdAt": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var uploads []types.Upload
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// DeleteUploadRecords removes the upload records for the given object keys
+func (m *MongoDB) DeleteUploadRecords(ctx context.Context, keys []string) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Uploads)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	result, err := collection.DeleteMany(ctx, bson.M{"key": bson.M{"$in": keys}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// IsUploadReferenced checks if an upload is referenced in any plant
+func (m *MongoDB) IsUploadReferenced(ctx context.Context, key string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Plants)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"photoIds": key})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetStorageUsageByUser returns the total registered upload bytes per user
+func (m *MongoDB) GetStorageUsageByUser(ctx context.Context) (map[string]int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Uploads)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$group": bson.M{
+			"_id":   "$userId",
+			"bytes": bson.M{"$sum": "$sizeBytes"},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		UserID string `bson:"_id"`
+		Bytes  int64  `bson:"bytes"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]int64, len(results))
+	for _, result := range results {
+		usage[result.UserID] = result.Bytes
+	}
+	return usage, nil
+}
+
 // NotificationConfig methods
 
 func (m *MongoDB) GetNotificationConfig(
@@ -485,6 +879,8 @@ func (m *MongoDB) GetPlantsNeedingWatering(ctx context.Context, limit int) ([]ty
 		bson.M{"$match": bson.M{
 			"watering":              bson.M{"$exists": true, "$ne": nil},
 			"watering.intervalDays": bson.M{"$gt": 0},
+			// Matches plants that were never snoozed, too
+			"watering.snoozedUntil": bson.M{"$not": bson.M{"$gt": now}},
 		}},
 		bson.M{"$addFields": bson.M{
 			"nextWateringDate": bson.M{
@@ -728,3 +1124,1332 @@ func (m *MongoDB) MarkTokensAsInactive(ctx context.Context, userID string, token
 
 	return err
 }
+
+// --- Users ---
+
+func (m *MongoDB) CreateUser(ctx context.Context, user types.User) (*types.User, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Users)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, user); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (m *MongoDB) GetUserByEmail(ctx context.Context, email string) (*types.User, error) {
+	return m.findUser(ctx, bson.M{"email": email})
+}
+
+func (m *MongoDB) GetUserByID(ctx context.Context, id string) (*types.User, error) {
+	return m.findUser(ctx, bson.M{"_id": id})
+}
+
+func (m *MongoDB) findUser(ctx context.Context, filter bson.M) (*types.User, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Users)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var user types.User
+	if err := collection.FindOne(ctx, filter).Decode(&user); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (m *MongoDB) UpdateUserPassword(
+	ctx context.Context,
+	userID, hash string,
+	changedAt time.Time,
+) error {
+	collection := m.GetCollection(constants.MongoDBCollections.Users)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"passwordHash": hash, "passwordChangedAt": changedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return types.ErrNoDocuments
+	}
+	return nil
+}
+
+// --- API tokens ---
+
+func (m *MongoDB) CreateAPIToken(ctx context.Context, token types.APIToken) (*types.APIToken, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.APITokens)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (m *MongoDB) GetAPITokens(ctx context.Context, userID string) ([]types.APIToken, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.APITokens)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := make([]types.APIToken, 0)
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (m *MongoDB) GetAPITokenByHash(ctx context.Context, hash string) (*types.APIToken, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.APITokens)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var token types.APIToken
+	if err := collection.FindOne(ctx, bson.M{"hash": hash}).Decode(&token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (m *MongoDB) DeleteAPIToken(ctx context.Context, userID string, id string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.APITokens)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (m *MongoDB) TouchAPIToken(ctx context.Context, id string, usedAt time.Time) error {
+	collection := m.GetCollection(constants.MongoDBCollections.APITokens)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lastUsedAt": usedAt}},
+	)
+	return err
+}
+
+// --- Households ---
+
+func (m *MongoDB) CreateHousehold(
+	ctx context.Context,
+	household types.Household,
+) (*types.Household, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, household); err != nil {
+		return nil, err
+	}
+	return &household, nil
+}
+
+func (m *MongoDB) GetHousehold(ctx context.Context, id string) (*types.Household, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var household types.Household
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&household); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &household, nil
+}
+
+func (m *MongoDB) GetHouseholdsForUser(ctx context.Context, userID string) ([]types.Household, error) {
+	return m.findHouseholds(ctx, bson.M{"members.userId": userID})
+}
+
+// GetHouseholdsByIDs is used by the notification worker to fan reminders out
+// to household members
+func (m *MongoDB) GetHouseholdsByIDs(ctx context.Context, ids []string) ([]types.Household, error) {
+	if len(ids) == 0 {
+		return []types.Household{}, nil
+	}
+	return m.findHouseholds(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (m *MongoDB) findHouseholds(ctx context.Context, filter bson.M) ([]types.Household, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	households := make([]types.Household, 0)
+	if err := cursor.All(ctx, &households); err != nil {
+		return nil, err
+	}
+	return households, nil
+}
+
+func (m *MongoDB) DeleteHousehold(ctx context.Context, id string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	invites := m.GetCollection(constants.MongoDBCollections.HouseholdInvites)
+	if collection == nil || invites == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	if _, err := invites.DeleteMany(ctx, bson.M{"householdId": id}); err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (m *MongoDB) AddHouseholdMember(
+	ctx context.Context,
+	householdID string,
+	member types.HouseholdMember,
+) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	// The filter makes the check and the push one atomic operation
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": householdID, "members.userId": bson.M{"$ne": member.UserID}},
+		bson.M{
+			"$push": bson.M{"members": member},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (m *MongoDB) UpdateHouseholdMemberRole(
+	ctx context.Context,
+	householdID, userID string,
+	role types.HouseholdRole,
+) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": householdID, "members.userId": userID},
+		bson.M{"$set": bson.M{"members.$.role": role, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) RemoveHouseholdMember(ctx context.Context, householdID, userID string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": householdID, "members.userId": userID},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"userId": userID}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (m *MongoDB) SetHouseholdProfile(
+	ctx context.Context,
+	householdID string,
+	profile types.SafetyProfile,
+) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Households)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": householdID},
+		bson.M{"$set": bson.M{"profile": profile, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) CreateHouseholdInvite(
+	ctx context.Context,
+	invite types.HouseholdInvite,
+) (*types.HouseholdInvite, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.HouseholdInvites)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, invite); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (m *MongoDB) GetHouseholdInvites(
+	ctx context.Context,
+	householdID string,
+) ([]types.HouseholdInvite, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.HouseholdInvites)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"householdId": householdID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invites := make([]types.HouseholdInvite, 0)
+	if err := cursor.All(ctx, &invites); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (m *MongoDB) GetHouseholdInviteByCode(
+	ctx context.Context,
+	code string,
+) (*types.HouseholdInvite, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.HouseholdInvites)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var invite types.HouseholdInvite
+	if err := collection.FindOne(ctx, bson.M{"code": code}).Decode(&invite); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (m *MongoDB) DeleteHouseholdInvite(ctx context.Context, householdID, id string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.HouseholdInvites)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id, "householdId": householdID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// --- Care events ---
+
+func (m *MongoDB) LogCareEvents(ctx context.Context, events []types.CareEvent) error {
+	collection := m.GetCollection(constants.MongoDBCollections.CareEvents)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(events))
+	for i, event := range events {
+		docs[i] = event
+	}
+	_, err := collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (m *MongoDB) GetCareEvents(
+	ctx context.Context,
+	ownerID string,
+	from, to time.Time,
+) ([]types.CareEvent, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.CareEvents)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	filter := bson.M{"ownerId": ownerID, "at": bson.M{"$gte": from, "$lt": to}}
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]types.CareEvent, 0)
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// --- Vacations ---
+
+func (m *MongoDB) CreateVacation(ctx context.Context, vacation types.Vacation) (*types.Vacation, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, vacation); err != nil {
+		return nil, err
+	}
+	return &vacation, nil
+}
+
+func (m *MongoDB) GetVacations(ctx context.Context, userID string) ([]types.Vacation, error) {
+	return m.findVacations(ctx, bson.M{"userId": userID},
+		options.Find().SetSort(bson.D{{Key: "startsAt", Value: -1}}))
+}
+
+func (m *MongoDB) GetVacation(ctx context.Context, userID, id string) (*types.Vacation, error) {
+	return m.findVacation(ctx, bson.M{"_id": id, "userId": userID})
+}
+
+func (m *MongoDB) GetVacationByCode(ctx context.Context, code string) (*types.Vacation, error) {
+	return m.findVacation(ctx, bson.M{"inviteCode": code})
+}
+
+func (m *MongoDB) SetVacationCaretaker(
+	ctx context.Context,
+	id, caretakerID, caretakerName string,
+) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	set := bson.M{"caretakerId": caretakerID}
+	if caretakerName != "" {
+		set["caretakerName"] = caretakerName
+	}
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "caretakerId": bson.M{"$exists": false}},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (m *MongoDB) EndVacation(ctx context.Context, userID, id string, endsAt time.Time) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "userId": userID},
+		bson.M{"$set": bson.M{"endsAt": endsAt}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) DeleteVacation(ctx context.Context, userID, id string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (m *MongoDB) GetCaretakerVacations(
+	ctx context.Context,
+	caretakerID string,
+	now time.Time,
+) ([]types.Vacation, error) {
+	return m.findVacations(ctx, bson.M{
+		"caretakerId": caretakerID,
+		"startsAt":    bson.M{"$lte": now},
+		"endsAt":      bson.M{"$gt": now},
+	}, nil)
+}
+
+// GetActiveVacations returns the vacations of the given users running at now.
+// The notification worker uses it to redirect reminders to caretakers.
+func (m *MongoDB) GetActiveVacations(
+	ctx context.Context,
+	userIDs []string,
+	now time.Time,
+) ([]types.Vacation, error) {
+	if len(userIDs) == 0 {
+		return []types.Vacation{}, nil
+	}
+	return m.findVacations(ctx, bson.M{
+		"userId":   bson.M{"$in": userIDs},
+		"startsAt": bson.M{"$lte": now},
+		"endsAt":   bson.M{"$gt": now},
+	}, nil)
+}
+
+// GetVacationsNeedingSummary returns ended vacations whose owner has not been
+// sent a summary yet
+func (m *MongoDB) GetVacationsNeedingSummary(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]types.Vacation, error) {
+	return m.findVacations(ctx,
+		bson.M{"endsAt": bson.M{"$lte": now}, "summarySentAt": bson.M{"$exists": false}},
+		options.Find().SetSort(bson.D{{Key: "endsAt", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+func (m *MongoDB) MarkVacationSummarySent(ctx context.Context, id string, sentAt time.Time) error {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"summarySentAt": sentAt}},
+	)
+	return err
+}
+
+func (m *MongoDB) findVacation(ctx context.Context, filter bson.M) (*types.Vacation, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var vacation types.Vacation
+	if err := collection.FindOne(ctx, filter).Decode(&vacation); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &vacation, nil
+}
+
+func (m *MongoDB) findVacations(
+	ctx context.Context,
+	filter bson.M,
+	opts *options.FindOptions,
+) ([]types.Vacation, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	vacations := make([]types.Vacation, 0)
+	if err := cursor.All(ctx, &vacations); err != nil {
+		return nil, err
+	}
+	return vacations, nil
+}
+
+// --- Plant shares ---
+
+func (m *MongoDB) CreatePlantShare(ctx context.Context, share types.PlantShare) (*types.PlantShare, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PlantShares)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (m *MongoDB) GetPlantShares(ctx context.Context, plantID string) ([]types.PlantShare, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PlantShares)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"plantId": plantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	shares := make([]types.PlantShare, 0)
+	if err := cursor.All(ctx, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (m *MongoDB) GetPlantShareByToken(ctx context.Context, token string) (*types.PlantShare, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PlantShares)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var share types.PlantShare
+	if err := collection.FindOne(ctx, bson.M{"token": token}).Decode(&share); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (m *MongoDB) DeletePlantShare(ctx context.Context, plantID, id string) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PlantShares)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id, "plantId": plantID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// --- Exports ---
+
+func (m *MongoDB) CreateExport(ctx context.Context, export types.DataExport) (*types.DataExport, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Exports)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, export); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrExportInProgress
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (m *MongoDB) GetExports(ctx context.Context, userID string) ([]types.DataExport, error) {
+	return m.findExports(ctx, bson.M{"userId": userID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+}
+
+func (m *MongoDB) GetExport(ctx context.Context, userID, id string) (*types.DataExport, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Exports)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var export types.DataExport
+	err := collection.FindOne(ctx, bson.M{"_id": id, "userId": userID}).Decode(&export)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (m *MongoDB) UpdateExport(ctx context.Context, export types.DataExport) error {
+	collection := m.GetCollection(constants.MongoDBCollections.Exports)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": export.ID}, export)
+	return err
+}
+
+func (m *MongoDB) GetExpiredExports(ctx context.Context, now time.Time) ([]types.DataExport, error) {
+	return m.findExports(ctx, bson.M{"expiresAt": bson.M{"$lte": now}}, nil)
+}
+
+func (m *MongoDB) DeleteExport(ctx context.Context, id string) error {
+	_, err := m.deleteMany(ctx, constants.MongoDBCollections.Exports, bson.M{"_id": id})
+	return err
+}
+
+func (m *MongoDB) findExports(
+	ctx context.Context,
+	filter bson.M,
+	opts *options.FindOptions,
+) ([]types.DataExport, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Exports)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	exports := make([]types.DataExport, 0)
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// --- Species ---
+
+func (m *MongoDB) SearchSpecies(ctx context.Context, query string, limit int) ([]types.Species, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Species)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	filter := bson.M{}
+	if query != "" {
+		pattern := primitive.Regex{Pattern: SpeciesSearchPattern(query)}
+		filter = bson.M{"$or": bson.A{
+			bson.M{"scientificName": pattern},
+			bson.M{"commonNames.name": pattern},
+		}}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "scientificName", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	species := make([]types.Species, 0)
+	if err := cursor.All(ctx, &species); err != nil {
+		return nil, err
+	}
+	return species, nil
+}
+
+func (m *MongoDB) GetSpecies(ctx context.Context, id string) (*types.Species, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Species)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var species types.Species
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&species); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &species, nil
+}
+
+func (m *MongoDB) UpsertSpecies(ctx context.Context, species types.Species) error {
+	collection := m.GetCollection(constants.MongoDBCollections.Species)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"_id": species.ID},
+		species,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// --- Rooms ---
+
+func (m *MongoDB) CreateRoom(ctx context.Context, room types.Room) (*types.Room, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Rooms)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, room); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrRoomNameTaken
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (m *MongoDB) GetRooms(ctx context.Context, userID string) ([]types.Room, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Rooms)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "nameKey", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rooms := make([]types.Room, 0)
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func (m *MongoDB) GetRoom(ctx context.Context, userID, id string) (*types.Room, error) {
+	return m.findRoom(ctx, bson.M{"_id": id, "userId": userID})
+}
+
+func (m *MongoDB) GetRoomByName(ctx context.Context, userID, name string) (*types.Room, error) {
+	return m.findRoom(ctx, bson.M{"userId": userID, "nameKey": types.RoomNameKey(name)})
+}
+
+func (m *MongoDB) findRoom(ctx context.Context, filter bson.M) (*types.Room, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Rooms)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var room types.Room
+	if err := collection.FindOne(ctx, filter).Decode(&room); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (m *MongoDB) UpdateRoom(ctx context.Context, room types.Room) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Rooms)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.ReplaceOne(ctx, bson.M{"_id": room.ID, "userId": room.UserID}, room)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, ErrRoomNameTaken
+		}
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) DeleteRoom(ctx context.Context, userID, id string) (bool, error) {
+	deleted, err := m.deleteMany(ctx, constants.MongoDBCollections.Rooms, bson.M{"_id": id, "userId": userID})
+	return deleted > 0, err
+}
+
+// --- Pest outbreaks ---
+
+func (m *MongoDB) CreateOutbreak(ctx context.Context, outbreak types.PestOutbreak) (*types.PestOutbreak, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PestOutbreaks)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, outbreak); err != nil {
+		return nil, err
+	}
+	return &outbreak, nil
+}
+
+func (m *MongoDB) GetOutbreaks(ctx context.Context, userID string) ([]types.PestOutbreak, error) {
+	return m.findOutbreaks(ctx,
+		bson.M{"userId": userID},
+		options.Find().SetSort(bson.D{{Key: "detectedAt", Value: -1}}),
+	)
+}
+
+func (m *MongoDB) AddOutbreakPlant(
+	ctx context.Context,
+	id string,
+	plant types.OutbreakPlant,
+	room string,
+	now time.Time,
+) (*types.PestOutbreak, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PestOutbreaks)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	result := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": types.OutbreakOpen},
+		bson.M{
+			"$addToSet": bson.M{"infected": plant},
+			"$set":      bson.M{"room": room, "updatedAt": now},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	)
+	var outbreak types.PestOutbreak
+	if err := result.Decode(&outbreak); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &outbreak, nil
+}
+
+func (m *MongoDB) SetOutbreakAtRisk(
+	ctx context.Context,
+	id string,
+	infected int,
+	atRisk []types.OutbreakPlant,
+	quarantine string,
+	now time.Time,
+) (bool, error) {
+	return m.updateOutbreak(ctx,
+		bson.M{"_id": id, "status": types.OutbreakOpen, "infected": bson.M{"$size": infected}},
+		bson.M{"$set": bson.M{"atRisk": atRisk, "quarantine": quarantine, "updatedAt": now}},
+	)
+}
+
+func (m *MongoDB) ContainOutbreak(ctx context.Context, id string, infected int, now time.Time) (bool, error) {
+	return m.updateOutbreak(ctx,
+		bson.M{"_id": id, "status": types.OutbreakOpen, "infected": bson.M{"$size": infected}},
+		bson.M{
+			"$set":   bson.M{"status": types.OutbreakContained, "containedAt": now, "updatedAt": now},
+			"$unset": bson.M{"nextInspectionAt": ""},
+		},
+	)
+}
+
+func (m *MongoDB) GetOutbreaksNeedingInspection(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]types.PestOutbreak, error) {
+	return m.findOutbreaks(ctx,
+		bson.M{"status": types.OutbreakOpen, "nextInspectionAt": bson.M{"$lte": now}},
+		options.Find().SetSort(bson.D{{Key: "nextInspectionAt", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+func (m *MongoDB) MarkOutbreakInspected(
+	ctx context.Context,
+	id string,
+	sent int,
+	next *time.Time,
+	now time.Time,
+) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PestOutbreaks)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	// Only the schedule is written, so containment or new plants recorded by
+	// the API in the meantime are kept
+	update := bson.M{"$set": bson.M{"inspectionsSent": sent, "updatedAt": now}}
+	if next != nil {
+		update["$set"].(bson.M)["nextInspectionAt"] = *next
+	} else {
+		update["$unset"] = bson.M{"nextInspectionAt": ""}
+	}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": id, "status": types.OutbreakOpen}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) updateOutbreak(ctx context.Context, filter, update bson.M) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PestOutbreaks)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) findOutbreaks(
+	ctx context.Context,
+	filter bson.M,
+	opts *options.FindOptions,
+) ([]types.PestOutbreak, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.PestOutbreaks)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	outbreaks := make([]types.PestOutbreak, 0)
+	if err := cursor.All(ctx, &outbreaks); err != nil {
+		return nil, err
+	}
+	return outbreaks, nil
+}
+
+// --- Treatment plans ---
+
+func (m *MongoDB) CreateTreatmentPlan(ctx context.Context, plan types.TreatmentPlan) (*types.TreatmentPlan, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.TreatmentPlans)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	if _, err := collection.InsertOne(ctx, plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (m *MongoDB) GetTreatmentPlan(ctx context.Context, id string) (*types.TreatmentPlan, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.TreatmentPlans)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var plan types.TreatmentPlan
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&plan); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (m *MongoDB) GetTreatmentPlans(ctx context.Context, plantID string) ([]types.TreatmentPlan, error) {
+	return m.findTreatmentPlans(ctx,
+		bson.M{"plantId": plantID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}),
+	)
+}
+
+func (m *MongoDB) LogTreatmentApplication(ctx context.Context, plan types.TreatmentPlan) (bool, error) {
+	if len(plan.Applications) == 0 {
+		return false, nil
+	}
+	set := bson.M{"status": plan.Status, "updatedAt": plan.UpdatedAt}
+	unset := bson.M{}
+	for field, value := range map[string]*time.Time{
+		"nextDueAt":      plan.NextDueAt,
+		"nextReminderAt": plan.NextReminderAt,
+		"endedAt":        plan.EndedAt,
+	} {
+		if value != nil {
+			set[field] = *value
+		} else {
+			unset[field] = ""
+		}
+	}
+	update := bson.M{
+		"$push": bson.M{"applications": plan.Applications[len(plan.Applications)-1]},
+		"$set":  set,
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	// The size guard keeps two applications logged at once from both
+	// computing the schedule from the same earlier state
+	filter := bson.M{
+		"_id":          plan.ID,
+		"status":       types.TreatmentActive,
+		"applications": bson.M{"$size": len(plan.Applications) - 1},
+	}
+	return m.updateTreatmentPlan(ctx, filter, update)
+}
+
+func (m *MongoDB) RescheduleTreatmentReminder(
+	ctx context.Context,
+	id string,
+	due, next time.Time,
+	now time.Time,
+) (bool, error) {
+	return m.updateTreatmentPlan(ctx,
+		bson.M{"_id": id, "status": types.TreatmentActive, "nextReminderAt": due},
+		bson.M{"$set": bson.M{"nextReminderAt": next, "updatedAt": now}},
+	)
+}
+
+func (m *MongoDB) StopTreatmentPlan(ctx context.Context, id string, now time.Time) (bool, error) {
+	return m.updateTreatmentPlan(ctx,
+		bson.M{"_id": id, "status": types.TreatmentActive},
+		bson.M{
+			"$set":   bson.M{"status": types.TreatmentStopped, "endedAt": now, "updatedAt": now},
+			"$unset": bson.M{"nextDueAt": "", "nextReminderAt": ""},
+		},
+	)
+}
+
+func (m *MongoDB) updateTreatmentPlan(ctx context.Context, filter, update bson.M) (bool, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.TreatmentPlans)
+	if collection == nil {
+		return false, types.ErrNoDocuments
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MongoDB) GetTreatmentPlansNeedingReminder(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]types.TreatmentPlan, error) {
+	return m.findTreatmentPlans(ctx,
+		bson.M{"status": types.TreatmentActive, "nextReminderAt": bson.M{"$lte": now}},
+		options.Find().SetSort(bson.D{{Key: "nextReminderAt", Value: 1}}).SetLimit(int64(limit)),
+	)
+}
+
+func (m *MongoDB) findTreatmentPlans(
+	ctx context.Context,
+	filter bson.M,
+	opts *options.FindOptions,
+) ([]types.TreatmentPlan, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.TreatmentPlans)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	plans := make([]types.TreatmentPlan, 0)
+	if err := cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// --- Safety profiles ---
+
+func (m *MongoDB) GetSafetyProfile(ctx context.Context, userID string) (*types.SafetyProfile, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.SafetyProfiles)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var profile types.SafetyProfile
+	if err := collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&profile); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (m *MongoDB) PutSafetyProfile(ctx context.Context, profile types.SafetyProfile) error {
+	collection := m.GetCollection(constants.MongoDBCollections.SafetyProfiles)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"_id": profile.UserID},
+		profile,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// --- Account erasure ---
+
+func (m *MongoDB) DeleteUserPlants(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.Plants, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserUploads(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.Uploads, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserAPITokens(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.APITokens, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserVacations(ctx context.Context, userID string) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.Vacations)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"caretakerId": userID},
+		bson.M{"$unset": bson.M{"caretakerId": "", "caretakerName": ""}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return m.deleteMany(ctx, constants.MongoDBCollections.Vacations, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserCareEvents(ctx context.Context, userID string) (int64, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.CareEvents)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "ownerId": bson.M{"$ne": userID}},
+		bson.M{"$set": bson.M{"userId": ""}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return m.deleteMany(ctx, constants.MongoDBCollections.CareEvents, bson.M{"ownerId": userID})
+}
+
+func (m *MongoDB) DeleteUserPlantShares(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.PlantShares, bson.M{"ownerId": userID})
+}
+
+func (m *MongoDB) DeleteUserExports(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.Exports, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserSafetyProfile(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.SafetyProfiles, bson.M{"_id": userID})
+}
+
+func (m *MongoDB) DeleteUserRooms(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.Rooms, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserOutbreaks(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.PestOutbreaks, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUserTreatmentPlans(ctx context.Context, userID string) (int64, error) {
+	return m.deleteMany(ctx, constants.MongoDBCollections.TreatmentPlans, bson.M{"userId": userID})
+}
+
+func (m *MongoDB) DeleteUser(ctx context.Context, userID string) (bool, error) {
+	deleted, err := m.deleteMany(ctx, constants.MongoDBCollections.Users, bson.M{"_id": userID})
+	return deleted > 0, err
+}
+
+func (m *MongoDB) StartAccountErasure(ctx context.Context, userHash string, at time.Time) error {
+	collection := m.GetCollection(constants.MongoDBCollections.AccountErasures)
+	if collection == nil {
+		return types.ErrNoDocuments
+	}
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": userHash},
+		bson.M{
+			"$setOnInsert": bson.M{"startedAt": at},
+			"$inc":         bson.M{"attempts": 1},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (m *MongoDB) CompleteAccountErasure(
+	ctx context.Context,
+	userHash string,
+	at time.Time,
+) (*types.AccountErasure, error) {
+	collection := m.GetCollection(constants.MongoDBCollections.AccountErasures)
+	if collection == nil {
+		return nil, types.ErrNoDocuments
+	}
+
+	var erasure types.AccountErasure
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": userHash},
+		bson.M{"$set": bson.M{"completedAt": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&erasure)
+	if err != nil {
+		return nil, err
+	}
+	return &erasure, nil
+}
+
+func (m *MongoDB) deleteMany(ctx context.Context, name string, filter bson.M) (int64, error) {
+	collection := m.GetCollection(name)
+	if collection == nil {
+		return 0, types.ErrNoDocuments
+	}
+
+	result, err := collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
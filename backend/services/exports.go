@@ -0,0 +1,318 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	// ExportRetention is how long a finished archive can be downloaded before
+	// the cleanup worker deletes it
+	ExportRetention = 7 * 24 * time.Hour
+	// MaxExportsPerDay limits how often a user can start an export
+	MaxExportsPerDay = 3
+	// exportTimeout bounds building one archive
+	exportTimeout = 20 * time.Minute
+	// exportStaleAfter is how long an unfinished export may go without progress
+	// before it counts as interrupted, e.g. by a restart of the API
+	exportStaleAfter = 30 * time.Minute
+)
+
+var (
+	ErrExportInProgress = errors.New("an export is already in progress")
+	ErrExportLimit      = errors.New("export limit reached")
+)
+
+// Exporter builds ZIP archives of all of a user's data: plants with their
+// growth and pest history, rooms, the notification config without device
+// tokens, care events, treatment plans and the original photos.
+type Exporter struct {
+	store   Store
+	objects ObjectStore
+}
+
+// NewExporter creates an Exporter
+func NewExporter(store Store, objects ObjectStore) *Exporter {
+	return &Exporter{store: store, objects: objects}
+}
+
+// MarkStale flags an unfinished export without recent progress as failed and
+// reports whether it did
+func MarkStale(export *types.DataExport, now time.Time) bool {
+	if !export.Active() || now.Sub(export.UpdatedAt) < exportStaleAfter {
+		return false
+	}
+	export.Status = types.ExportFailed
+	export.Error = "Export was interrupted"
+	export.UpdatedAt = now
+	return true
+}
+
+// Start creates an export for userID and builds it in the background. It
+// returns ErrExportInProgress or ErrExportLimit if no export may be started.
+// The store rejects a second active export, also one started concurrently.
+func (e *Exporter) Start(ctx context.Context, userID string) (*types.DataExport, error) {
+	exports, err := e.store.GetExports(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recent := 0
+	for _, export := range exports {
+		if MarkStale(&export, now) {
+			expires := now.Add(ExportRetention)
+			export.ExpiresAt = &expires
+			if err := e.store.UpdateExport(ctx, export); err != nil {
+				return nil, err
+			}
+		}
+		if export.Active() {
+			return nil, ErrExportInProgress
+		}
+		if now.Sub(export.CreatedAt) < 24*time.Hour {
+			recent++
+		}
+	}
+	if recent >= MaxExportsPerDay {
+		return nil, ErrExportLimit
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		return nil, fmt.Errorf("generate export id: %w", err)
+	}
+	export, err := e.store.CreateExport(ctx, types.DataExport{
+		ID:        id,
+		UserID:    userID,
+		Status:    types.ExportPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Detached from the request, which ends before the archive is ready
+	go func(export types.DataExport) {
+		runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportTimeout)
+		defer cancel()
+		e.run(runCtx, export)
+	}(*export)
+
+	return export, nil
+}
+
+// run builds the archive and records the outcome
+func (e *Exporter) run(ctx context.Context, export types.DataExport) {
+	export.Status = types.ExportRunning
+	e.save(ctx, &export)
+
+	err := e.build(ctx, &export)
+
+	now := time.Now()
+	expires := now.Add(ExportRetention)
+	export.ExpiresAt = &expires
+	if err != nil {
+		slog.ErrorContext(ctx, "data export failed", "exportId", export.ID, "error", err)
+		export.Status = types.ExportFailed
+		export.Error = "Export failed"
+	} else {
+		slog.InfoContext(ctx, "data export completed", "exportId", export.ID, "bytes", export.SizeBytes)
+		export.Status = types.ExportCompleted
+		export.CompletedAt = &now
+	}
+	e.save(ctx, &export)
+}
+
+func (e *Exporter) build(ctx context.Context, export *types.DataExport) error {
+	userID := export.UserID
+
+	plants, err := e.store.GetPlants(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get plants: %w", err)
+	}
+	rooms, err := e.store.GetRooms(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get rooms: %w", err)
+	}
+	config, err := e.store.GetNotificationConfig(ctx, userID)
+	if err != nil && !errors.Is(err, types.ErrNoDocuments) {
+		return fmt.Errorf("get notification config: %w", err)
+	}
+	events, err := e.store.GetCareEvents(ctx, userID, time.Time{}, time.Now())
+	if err != nil {
+		return fmt.Errorf("get care events: %w", err)
+	}
+	treatments := make([]types.TreatmentPlan, 0)
+	for _, plant := range plants {
+		plans, err := e.store.GetTreatmentPlans(ctx, plant.ID)
+		if err != nil {
+			return fmt.Errorf("get treatment plans: %w", err)
+		}
+		treatments = append(treatments, plans...)
+	}
+
+	for _, plant := range plants {
+		for _, key := range plant.PhotoIDs {
+			if KeyBelongsToUser(key, userID) {
+				export.PhotosTotal++
+			}
+		}
+	}
+	e.save(ctx, export)
+
+	tmp, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	archive := zip.NewWriter(tmp)
+	for _, plant := range plants {
+		if err := writeJSONEntry(archive, "plants/"+exportName(plant)+".json", plant); err != nil {
+			return err
+		}
+	}
+	if err := writeJSONEntry(archive, "rooms.json", rooms); err != nil {
+		return err
+	}
+	if config != nil {
+		if err := writeJSONEntry(archive, "notifications.json", redactDeviceTokens(*config)); err != nil {
+			return err
+		}
+	}
+	if err := writeJSONEntry(archive, "care-events.json", events); err != nil {
+		return err
+	}
+	if err := writeJSONEntry(archive, "treatment-plans.json", treatments); err != nil {
+		return err
+	}
+
+	for _, plant := range plants {
+		for _, key := range plant.PhotoIDs {
+			if !KeyBelongsToUser(key, userID) {
+				continue
+			}
+			if err := e.addPhoto(ctx, archive, "photos/"+exportName(plant)+"/"+path.Base(key), key); err != nil {
+				// A missing photo must not fail the whole export
+				slog.WarnContext(ctx, "skipped photo in data export", "exportId", export.ID, "error", err)
+			}
+			export.PhotosDone++
+			e.save(ctx, export)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("size archive: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind archive: %w", err)
+	}
+
+	key := UserPrefix(userID) + "exports/" + export.ID + ".zip"
+	if err := e.objects.PutObject(ctx, key, "application/zip", tmp, size); err != nil {
+		return err
+	}
+	export.Key = key
+	export.SizeBytes = size
+	return nil
+}
+
+func (e *Exporter) addPhoto(ctx context.Context, archive *zip.Writer, name, key string) error {
+	body, err := e.objects.GetObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// Photos are already compressed
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	return nil
+}
+
+// save persists progress; a failed write only delays the status the user sees
+func (e *Exporter) save(ctx context.Context, export *types.DataExport) {
+	export.UpdatedAt = time.Now()
+	if err := e.store.UpdateExport(ctx, *export); err != nil {
+		slog.WarnContext(ctx, "failed to update data export", "exportId", export.ID, "error", err)
+	}
+}
+
+// DeleteExpired deletes archives past their retention and their records
+func (e *Exporter) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	exports, err := e.store.GetExpiredExports(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, export := range exports {
+		if export.Key != "" {
+			if err := e.objects.DeleteObject(ctx, export.Key); err != nil {
+				return deleted, err
+			}
+		}
+		if err := e.store.DeleteExport(ctx, export.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// exportName names a plant's entries in the archive
+func exportName(plant types.Plant) string {
+	if plant.Slug != "" {
+		return plant.Slug
+	}
+	return plant.ID
+}
+
+func writeJSONEntry(archive *zip.Writer, name string, v any) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// redactDeviceTokens keeps the devices but drops the push tokens, which are
+// credentials
+func redactDeviceTokens(config types.NotificationConfig) types.NotificationConfig {
+	devices := make([]types.DeviceToken, len(config.DeviceTokens))
+	for i, device := range config.DeviceTokens {
+		device.Token = ""
+		devices[i] = device
+	}
+	config.DeviceTokens = devices
+	return config
+}
@@ -0,0 +1,45 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/services/memstore"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// interleavedExports runs during once after the exports were read, like a
+// second export request handled at the same time
+type interleavedExports struct {
+	*memstore.Store
+	during func()
+}
+
+func (s *interleavedExports) GetExports(ctx context.Context, userID string) ([]types.DataExport, error) {
+	exports, err := s.Store.GetExports(ctx, userID)
+	if during := s.during; during != nil {
+		s.during = nil
+		during()
+	}
+	return exports, err
+}
+
+func TestExporter_StartsOneExportAtATime(t *testing.T) {
+	ctx := context.Background()
+	store := &interleavedExports{Store: memstore.New()}
+	exporter := services.NewExporter(store, memstore.NewObjectStore())
+
+	var concurrent error
+	store.during = func() {
+		_, concurrent = exporter.Start(ctx, "user1")
+	}
+	_, err := exporter.Start(ctx, "user1")
+	if concurrent != nil {
+		t.Fatalf("concurrent export: %v", concurrent)
+	}
+	if !errors.Is(err, services.ErrExportInProgress) {
+		t.Errorf("second export: got %v, want ErrExportInProgress", err)
+	}
+}
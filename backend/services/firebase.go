@@ -9,6 +9,8 @@ import (
 	"firebase.google.com/go/auth"
 	"firebase.google.com/go/messaging"
 
+	"github.com/qreepex/water-me-app/backend/tracing"
+
 	"google.golang.org/api/option"
 )
 
@@ -50,7 +52,11 @@ func NewFirebaseService() (*FirebaseService, error) {
 }
 
 func (fs *FirebaseService) VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	ctx, span := tracing.Tracer.Start(ctx, "firebase.VerifyIDToken")
+	defer span.End()
+
 	token, err := fs.authClient.VerifyIDToken(ctx, idToken)
+	tracing.RecordError(span, err)
 	if err != nil {
 		return nil, fmt.Errorf("error verifying ID token: %v", err)
 	}
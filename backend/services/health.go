@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// HealthCheck is a named dependency probe used by readiness endpoints
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// DependencyChecks builds readiness checks for the given dependencies.
+// Nil dependencies are skipped, so workers only check what they use.
+func DependencyChecks(db *MongoDB, objects ObjectStore, firebase *FirebaseService) []HealthCheck {
+	checks := make([]HealthCheck, 0, 3)
+	if db != nil {
+		checks = append(checks, HealthCheck{Name: "mongodb", Check: db.Ping})
+	}
+	if objects != nil {
+		checks = append(checks, HealthCheck{Name: "storage", Check: objects.Ready})
+	}
+	if firebase != nil {
+		checks = append(checks, HealthCheck{Name: "firebase", Check: firebase.Ready})
+	}
+	return checks
+}
+
+// Ping verifies the MongoDB primary is reachable
+func (m *MongoDB) Ping(ctx context.Context) error {
+	return m.client.Ping(ctx, nil)
+}
+
+// HeadBucket verifies the configured bucket exists and the credentials can access it
+func (s *S3Service) HeadBucket(ctx context.Context) error {
+	_, err := s.Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &s.Bucket})
+	if err != nil {
+		return fmt.Errorf("head bucket: %w", err)
+	}
+	return nil
+}
+
+// Ready verifies the S3 bucket is reachable
+func (s *S3Service) Ready(ctx context.Context) error {
+	return s.HeadBucket(ctx)
+}
+
+// Ready verifies the Firebase credentials were loaded and the clients are available
+func (fs *FirebaseService) Ready(ctx context.Context) error {
+	if fs.app == nil || fs.authClient == nil || fs.messagingClient == nil {
+		return errors.New("firebase clients not initialized")
+	}
+	return nil
+}
+
+// Heartbeat tracks the last successful tick of a background worker so a
+// liveness probe can detect a worker that is stuck
+type Heartbeat struct {
+	mu          sync.RWMutex
+	startedAt   time.Time
+	lastSuccess time.Time
+	maxAge      time.Duration
+}
+
+// NewHeartbeat creates a heartbeat that is considered stale when no tick
+// succeeded within maxAge (counted from startup until the first success)
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{
+		startedAt: time.Now(),
+		maxAge:    maxAge,
+	}
+}
+
+// Beat records a successful tick
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastSuccess = time.Now()
+}
+
+// Status returns the time of the last successful tick (zero if none yet)
+// and whether the worker is still considered alive
+func (h *Heartbeat) Status() (time.Time, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	reference := h.lastSuccess
+	if reference.IsZero() {
+		reference = h.startedAt
+	}
+	return h.lastSuccess, time.Since(reference) <= h.maxAge
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"fmt"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// inviteCodeAlphabet leaves out characters that are easily confused when a
+// code is read out or typed (0/O, 1/I/L)
+const inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+const inviteCodeLength = 8
+
+// GenerateInviteCode returns a random household or vacation invite code
+func GenerateInviteCode() (string, error) {
+	code, err := gonanoid.Generate(inviteCodeAlphabet, inviteCodeLength)
+	if err != nil {
+		return "", fmt.Errorf("generate invite code: %w", err)
+	}
+	return code, nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+)
+
+// LocalStoragePath is the API path prefix under which LocalStorage serves objects
+const LocalStoragePath = "/storage/"
+
+// Operations a LocalStorage URL can be signed for
+const (
+	StorageOpGet = "get"
+	StorageOpPut = "put"
+)
+
+var (
+	ErrInvalidSignature    = errors.New("invalid or expired signature")
+	ErrInvalidObjectKey    = errors.New("invalid object key")
+	ErrObjectTooLarge      = errors.New("object exceeds upload limit")
+	ErrContentTypeMismatch = errors.New("content does not match the signed content type")
+)
+
+// LocalStorage stores objects on the local disk. Clients upload and download
+// through HMAC-signed URLs served by the API (see routes.StorageHandler), which
+// mirrors how presigned S3 URLs are used.
+type LocalStorage struct {
+	root      string
+	publicURL string
+	secret    []byte
+	URLExpire time.Duration
+}
+
+// NewLocalStorage creates the storage root if needed. publicURL is the base URL
+// clients reach the API on, e.g. "http://localhost:8080".
+func NewLocalStorage(root, publicURL string, secret []byte) (*LocalStorage, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("local storage signing secret is empty")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	return &LocalStorage{
+		root:      root,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		secret:    secret,
+		URLExpire: 1 * time.Hour,
+	}, nil
+}
+
+// PresignPutURL returns a signed URL that accepts one PUT of the given content type
+func (l *LocalStorage) PresignPutURL(
+	_ context.Context,
+	key, contentType string,
+	_ string,
+) (string, map[string]string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", nil, err
+	}
+	// Headers client MUST include
+	headers := map[string]string{"Content-Type": contentType}
+	return l.signedURL(StorageOpPut, key, contentType), headers, nil
+}
+
+// PresignGetURL returns a short-lived signed URL to view an object
+func (l *LocalStorage) PresignGetURL(_ context.Context, key string) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	return l.signedURL(StorageOpGet, key, ""), nil
+}
+
+// HeadObjectInfo returns object size and its content type as detected from the
+// stored bytes
+func (l *LocalStorage) HeadObjectInfo(_ context.Context, key string) (int64, string, error) {
+	file, info, err := l.Open(key)
+	if err != nil {
+		return 0, "", fmt.Errorf("head object: %w", err)
+	}
+	defer file.Close()
+
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		return 0, "", fmt.Errorf("head object: %w", err)
+	}
+	return info.Size(), contentType, nil
+}
+
+// DeleteObject removes an object. Deleting a missing object is not an error.
+func (l *LocalStorage) DeleteObject(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete object: %w", err)
+	}
+	return nil
+}
+
+// DeleteObjects removes multiple objects
+func (l *LocalStorage) DeleteObjects(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := l.DeleteObject(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeletePrefix removes every object below prefix, which must be a
+// user-scoped directory such as UserPrefix(userID)
+func (l *LocalStorage) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	dir, err := l.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	err = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			deleted++
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("list objects: %w", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("delete objects: %w", err)
+	}
+	return deleted, nil
+}
+
+// Ready verifies the storage root exists and is a directory
+func (l *LocalStorage) Ready(context.Context) error {
+	info, err := os.Stat(l.root)
+	if err != nil {
+		return fmt.Errorf("stat storage dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("storage root %s is not a directory", l.root)
+	}
+	return nil
+}
+
+// Verify checks a URL signature produced by PresignPutURL or PresignGetURL
+func (l *LocalStorage) Verify(op, key, contentType, expires, signature string) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidSignature
+	}
+	expected := l.sign(op, key, contentType, exp)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Open opens an object for reading
+func (l *LocalStorage) Open(key string) (*os.File, os.FileInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, info, nil
+}
+
+// Write stores an object. The content must not exceed constants.MaxUploadBytes
+// and its detected type must equal contentType; otherwise nothing is stored.
+func (l *LocalStorage) Write(key, contentType string, body io.Reader) (int64, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	// Read one byte past the limit to detect oversized bodies
+	data, err := io.ReadAll(io.LimitReader(body, constants.MaxUploadBytes+1))
+	if err != nil {
+		return 0, fmt.Errorf("read body: %w", err)
+	}
+	if int64(len(data)) > constants.MaxUploadBytes {
+		return 0, ErrObjectTooLarge
+	}
+	if detected, _ := sniffContentType(bytes.NewReader(data)); detected != contentType {
+		return 0, ErrContentTypeMismatch
+	}
+
+	return writeFile(p, bytes.NewReader(data))
+}
+
+// GetObject opens an object for reading
+func (l *LocalStorage) GetObject(_ context.Context, key string) (io.ReadCloser, error) {
+	file, _, err := l.Open(key)
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+	return file, nil
+}
+
+// PutObject stores a server-generated object without the upload checks of Write
+func (l *LocalStorage) PutObject(_ context.Context, key, _ string, body io.Reader, _ int64) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	_, err = writeFile(p, body)
+	return err
+}
+
+// writeFile writes to a temp file first so readers never see a partial object
+func writeFile(p string, body io.Reader) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return 0, fmt.Errorf("create object dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("write object: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return 0, fmt.Errorf("store object: %w", err)
+	}
+	return written, nil
+}
+
+// path maps a key to a file below the storage root, rejecting keys that are
+// not user-scoped or that try to escape the root
+func (l *LocalStorage) path(key string) (string, error) {
+	if !strings.HasPrefix(key, "users/") || path.Clean(key) != key || strings.Contains(key, "\\") {
+		return "", ErrInvalidObjectKey
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+func (l *LocalStorage) signedURL(op, key, contentType string) string {
+	exp := time.Now().Add(l.URLExpire).Unix()
+
+	query := url.Values{}
+	query.Set("op", op)
+	query.Set("exp", strconv.FormatInt(exp, 10))
+	if contentType != "" {
+		query.Set("ct", contentType)
+	}
+	query.Set("sig", l.sign(op, key, contentType, exp))
+
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return l.publicURL + LocalStoragePath + strings.Join(segments, "/") + "?" + query.Encode()
+}
+
+func (l *LocalStorage) sign(op, key, contentType string, exp int64) string {
+	mac := hmac.New(sha256.New, l.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d", op, key, contentType, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sniffContentType detects the MIME type from the first 512 bytes
+func sniffContentType(r io.Reader) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
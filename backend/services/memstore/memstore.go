@@ -0,0 +1,1657 @@
+// Package memstore provides in-memory implementations of the store interfaces
+// in services, for tests and local runs without MongoDB or S3.
+package memstore
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrDuplicateKey mirrors a unique index violation in MongoDB
+var ErrDuplicateKey = errors.New("memstore: duplicate key")
+
+// Store keeps plants, notification configs, uploads, users, API tokens,
+// households, care events, vacations, plant shares, exports, species, rooms,
+// safety profiles, pest outbreaks, treatment plans and account erasures in
+// memory. It is safe for concurrent use.
+type Store struct {
+	mu            sync.Mutex
+	plants        map[string]types.Plant              // by ID
+	notifications map[string]types.NotificationConfig // by user ID
+	uploads       map[string]types.Upload             // by object key
+	users         map[string]types.User               // by ID
+	apiTokens     map[string]types.APIToken           // by ID
+	households    map[string]types.Household          // by ID
+	invites       map[string]types.HouseholdInvite    // by ID
+	careEvents    []types.CareEvent                   // in insertion order
+	vacations     map[string]types.Vacation           // by ID
+	shares        map[string]types.PlantShare         // by ID
+	exports       map[string]types.DataExport         // by ID
+	species       map[string]types.Species            // by ID
+	rooms         map[string]types.Room               // by ID
+	outbreaks     map[string]types.PestOutbreak       // by ID
+	treatments    map[string]types.TreatmentPlan      // by ID
+	profiles      map[string]types.SafetyProfile      // by user ID
+	erasures      map[string]types.AccountErasure     // by user hash
+}
+
+var _ services.Store = (*Store)(nil)
+
+// New returns an empty store
+func New() *Store {
+	return &Store{
+		plants:        make(map[string]types.Plant),
+		notifications: make(map[string]types.NotificationConfig),
+		uploads:       make(map[string]types.Upload),
+		users:         make(map[string]types.User),
+		apiTokens:     make(map[string]types.APIToken),
+		households:    make(map[string]types.Household),
+		invites:       make(map[string]types.HouseholdInvite),
+		vacations:     make(map[string]types.Vacation),
+		shares:        make(map[string]types.PlantShare),
+		exports:       make(map[string]types.DataExport),
+		species:       make(map[string]types.Species),
+		rooms:         make(map[string]types.Room),
+		outbreaks:     make(map[string]types.PestOutbreak),
+		treatments:    make(map[string]types.TreatmentPlan),
+		profiles:      make(map[string]types.SafetyProfile),
+		erasures:      make(map[string]types.AccountErasure),
+	}
+}
+
+// --- Plants ---
+
+func (s *Store) GetPlants(_ context.Context, userID string) ([]types.Plant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var plants []types.Plant
+	for _, plant := range s.plants {
+		if plant.UserID == userID {
+			plants = append(plants, plant)
+		}
+	}
+	// Object IDs grow over time, so this matches MongoDB's insertion order
+	sort.Slice(plants, func(i, j int) bool { return plants[i].ID < plants[j].ID })
+	return plants, nil
+}
+
+func (s *Store) GetPlant(_ context.Context, userID string, id string) (*types.Plant, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plant, ok := s.plants[id]
+	if !ok || plant.UserID != userID {
+		return nil, nil
+	}
+	return &plant, nil
+}
+
+func (s *Store) GetPlantBySlug(
+	_ context.Context,
+	userID string,
+	slug string,
+) (*types.Plant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, plant := range s.plants {
+		if plant.UserID == userID && plant.Slug == slug {
+			return &plant, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) CreatePlant(_ context.Context, plant types.Plant) (*types.Plant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.plants {
+		if existing.UserID == plant.UserID && existing.Slug == plant.Slug {
+			return nil, ErrDuplicateKey
+		}
+	}
+
+	plant.ID = primitive.NewObjectID().Hex()
+	s.plants[plant.ID] = plant
+	return &plant, nil
+}
+
+func (s *Store) UpdatePlant(
+	_ context.Context,
+	id string,
+	userID string,
+	update types.UpdatePlantRequest,
+) (*types.Plant, bool, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plant, ok := s.plants[id]
+	if !ok || plant.UserID != userID {
+		return nil, false, nil
+	}
+	services.ApplyPlantUpdate(&plant, update, time.Now())
+	s.plants[id] = plant
+	return &plant, true, nil
+}
+
+func (s *Store) DeletePlant(_ context.Context, id string, userID string) (bool, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plant, ok := s.plants[id]
+	if !ok || plant.UserID != userID {
+		return false, nil
+	}
+	delete(s.plants, id)
+	return true, nil
+}
+
+func (s *Store) WaterPlants(_ context.Context, userID string, plantIDs []string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var modified int64
+	for _, id := range plantIDs {
+		plant, ok := s.plants[id]
+		if !ok || plant.UserID != userID {
+			continue
+		}
+		watering := types.WateringConfig{}
+		if plant.Watering != nil {
+			watering = *plant.Watering
+		}
+		watering.LastWatered = &now
+		watering.SnoozedUntil = nil
+		plant.Watering = &watering
+		plant.UpdatedAt = now
+		s.plants[id] = plant
+		modified++
+	}
+	return modified, nil
+}
+
+func (s *Store) GetPlantByID(_ context.Context, id string) (*types.Plant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plant, ok := s.plants[id]
+	if !ok {
+		return nil, nil
+	}
+	return &plant, nil
+}
+
+func (s *Store) GetHouseholdPlants(_ context.Context, householdIDs []string) ([]types.Plant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plants := make([]types.Plant, 0)
+	for _, plant := range s.plants {
+		if plant.HouseholdID != "" && slices.Contains(householdIDs, plant.HouseholdID) {
+			plants = append(plants, plant)
+		}
+	}
+	sort.Slice(plants, func(i, j int) bool { return plants[i].ID < plants[j].ID })
+	return plants, nil
+}
+
+func (s *Store) SnoozeWatering(_ context.Context, id, userID string, until time.Time) (bool, error) {
+	return s.updateWatering(id, userID, func(watering *types.WateringConfig) {
+		watering.SnoozedUntil = &until
+	}), nil
+}
+
+func (s *Store) SetWateringInterval(_ context.Context, id, userID string, days int) (bool, error) {
+	return s.updateWatering(id, userID, func(watering *types.WateringConfig) {
+		watering.IntervalDays = days
+	}), nil
+}
+
+// updateWatering changes the watering config of a plant with a watering interval
+func (s *Store) updateWatering(id, userID string, update func(*types.WateringConfig)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plant, ok := s.plants[id]
+	if !ok || plant.UserID != userID || plant.Watering == nil || plant.Watering.IntervalDays <= 0 {
+		return false
+	}
+	watering := *plant.Watering
+	update(&watering)
+	plant.Watering = &watering
+	plant.UpdatedAt = time.Now()
+	s.plants[id] = plant
+	return true
+}
+
+func (s *Store) SetPlantHousehold(_ context.Context, id, userID, householdID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plant, ok := s.plants[id]
+	if !ok || plant.UserID != userID {
+		return false, nil
+	}
+	plant.HouseholdID = householdID
+	plant.UpdatedAt = time.Now()
+	s.plants[id] = plant
+	return true, nil
+}
+
+func (s *Store) ClearPlantHousehold(_ context.Context, householdID, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var modified int64
+	for id, plant := range s.plants {
+		if plant.HouseholdID != householdID || (userID != "" && plant.UserID != userID) {
+			continue
+		}
+		plant.HouseholdID = ""
+		plant.UpdatedAt = time.Now()
+		s.plants[id] = plant
+		modified++
+	}
+	return modified, nil
+}
+
+func (s *Store) MovePlantsToRoom(
+	_ context.Context,
+	userID string,
+	plantIDs []string,
+	room types.Room,
+) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched int64
+	for _, id := range plantIDs {
+		plant, ok := s.plants[id]
+		if !ok || plant.UserID != userID {
+			continue
+		}
+		location := types.Location{}
+		if plant.Location != nil {
+			location = *plant.Location
+		}
+		services.PlaceInRoom(&location, room)
+		plant.Location = &location
+		plant.UpdatedAt = time.Now()
+		s.plants[id] = plant
+		matched++
+	}
+	return matched, nil
+}
+
+func (s *Store) SyncRoomPlants(_ context.Context, room types.Room) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var modified int64
+	for id, plant := range s.plants {
+		if plant.UserID != room.UserID || plant.Location == nil || plant.Location.RoomID != room.ID {
+			continue
+		}
+		location := *plant.Location
+		services.PlaceInRoom(&location, room)
+		plant.Location = &location
+		plant.UpdatedAt = time.Now()
+		s.plants[id] = plant
+		modified++
+	}
+	return modified, nil
+}
+
+func (s *Store) ClearPlantRoom(_ context.Context, userID, roomID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var modified int64
+	for id, plant := range s.plants {
+		if plant.UserID != userID || plant.Location == nil || plant.Location.RoomID != roomID {
+			continue
+		}
+		location := *plant.Location
+		location.RoomID = ""
+		location.Room = ""
+		plant.Location = &location
+		plant.UpdatedAt = time.Now()
+		s.plants[id] = plant
+		modified++
+	}
+	return modified, nil
+}
+
+func (s *Store) CountActiveUsers(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make(map[string]bool)
+	for _, plant := range s.plants {
+		users[plant.UserID] = true
+	}
+	return int64(len(users)), nil
+}
+
+func (s *Store) CountPlants(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.plants)), nil
+}
+
+// --- Notification configs ---
+
+func (s *Store) GetNotificationConfig(
+	_ context.Context,
+	userID string,
+) (*types.NotificationConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, ok := s.notifications[userID]
+	if !ok {
+		return nil, types.ErrNoDocuments
+	}
+	config.DeviceTokens = append([]types.DeviceToken(nil), config.DeviceTokens...)
+	return &config, nil
+}
+
+func (s *Store) CreateNotificationConfig(
+	_ context.Context,
+	config types.NotificationConfig,
+) (*types.NotificationConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.notifications[config.UserID]; ok {
+		return nil, ErrDuplicateKey
+	}
+	s.notifications[config.UserID] = config
+	return &config, nil
+}
+
+func (s *Store) UpdateNotificationConfig(
+	_ context.Context,
+	config types.NotificationConfig,
+) (*types.NotificationConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.notifications[config.UserID]; !ok {
+		return nil, types.ErrNoDocuments
+	}
+	s.notifications[config.UserID] = config
+	return &config, nil
+}
+
+func (s *Store) DeleteNotificationConfig(_ context.Context, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.notifications[userID]; !ok {
+		return false, nil
+	}
+	delete(s.notifications, userID)
+	return true, nil
+}
+
+// --- Uploads ---
+
+func (s *Store) GetUserUploadCount(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, upload := range s.uploads {
+		if upload.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Store) RegisterUpload(_ context.Context, userID string, key string, size int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.uploads[key] = types.Upload{
+		ID:        primitive.NewObjectID().Hex(),
+		UserID:    userID,
+		Key:       key,
+		SizeBytes: size,
+		CreatedAt: time.Now(),
+	}
+	return nil
+}
+
+func (s *Store) GetUploadsCreatedBefore(
+	_ context.Context,
+	cutoff time.Time,
+) ([]types.Upload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var uploads []types.Upload
+	for _, upload := range s.uploads {
+		if upload.CreatedAt.Before(cutoff) {
+			uploads = append(uploads, upload)
+		}
+	}
+	return uploads, nil
+}
+
+func (s *Store) DeleteUploadRecords(_ context.Context, keys []string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := s.uploads[key]; ok {
+			delete(s.uploads, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) IsUploadReferenced(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, plant := range s.plants {
+		for _, photoID := range plant.PhotoIDs {
+			if photoID == key {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) GetStorageUsageByUser(context.Context) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage := make(map[string]int64)
+	for _, upload := range s.uploads {
+		usage[upload.UserID] += upload.SizeBytes
+	}
+	return usage, nil
+}
+
+// --- Users ---
+
+func (s *Store) CreateUser(_ context.Context, user types.User) (*types.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.users {
+		if existing.Email == user.Email {
+			return nil, services.ErrEmailTaken
+		}
+	}
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+func (s *Store) GetUserByEmail(_ context.Context, email string) (*types.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) GetUserByID(_ context.Context, id string) (*types.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (s *Store) UpdateUserPassword(
+	_ context.Context,
+	userID, hash string,
+	changedAt time.Time,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return types.ErrNoDocuments
+	}
+	user.PasswordHash = hash
+	user.PasswordChangedAt = changedAt
+	s.users[userID] = user
+	return nil
+}
+
+// --- API tokens ---
+
+func (s *Store) CreateAPIToken(_ context.Context, token types.APIToken) (*types.APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.apiTokens {
+		if existing.ID == token.ID || existing.Hash == token.Hash {
+			return nil, ErrDuplicateKey
+		}
+	}
+	s.apiTokens[token.ID] = token
+	return &token, nil
+}
+
+func (s *Store) GetAPITokens(_ context.Context, userID string) ([]types.APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := make([]types.APIToken, 0)
+	for _, token := range s.apiTokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.Before(tokens[j].CreatedAt) })
+	return tokens, nil
+}
+
+func (s *Store) GetAPITokenByHash(_ context.Context, hash string) (*types.APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.apiTokens {
+		if token.Hash == hash {
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) DeleteAPIToken(_ context.Context, userID string, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.apiTokens[id]
+	if !ok || token.UserID != userID {
+		return false, nil
+	}
+	delete(s.apiTokens, id)
+	return true, nil
+}
+
+func (s *Store) TouchAPIToken(_ context.Context, id string, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.apiTokens[id]
+	if !ok {
+		return nil
+	}
+	token.LastUsedAt = &usedAt
+	s.apiTokens[id] = token
+	return nil
+}
+
+// --- Households ---
+
+// Members are cloned on the way in and out so callers never share the stored slice.
+
+func (s *Store) CreateHousehold(_ context.Context, household types.Household) (*types.Household, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[household.ID]; ok {
+		return nil, ErrDuplicateKey
+	}
+	household.Members = slices.Clone(household.Members)
+	s.households[household.ID] = household
+	return cloneHousehold(household), nil
+}
+
+func (s *Store) GetHousehold(_ context.Context, id string) (*types.Household, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneHousehold(household), nil
+}
+
+func (s *Store) GetHouseholdsForUser(_ context.Context, userID string) ([]types.Household, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	households := make([]types.Household, 0)
+	for _, household := range s.households {
+		if _, ok := household.Member(userID); ok {
+			households = append(households, *cloneHousehold(household))
+		}
+	}
+	sort.Slice(households, func(i, j int) bool {
+		return households[i].CreatedAt.Before(households[j].CreatedAt)
+	})
+	return households, nil
+}
+
+func (s *Store) DeleteHousehold(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[id]; !ok {
+		return false, nil
+	}
+	delete(s.households, id)
+	for inviteID, invite := range s.invites {
+		if invite.HouseholdID == id {
+			delete(s.invites, inviteID)
+		}
+	}
+	return true, nil
+}
+
+func (s *Store) AddHouseholdMember(
+	_ context.Context,
+	householdID string,
+	member types.HouseholdMember,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[householdID]
+	if !ok {
+		return false, nil
+	}
+	if _, exists := household.Member(member.UserID); exists {
+		return false, nil
+	}
+	household.Members = append(slices.Clone(household.Members), member)
+	household.UpdatedAt = time.Now()
+	s.households[householdID] = household
+	return true, nil
+}
+
+func (s *Store) UpdateHouseholdMemberRole(
+	_ context.Context,
+	householdID, userID string,
+	role types.HouseholdRole,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[householdID]
+	if !ok {
+		return false, nil
+	}
+	members := slices.Clone(household.Members)
+	for i := range members {
+		if members[i].UserID == userID {
+			members[i].Role = role
+			household.Members = members
+			household.UpdatedAt = time.Now()
+			s.households[householdID] = household
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) RemoveHouseholdMember(_ context.Context, householdID, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[householdID]
+	if !ok {
+		return false, nil
+	}
+	members := slices.DeleteFunc(slices.Clone(household.Members), func(m types.HouseholdMember) bool {
+		return m.UserID == userID
+	})
+	if len(members) == len(household.Members) {
+		return false, nil
+	}
+	household.Members = members
+	household.UpdatedAt = time.Now()
+	s.households[householdID] = household
+	return true, nil
+}
+
+func (s *Store) SetHouseholdProfile(
+	_ context.Context,
+	householdID string,
+	profile types.SafetyProfile,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[householdID]
+	if !ok {
+		return false, nil
+	}
+	household.Profile = &profile
+	household.UpdatedAt = time.Now()
+	s.households[householdID] = household
+	return true, nil
+}
+
+func (s *Store) CreateHouseholdInvite(
+	_ context.Context,
+	invite types.HouseholdInvite,
+) (*types.HouseholdInvite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.invites {
+		if existing.ID == invite.ID || existing.Code == invite.Code {
+			return nil, ErrDuplicateKey
+		}
+	}
+	s.invites[invite.ID] = invite
+	return &invite, nil
+}
+
+func (s *Store) GetHouseholdInvites(
+	_ context.Context,
+	householdID string,
+) ([]types.HouseholdInvite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invites := make([]types.HouseholdInvite, 0)
+	for _, invite := range s.invites {
+		if invite.HouseholdID == householdID {
+			invites = append(invites, invite)
+		}
+	}
+	sort.Slice(invites, func(i, j int) bool { return invites[i].CreatedAt.Before(invites[j].CreatedAt) })
+	return invites, nil
+}
+
+func (s *Store) GetHouseholdInviteByCode(
+	_ context.Context,
+	code string,
+) (*types.HouseholdInvite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, invite := range s.invites {
+		if invite.Code == code {
+			return &invite, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) DeleteHouseholdInvite(_ context.Context, householdID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invite, ok := s.invites[id]
+	if !ok || invite.HouseholdID != householdID {
+		return false, nil
+	}
+	delete(s.invites, id)
+	return true, nil
+}
+
+func cloneHousehold(household types.Household) *types.Household {
+	household.Members = slices.Clone(household.Members)
+	return &household
+}
+
+// --- Care events ---
+
+func (s *Store) LogCareEvents(_ context.Context, events []types.CareEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.careEvents = append(s.careEvents, events...)
+	return nil
+}
+
+func (s *Store) GetCareEvents(
+	_ context.Context,
+	ownerID string,
+	from, to time.Time,
+) ([]types.CareEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]types.CareEvent, 0)
+	for _, event := range s.careEvents {
+		if event.OwnerID == ownerID && !event.At.Before(from) && event.At.Before(to) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events, nil
+}
+
+// --- Vacations ---
+
+func (s *Store) CreateVacation(_ context.Context, vacation types.Vacation) (*types.Vacation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.vacations {
+		if existing.ID == vacation.ID || existing.InviteCode == vacation.InviteCode {
+			return nil, ErrDuplicateKey
+		}
+	}
+	s.vacations[vacation.ID] = vacation
+	return &vacation, nil
+}
+
+func (s *Store) GetVacations(_ context.Context, userID string) ([]types.Vacation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vacations := make([]types.Vacation, 0)
+	for _, vacation := range s.vacations {
+		if vacation.UserID == userID {
+			vacations = append(vacations, vacation)
+		}
+	}
+	sort.Slice(vacations, func(i, j int) bool { return vacations[i].StartsAt.After(vacations[j].StartsAt) })
+	return vacations, nil
+}
+
+func (s *Store) GetVacation(_ context.Context, userID, id string) (*types.Vacation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vacation, ok := s.vacations[id]
+	if !ok || vacation.UserID != userID {
+		return nil, nil
+	}
+	return &vacation, nil
+}
+
+func (s *Store) GetVacationByCode(_ context.Context, code string) (*types.Vacation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, vacation := range s.vacations {
+		if vacation.InviteCode == code {
+			return &vacation, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) SetVacationCaretaker(
+	_ context.Context,
+	id, caretakerID, caretakerName string,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vacation, ok := s.vacations[id]
+	if !ok || vacation.CaretakerID != "" {
+		return false, nil
+	}
+	vacation.CaretakerID = caretakerID
+	if caretakerName != "" {
+		vacation.CaretakerName = caretakerName
+	}
+	s.vacations[id] = vacation
+	return true, nil
+}
+
+func (s *Store) EndVacation(_ context.Context, userID, id string, endsAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vacation, ok := s.vacations[id]
+	if !ok || vacation.UserID != userID {
+		return false, nil
+	}
+	vacation.EndsAt = endsAt
+	s.vacations[id] = vacation
+	return true, nil
+}
+
+func (s *Store) DeleteVacation(_ context.Context, userID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vacation, ok := s.vacations[id]
+	if !ok || vacation.UserID != userID {
+		return false, nil
+	}
+	delete(s.vacations, id)
+	return true, nil
+}
+
+func (s *Store) GetCaretakerVacations(
+	_ context.Context,
+	caretakerID string,
+	now time.Time,
+) ([]types.Vacation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vacations := make([]types.Vacation, 0)
+	for _, vacation := range s.vacations {
+		if vacation.CaretakerID == caretakerID && vacation.ActiveAt(now) {
+			vacations = append(vacations, vacation)
+		}
+	}
+	return vacations, nil
+}
+
+// --- Plant shares ---
+
+func (s *Store) CreatePlantShare(_ context.Context, share types.PlantShare) (*types.PlantShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.shares {
+		if existing.ID == share.ID || existing.Token == share.Token {
+			return nil, ErrDuplicateKey
+		}
+	}
+	s.shares[share.ID] = share
+	return &share, nil
+}
+
+func (s *Store) GetPlantShares(_ context.Context, plantID string) ([]types.PlantShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shares := make([]types.PlantShare, 0)
+	for _, share := range s.shares {
+		if share.PlantID == plantID {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool { return shares[i].CreatedAt.Before(shares[j].CreatedAt) })
+	return shares, nil
+}
+
+func (s *Store) GetPlantShareByToken(_ context.Context, token string) (*types.PlantShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, share := range s.shares {
+		if share.Token == token {
+			return &share, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) DeletePlantShare(_ context.Context, plantID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	share, ok := s.shares[id]
+	if !ok || share.PlantID != plantID {
+		return false, nil
+	}
+	delete(s.shares, id)
+	return true, nil
+}
+
+// --- Exports ---
+
+func (s *Store) CreateExport(_ context.Context, export types.DataExport) (*types.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.exports[export.ID]; ok {
+		return nil, ErrDuplicateKey
+	}
+	for _, other := range s.exports {
+		if other.UserID == export.UserID && other.Active() && export.Active() {
+			return nil, services.ErrExportInProgress
+		}
+	}
+	s.exports[export.ID] = export
+	return &export, nil
+}
+
+func (s *Store) GetExports(_ context.Context, userID string) ([]types.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exports := make([]types.DataExport, 0)
+	for _, export := range s.exports {
+		if export.UserID == userID {
+			exports = append(exports, export)
+		}
+	}
+	sort.Slice(exports, func(i, j int) bool { return exports[i].CreatedAt.After(exports[j].CreatedAt) })
+	return exports, nil
+}
+
+func (s *Store) GetExport(_ context.Context, userID, id string) (*types.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	export, ok := s.exports[id]
+	if !ok || export.UserID != userID {
+		return nil, nil
+	}
+	return &export, nil
+}
+
+func (s *Store) UpdateExport(_ context.Context, export types.DataExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.exports[export.ID]; ok {
+		s.exports[export.ID] = export
+	}
+	return nil
+}
+
+func (s *Store) GetExpiredExports(_ context.Context, now time.Time) ([]types.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exports := make([]types.DataExport, 0)
+	for _, export := range s.exports {
+		if export.ExpiresAt != nil && !export.ExpiresAt.After(now) {
+			exports = append(exports, export)
+		}
+	}
+	return exports, nil
+}
+
+func (s *Store) DeleteExport(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.exports, id)
+	return nil
+}
+
+// --- Species ---
+
+func (s *Store) SearchSpecies(_ context.Context, query string, limit int) ([]types.Species, error) {
+	pattern, err := regexp.Compile(services.SpeciesSearchPattern(query))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := make([]types.Species, 0)
+	for _, entry := range s.species {
+		if query == "" || speciesMatches(entry, pattern) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ScientificName < matches[j].ScientificName
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func speciesMatches(species types.Species, pattern *regexp.Regexp) bool {
+	if pattern.MatchString(species.ScientificName) {
+		return true
+	}
+	for _, name := range species.CommonNames {
+		if pattern.MatchString(name.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) GetSpecies(_ context.Context, id string) (*types.Species, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	species, ok := s.species[id]
+	if !ok {
+		return nil, nil
+	}
+	return &species, nil
+}
+
+func (s *Store) UpsertSpecies(_ context.Context, species types.Species) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.species[species.ID] = species
+	return nil
+}
+
+// --- Rooms ---
+
+func (s *Store) CreateRoom(_ context.Context, room types.Room) (*types.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roomNameTaken(room) {
+		return nil, services.ErrRoomNameTaken
+	}
+	s.rooms[room.ID] = room
+	return &room, nil
+}
+
+func (s *Store) GetRooms(_ context.Context, userID string) ([]types.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rooms := make([]types.Room, 0)
+	for _, room := range s.rooms {
+		if room.UserID == userID {
+			rooms = append(rooms, room)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].NameKey < rooms[j].NameKey })
+	return rooms, nil
+}
+
+func (s *Store) GetRoom(_ context.Context, userID, id string) (*types.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[id]
+	if !ok || room.UserID != userID {
+		return nil, nil
+	}
+	return &room, nil
+}
+
+func (s *Store) GetRoomByName(_ context.Context, userID, name string) (*types.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := types.RoomNameKey(name)
+	for _, room := range s.rooms {
+		if room.UserID == userID && room.NameKey == key {
+			return &room, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) UpdateRoom(_ context.Context, room types.Room) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.rooms[room.ID]
+	if !ok || existing.UserID != room.UserID {
+		return false, nil
+	}
+	if s.roomNameTaken(room) {
+		return false, services.ErrRoomNameTaken
+	}
+	s.rooms[room.ID] = room
+	return true, nil
+}
+
+func (s *Store) DeleteRoom(_ context.Context, userID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[id]
+	if !ok || room.UserID != userID {
+		return false, nil
+	}
+	delete(s.rooms, id)
+	return true, nil
+}
+
+// roomNameTaken mirrors the unique (userId, nameKey) index
+func (s *Store) roomNameTaken(room types.Room) bool {
+	for _, other := range s.rooms {
+		if other.ID != room.ID && other.UserID == room.UserID && other.NameKey == room.NameKey {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Pest outbreaks ---
+
+func (s *Store) CreateOutbreak(_ context.Context, outbreak types.PestOutbreak) (*types.PestOutbreak, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.outbreaks[outbreak.ID]; exists {
+		return nil, ErrDuplicateKey
+	}
+	s.outbreaks[outbreak.ID] = outbreak
+	return &outbreak, nil
+}
+
+func (s *Store) GetOutbreaks(_ context.Context, userID string) ([]types.PestOutbreak, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outbreaks := make([]types.PestOutbreak, 0)
+	for _, outbreak := range s.outbreaks {
+		if outbreak.UserID == userID {
+			outbreaks = append(outbreaks, outbreak)
+		}
+	}
+	sort.Slice(outbreaks, func(i, j int) bool { return outbreaks[i].DetectedAt.After(outbreaks[j].DetectedAt) })
+	return outbreaks, nil
+}
+
+func (s *Store) AddOutbreakPlant(
+	_ context.Context,
+	id string,
+	plant types.OutbreakPlant,
+	room string,
+	now time.Time,
+) (*types.PestOutbreak, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outbreak, ok := s.outbreaks[id]
+	if !ok || outbreak.Status != types.OutbreakOpen {
+		return nil, nil
+	}
+	if !slices.Contains(outbreak.Infected, plant) {
+		outbreak.Infected = append(slices.Clone(outbreak.Infected), plant)
+	}
+	outbreak.Room = room
+	outbreak.UpdatedAt = now
+	s.outbreaks[id] = outbreak
+	return &outbreak, nil
+}
+
+func (s *Store) SetOutbreakAtRisk(
+	_ context.Context,
+	id string,
+	infected int,
+	atRisk []types.OutbreakPlant,
+	quarantine string,
+	now time.Time,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outbreak, ok := s.outbreaks[id]
+	if !ok || outbreak.Status != types.OutbreakOpen || len(outbreak.Infected) != infected {
+		return false, nil
+	}
+	outbreak.AtRisk = slices.Clone(atRisk)
+	outbreak.Quarantine = quarantine
+	outbreak.UpdatedAt = now
+	s.outbreaks[id] = outbreak
+	return true, nil
+}
+
+func (s *Store) ContainOutbreak(_ context.Context, id string, infected int, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outbreak, ok := s.outbreaks[id]
+	if !ok || outbreak.Status != types.OutbreakOpen || len(outbreak.Infected) != infected {
+		return false, nil
+	}
+	outbreak.Status = types.OutbreakContained
+	outbreak.ContainedAt = &now
+	outbreak.NextInspectionAt = nil
+	outbreak.UpdatedAt = now
+	s.outbreaks[id] = outbreak
+	return true, nil
+}
+
+func (s *Store) GetOutbreaksNeedingInspection(
+	_ context.Context,
+	now time.Time,
+	limit int,
+) ([]types.PestOutbreak, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := make([]types.PestOutbreak, 0)
+	for _, outbreak := range s.outbreaks {
+		if outbreak.Status == types.OutbreakOpen && outbreak.NextInspectionAt != nil &&
+			!outbreak.NextInspectionAt.After(now) {
+			due = append(due, outbreak)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextInspectionAt.Before(*due[j].NextInspectionAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (s *Store) MarkOutbreakInspected(
+	_ context.Context,
+	id string,
+	sent int,
+	next *time.Time,
+	now time.Time,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outbreak, ok := s.outbreaks[id]
+	if !ok || outbreak.Status != types.OutbreakOpen {
+		return false, nil
+	}
+	outbreak.InspectionsSent = sent
+	outbreak.NextInspectionAt = next
+	outbreak.UpdatedAt = now
+	s.outbreaks[id] = outbreak
+	return true, nil
+}
+
+// --- Treatment plans ---
+
+func (s *Store) CreateTreatmentPlan(_ context.Context, plan types.TreatmentPlan) (*types.TreatmentPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.treatments[plan.ID]; exists {
+		return nil, ErrDuplicateKey
+	}
+	s.treatments[plan.ID] = *cloneTreatmentPlan(plan)
+	return &plan, nil
+}
+
+func (s *Store) GetTreatmentPlan(_ context.Context, id string) (*types.TreatmentPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, ok := s.treatments[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneTreatmentPlan(plan), nil
+}
+
+func (s *Store) GetTreatmentPlans(_ context.Context, plantID string) ([]types.TreatmentPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plans := make([]types.TreatmentPlan, 0)
+	for _, plan := range s.treatments {
+		if plan.PlantID == plantID {
+			plans = append(plans, *cloneTreatmentPlan(plan))
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.After(plans[j].CreatedAt) })
+	return plans, nil
+}
+
+func (s *Store) LogTreatmentApplication(_ context.Context, plan types.TreatmentPlan) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.treatments[plan.ID]
+	if !ok || len(plan.Applications) == 0 || stored.Status != types.TreatmentActive ||
+		len(stored.Applications) != len(plan.Applications)-1 {
+		return false, nil
+	}
+	stored.Applications = append(slices.Clone(stored.Applications), plan.Applications[len(plan.Applications)-1])
+	stored.Status = plan.Status
+	stored.NextDueAt = plan.NextDueAt
+	stored.NextReminderAt = plan.NextReminderAt
+	stored.EndedAt = plan.EndedAt
+	stored.UpdatedAt = plan.UpdatedAt
+	s.treatments[plan.ID] = stored
+	return true, nil
+}
+
+func (s *Store) RescheduleTreatmentReminder(
+	_ context.Context,
+	id string,
+	due, next time.Time,
+	now time.Time,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, ok := s.treatments[id]
+	if !ok || plan.Status != types.TreatmentActive || plan.NextReminderAt == nil || !plan.NextReminderAt.Equal(due) {
+		return false, nil
+	}
+	plan.NextReminderAt = &next
+	plan.UpdatedAt = now
+	s.treatments[id] = plan
+	return true, nil
+}
+
+func (s *Store) StopTreatmentPlan(_ context.Context, id string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, ok := s.treatments[id]
+	if !ok || plan.Status != types.TreatmentActive {
+		return false, nil
+	}
+	services.StopTreatment(&plan, now)
+	s.treatments[id] = plan
+	return true, nil
+}
+
+func (s *Store) GetTreatmentPlansNeedingReminder(
+	_ context.Context,
+	now time.Time,
+	limit int,
+) ([]types.TreatmentPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := make([]types.TreatmentPlan, 0)
+	for _, plan := range s.treatments {
+		if plan.Status == types.TreatmentActive && plan.NextReminderAt != nil && !plan.NextReminderAt.After(now) {
+			due = append(due, *cloneTreatmentPlan(plan))
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextReminderAt.Before(*due[j].NextReminderAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func cloneTreatmentPlan(plan types.TreatmentPlan) *types.TreatmentPlan {
+	plan.Applications = slices.Clone(plan.Applications)
+	return &plan
+}
+
+// --- Safety profiles ---
+
+func (s *Store) GetSafetyProfile(_ context.Context, userID string) (*types.SafetyProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile, ok := s.profiles[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &profile, nil
+}
+
+func (s *Store) PutSafetyProfile(_ context.Context, profile types.SafetyProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.profiles[profile.UserID] = profile
+	return nil
+}
+
+// --- Account erasure ---
+
+func (s *Store) DeleteUserPlants(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.plants, func(plant types.Plant) bool { return plant.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserUploads(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.uploads, func(upload types.Upload) bool { return upload.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserAPITokens(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.apiTokens, func(token types.APIToken) bool { return token.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserVacations(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, vacation := range s.vacations {
+		if vacation.CaretakerID == userID {
+			vacation.CaretakerID = ""
+			vacation.CaretakerName = ""
+			s.vacations[id] = vacation
+		}
+	}
+	return deleteWhere(s.vacations, func(vacation types.Vacation) bool { return vacation.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserCareEvents(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.careEvents[:0]
+	var deleted int64
+	for _, event := range s.careEvents {
+		if event.OwnerID == userID {
+			deleted++
+			continue
+		}
+		if event.UserID == userID {
+			event.UserID = ""
+		}
+		kept = append(kept, event)
+	}
+	s.careEvents = kept
+	return deleted, nil
+}
+
+func (s *Store) DeleteUserPlantShares(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.shares, func(share types.PlantShare) bool { return share.OwnerID == userID }), nil
+}
+
+func (s *Store) DeleteUserExports(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.exports, func(export types.DataExport) bool { return export.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserSafetyProfile(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.profiles, func(profile types.SafetyProfile) bool { return profile.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserRooms(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.rooms, func(room types.Room) bool { return room.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserOutbreaks(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.outbreaks, func(outbreak types.PestOutbreak) bool { return outbreak.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUserTreatmentPlans(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteWhere(s.treatments, func(plan types.TreatmentPlan) bool { return plan.UserID == userID }), nil
+}
+
+func (s *Store) DeleteUser(_ context.Context, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return false, nil
+	}
+	delete(s.users, userID)
+	return true, nil
+}
+
+func (s *Store) StartAccountErasure(_ context.Context, userHash string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	erasure, ok := s.erasures[userHash]
+	if !ok {
+		erasure = types.AccountErasure{UserHash: userHash, StartedAt: at}
+	}
+	erasure.Attempts++
+	s.erasures[userHash] = erasure
+	return nil
+}
+
+func (s *Store) CompleteAccountErasure(
+	_ context.Context,
+	userHash string,
+	at time.Time,
+) (*types.AccountErasure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	erasure, ok := s.erasures[userHash]
+	if !ok {
+		return nil, types.ErrNoDocuments
+	}
+	erasure.CompletedAt = &at
+	s.erasures[userHash] = erasure
+	return &erasure, nil
+}
+
+// deleteWhere removes the entries of m that match and returns how many it removed
+func deleteWhere[T any](m map[string]T, match func(T) bool) int64 {
+	var deleted int64
+	for key, value := range m {
+		if match(value) {
+			delete(m, key)
+			deleted++
+		}
+	}
+	return deleted
+}
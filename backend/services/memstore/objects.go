@@ -0,0 +1,143 @@
+package memstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/qreepex/water-me-app/backend/services"
+)
+
+// Object is a stored object. Data is only kept for objects stored with
+// PutObject; objects from Put read as zeros.
+type Object struct {
+	Size        int64
+	ContentType string
+	Data        []byte
+}
+
+// ObjectStore keeps object metadata in memory. Presigned URLs use the
+// memory:// scheme and are not reachable; tests call Put to simulate an upload.
+type ObjectStore struct {
+	mu      sync.Mutex
+	objects map[string]Object
+}
+
+var _ services.ObjectStore = (*ObjectStore)(nil)
+
+// NewObjectStore returns an empty object store
+func NewObjectStore() *ObjectStore {
+	return &ObjectStore{objects: make(map[string]Object)}
+}
+
+// Put stores an object as if a client had uploaded it
+func (o *ObjectStore) Put(key string, size int64, contentType string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.objects[key] = Object{Size: size, ContentType: contentType}
+}
+
+// Has reports whether an object exists
+func (o *ObjectStore) Has(key string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.objects[key]
+	return ok
+}
+
+func (o *ObjectStore) PresignPutURL(
+	_ context.Context,
+	key, contentType string,
+	userID string,
+) (string, map[string]string, error) {
+	headers := map[string]string{
+		"Content-Type":    contentType,
+		"x-amz-meta-user": userID,
+	}
+	return "memory://objects/" + url.PathEscape(key) + "?op=put", headers, nil
+}
+
+func (o *ObjectStore) PresignGetURL(_ context.Context, key string) (string, error) {
+	return "memory://objects/" + url.PathEscape(key), nil
+}
+
+func (o *ObjectStore) HeadObjectInfo(_ context.Context, key string) (int64, string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	object, ok := o.objects[key]
+	if !ok {
+		return 0, "", fmt.Errorf("head object: %s not found", key)
+	}
+	return object.Size, object.ContentType, nil
+}
+
+func (o *ObjectStore) GetObject(_ context.Context, key string) (io.ReadCloser, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	object, ok := o.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("get object: %s not found", key)
+	}
+	data := object.Data
+	if data == nil {
+		data = make([]byte, object.Size)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (o *ObjectStore) PutObject(
+	_ context.Context,
+	key, contentType string,
+	body io.Reader,
+	_ int64,
+) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.objects[key] = Object{Size: int64(len(data)), ContentType: contentType, Data: data}
+	return nil
+}
+
+func (o *ObjectStore) DeleteObject(_ context.Context, key string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.objects, key)
+	return nil
+}
+
+func (o *ObjectStore) Ready(context.Context) error {
+	return nil
+}
+
+func (o *ObjectStore) DeleteObjects(_ context.Context, keys []string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, key := range keys {
+		delete(o.objects, key)
+	}
+	return nil
+}
+
+func (o *ObjectStore) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	deleted := 0
+	for key := range o.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(o.objects, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
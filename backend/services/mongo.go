@@ -3,16 +3,36 @@ package services
 import (
 	"context"
 	"log"
+	"log/slog"
 	"time"
 
+	"github.com/qreepex/water-me-app/backend/metrics"
+
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 var collectionNames = []string{
 	"plants",
 	"uploads",
 	"notifications",
+	"schema_migrations",
+	"users",
+	"api_tokens",
+	"households",
+	"household_invites",
+	"care_events",
+	"vacations",
+	"plant_shares",
+	"account_erasures",
+	"exports",
+	"species",
+	"safety_profiles",
+	"rooms",
+	"pest_outbreaks",
+	"treatment_plans",
 }
 
 // MongoDB wraps the MongoDB client and database
@@ -36,20 +56,21 @@ func Connect(uri, db, username, password string) (*MongoDB, error) {
 		AuthSource: db,
 		Username:   username,
 		Password:   password,
-	})
+	}).
+		SetMonitor(combineMonitors(metrics.MongoMonitor(), otelmongo.NewMonitor()))
 
 	_client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
-		log.Printf("Failed to connect to MongoDB: %v", err)
+		slog.Error("failed to connect to MongoDB", "error", err)
 		return nil, err
 	}
 
 	if err := _client.Ping(ctx, nil); err != nil {
-		log.Printf("Failed to ping MongoDB: %v", err)
+		slog.Error("failed to ping MongoDB", "error", err)
 		return nil, err
 	}
 
-	log.Println("Connected to MongoDB")
+	slog.Info("connected to MongoDB", "database", db)
 
 	database := _client.Database(db)
 
@@ -78,15 +99,24 @@ func GetClient() *MongoDB {
 	return mongodb
 }
 
-// Close disconnects the MongoDB client
+// Close disconnects the MongoDB client, waiting up to 10 seconds for in-use
+// connections to be returned to the pool
 func (m *MongoDB) Close() {
-	if err := m.client.Disconnect(context.TODO()); err != nil {
-		log.Printf("Failed to disconnect from MongoDB: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := m.client.Disconnect(ctx); err != nil {
+		slog.Error("failed to disconnect from MongoDB", "error", err)
 	} else {
-		log.Println("Disconnected from MongoDB")
+		slog.Info("disconnected from MongoDB")
 	}
 }
 
+// Database returns the underlying database handle, e.g. for schema migrations
+func (m *MongoDB) Database() *mongo.Database {
+	return m.db
+}
+
 // GetCollection returns a MongoDB collection by its name
 func (m *MongoDB) GetCollection(name string) *mongo.Collection {
 	if collection, ok := m.collections[name]; ok {
@@ -97,3 +127,31 @@ func (m *MongoDB) GetCollection(name string) *mongo.Collection {
 
 	return nil
 }
+
+// combineMonitors fans command events out to several monitors, since the driver
+// accepts only one
+func combineMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, evt)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, evt)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, evt)
+				}
+			}
+		},
+	}
+}
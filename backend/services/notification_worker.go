@@ -3,14 +3,17 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/metrics"
 	"github.com/qreepex/water-me-app/backend/types"
 
 	"firebase.google.com/go/messaging"
@@ -26,6 +29,8 @@ type NotificationStats struct {
 	NotificationsSent   int
 	NotificationsFailed int
 	UsersNotified       int
+	Errors              int  // Queries that failed during the run
+	Interrupted         bool // Stopped early because shutdown was requested
 }
 
 type NotificationMessage struct {
@@ -60,16 +65,21 @@ func LoadNotificationMessages(messageDir string) error {
 		}
 
 		messageCache[msgType] = &messages
-		log.Printf("Loaded %d single and %d multiple messages for %s",
-			len(messages.Single), len(messages.Multiple), msgType)
+		slog.Info("loaded notification messages",
+			"type", msgType, "single", len(messages.Single), "multiple", len(messages.Multiple))
 	}
 
 	return nil
 }
 
-// ProcessNotifications processes all notification types and returns statistics
+// ProcessNotifications processes all notification types and returns statistics.
+//
+// Once stop is closed no further users are started, but the user currently
+// being notified is always finished. Because lastNotificationSentAt is written
+// per user, the next run picks up exactly where an interrupted one left off.
 func ProcessNotifications(
 	ctx context.Context,
+	stop <-chan struct{},
 	db *MongoDB,
 	firebase *FirebaseService,
 	plantsBatchSize int,
@@ -77,16 +87,73 @@ func ProcessNotifications(
 	stats := &NotificationStats{}
 
 	// Process each notification type
-	processWateringNotifications(ctx, db, firebase, plantsBatchSize, stats)
-	processFertilizingNotifications(ctx, db, firebase, plantsBatchSize, stats)
-	processMistingNotifications(ctx, db, firebase, plantsBatchSize, stats)
-	processRepottingNotifications(ctx, db, firebase, plantsBatchSize, stats)
+	processors := []func(context.Context, <-chan struct{}, *MongoDB, *FirebaseService, int, *NotificationStats){
+		processWateringNotifications,
+		processFertilizingNotifications,
+		processMistingNotifications,
+		processRepottingNotifications,
+		processVacationSummaries,
+		processPestInspections,
+		processTreatmentReminders,
+	}
+	for _, process := range processors {
+		if stopRequested(stop) {
+			stats.Interrupted = true
+			break
+		}
+		process(ctx, stop, db, firebase, plantsBatchSize, stats)
+	}
 
 	return stats
 }
 
+// stopRequested reports whether the worker has been asked to shut down
+func stopRequested(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// notifyUsers sends notifications user by user until all are done or a stop is requested
+func notifyUsers(
+	ctx context.Context,
+	stop <-chan struct{},
+	db *MongoDB,
+	firebase *FirebaseService,
+	plants []types.Plant,
+	notificationType string,
+	stats *NotificationStats,
+) {
+	households, err := db.GetHouseholdsByIDs(ctx, householdIDs(plants))
+	if err != nil {
+		// Owners are still reminded; only the fan-out to other members is lost
+		slog.ErrorContext(ctx, "failed to fetch households", "type", notificationType, "error", err)
+		stats.Errors++
+	}
+
+	vacations, err := db.GetActiveVacations(ctx, recipientCandidates(plants, households), time.Now())
+	if err != nil {
+		// Without vacations everyone is reminded as usual
+		slog.ErrorContext(ctx, "failed to fetch vacations", "type", notificationType, "error", err)
+		stats.Errors++
+	}
+
+	userPlants := groupPlantsByRecipient(plants, households, vacations)
+	for userID, userPlantList := range userPlants {
+		if stopRequested(stop) {
+			stats.Interrupted = true
+			return
+		}
+		sendNotificationsForUser(ctx, db, firebase, userID, userPlantList, notificationType, stats)
+	}
+}
+
 func processWateringNotifications(
 	ctx context.Context,
+	stop <-chan struct{},
 	db *MongoDB,
 	firebase *FirebaseService,
 	batchSize int,
@@ -94,26 +161,26 @@ func processWateringNotifications(
 ) {
 	plants, err := db.GetPlantsNeedingWatering(ctx, batchSize)
 	if err != nil {
-		log.Printf("Error fetching plants needing watering: %v", err)
+		slog.ErrorContext(ctx, "failed to fetch due plants", "type", "watering", "error", err)
+		stats.Errors++
 		return
 	}
+	metrics.PlantsDue.WithLabelValues("watering").Set(float64(len(plants)))
 
 	if len(plants) == 0 {
-		log.Println("No plants need watering")
+		slog.InfoContext(ctx, "no plants need watering")
 		return
 	}
 
-	log.Printf("Found %d plants needing watering", len(plants))
+	slog.InfoContext(ctx, "found due plants", "type", "watering", "count", len(plants))
 	stats.PlantsChecked += len(plants)
 
-	userPlants := groupPlantsByUser(plants)
-	for userID, userPlantList := range userPlants {
-		sendNotificationsForUser(ctx, db, firebase, userID, userPlantList, "watering", stats)
-	}
+	notifyUsers(ctx, stop, db, firebase, plants, "watering", stats)
 }
 
 func processFertilizingNotifications(
 	ctx context.Context,
+	stop <-chan struct{},
 	db *MongoDB,
 	firebase *FirebaseService,
 	batchSize int,
@@ -121,25 +188,25 @@ func processFertilizingNotifications(
 ) {
 	plants, err := db.GetPlantsNeedingFertilizer(ctx, batchSize)
 	if err != nil {
-		log.Printf("Error fetching plants needing fertilizer: %v", err)
+		slog.ErrorContext(ctx, "failed to fetch due plants", "type", "fertilizing", "error", err)
+		stats.Errors++
 		return
 	}
+	metrics.PlantsDue.WithLabelValues("fertilizing").Set(float64(len(plants)))
 
 	if len(plants) == 0 {
 		return
 	}
 
-	log.Printf("Found %d plants needing fertilizer", len(plants))
+	slog.InfoContext(ctx, "found due plants", "type", "fertilizing", "count", len(plants))
 	stats.PlantsChecked += len(plants)
 
-	userPlants := groupPlantsByUser(plants)
-	for userID, userPlantList := range userPlants {
-		sendNotificationsForUser(ctx, db, firebase, userID, userPlantList, "fertilizing", stats)
-	}
+	notifyUsers(ctx, stop, db, firebase, plants, "fertilizing", stats)
 }
 
 func processMistingNotifications(
 	ctx context.Context,
+	stop <-chan struct{},
 	db *MongoDB,
 	firebase *FirebaseService,
 	batchSize int,
@@ -147,25 +214,25 @@ func processMistingNotifications(
 ) {
 	plants, err := db.GetPlantsNeedingMisting(ctx, batchSize)
 	if err != nil {
-		log.Printf("Error fetching plants needing misting: %v", err)
+		slog.ErrorContext(ctx, "failed to fetch due plants", "type", "misting", "error", err)
+		stats.Errors++
 		return
 	}
+	metrics.PlantsDue.WithLabelValues("misting").Set(float64(len(plants)))
 
 	if len(plants) == 0 {
 		return
 	}
 
-	log.Printf("Found %d plants needing misting", len(plants))
+	slog.InfoContext(ctx, "found due plants", "type", "misting", "count", len(plants))
 	stats.PlantsChecked += len(plants)
 
-	userPlants := groupPlantsByUser(plants)
-	for userID, userPlantList := range userPlants {
-		sendNotificationsForUser(ctx, db, firebase, userID, userPlantList, "misting", stats)
-	}
+	notifyUsers(ctx, stop, db, firebase, plants, "misting", stats)
 }
 
 func processRepottingNotifications(
 	ctx context.Context,
+	stop <-chan struct{},
 	db *MongoDB,
 	firebase *FirebaseService,
 	batchSize int,
@@ -173,31 +240,371 @@ func processRepottingNotifications(
 ) {
 	plants, err := db.GetPlantsNeedingRepotting(ctx, batchSize)
 	if err != nil {
-		log.Printf("Error fetching plants needing repotting: %v", err)
+		slog.ErrorContext(ctx, "failed to fetch due plants", "type", "repotting", "error", err)
+		stats.Errors++
 		return
 	}
+	metrics.PlantsDue.WithLabelValues("repotting").Set(float64(len(plants)))
 
 	if len(plants) == 0 {
 		return
 	}
 
-	log.Printf("Found %d plants needing repotting", len(plants))
+	slog.InfoContext(ctx, "found due plants", "type", "repotting", "count", len(plants))
 	stats.PlantsChecked += len(plants)
 
-	userPlants := groupPlantsByUser(plants)
-	for userID, userPlantList := range userPlants {
-		sendNotificationsForUser(ctx, db, firebase, userID, userPlantList, "repotting", stats)
+	notifyUsers(ctx, stop, db, firebase, plants, "repotting", stats)
+}
+
+// processVacationSummaries tells users who are back from vacation how their
+// plants were cared for. A summary is marked sent even if the user has push
+// notifications disabled or never set them up, so it is not retried forever;
+// the app can still fetch it from the API.
+func processVacationSummaries(
+	ctx context.Context,
+	stop <-chan struct{},
+	db *MongoDB,
+	firebase *FirebaseService,
+	batchSize int,
+	stats *NotificationStats,
+) {
+	vacations, err := db.GetVacationsNeedingSummary(ctx, time.Now(), batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch ended vacations", "error", err)
+		stats.Errors++
+		return
+	}
+
+	for _, vacation := range vacations {
+		if stopRequested(stop) {
+			stats.Interrupted = true
+			return
+		}
+		if err := sendVacationSummary(ctx, db, firebase, vacation, stats); err != nil {
+			slog.ErrorContext(ctx, "failed to send vacation summary", "vacationId", vacation.ID, "error", err)
+			stats.Errors++
+			continue
+		}
+		if err := db.MarkVacationSummarySent(ctx, vacation.ID, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "failed to mark vacation summary sent", "vacationId", vacation.ID, "error", err)
+			stats.Errors++
+		}
 	}
 }
 
-func groupPlantsByUser(plants []types.Plant) map[string][]types.Plant {
+func sendVacationSummary(
+	ctx context.Context,
+	db *MongoDB,
+	firebase *FirebaseService,
+	vacation types.Vacation,
+	stats *NotificationStats,
+) error {
+	ctx = logging.WithAttrs(ctx, "userHash", logging.HashUserID(vacation.UserID))
+
+	events, err := db.GetCareEvents(ctx, vacation.UserID, vacation.StartsAt, vacation.EndsAt)
+	if err != nil {
+		return err
+	}
+	plants, err := db.GetPlants(ctx, vacation.UserID)
+	if err != nil {
+		return err
+	}
+	summary := BuildVacationSummary(vacation, plants, events)
+
+	title, body := buildVacationSummaryMessage(summary)
+	sent, err := pushToUser(
+		ctx,
+		db,
+		firebase,
+		vacation.UserID,
+		title,
+		body,
+		"vacation_summary",
+		len(summary.Plants),
+		stats,
+	)
+	if err != nil {
+		return err
+	}
+	if sent {
+		slog.InfoContext(ctx, "sent vacation summary", "events", summary.TotalEvents)
+	}
+	return nil
+}
+
+// pushToUser sends a single notification to the active devices of userID if
+// they have push notifications enabled, and reports whether it was sent
+func pushToUser(
+	ctx context.Context,
+	db *MongoDB,
+	firebase *FirebaseService,
+	userID string,
+	title, body, notificationType string,
+	plantCount int,
+	stats *NotificationStats,
+) (bool, error) {
+	config, err := db.GetNotificationConfig(ctx, userID)
+	if errors.Is(err, types.ErrNoDocuments) {
+		// The user never set up push notifications
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !config.IsEnabled {
+		return false, nil
+	}
+	activeTokens := getActiveTokens(config.DeviceTokens)
+	if len(activeTokens) == 0 {
+		return false, nil
+	}
+
+	failedTokens := sendNotificationBatches(
+		ctx,
+		firebase,
+		activeTokens,
+		title,
+		body,
+		notificationType,
+		plantCount,
+		stats,
+	)
+	if len(failedTokens) > 0 {
+		if err := db.MarkTokensAsInactive(ctx, userID, failedTokens); err != nil {
+			slog.ErrorContext(ctx, "failed to mark tokens inactive", "error", err)
+		} else {
+			metrics.TokensDeactivated.Add(float64(len(failedTokens)))
+		}
+	}
+
+	stats.UsersNotified++
+	return true, nil
+}
+
+// processPestInspections reminds owners to inspect the plants at risk of an
+// open pest outbreak on the days of InspectionScheduleDays. Like vacation
+// summaries, inspections are counted as sent when pushes are disabled or
+// were never set up, so the outbreak moves on to its next inspection.
+func processPestInspections(
+	ctx context.Context,
+	stop <-chan struct{},
+	db *MongoDB,
+	firebase *FirebaseService,
+	batchSize int,
+	stats *NotificationStats,
+) {
+	now := time.Now()
+	outbreaks, err := db.GetOutbreaksNeedingInspection(ctx, now, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch outbreaks needing inspection", "error", err)
+		stats.Errors++
+		return
+	}
+
+	for _, outbreak := range outbreaks {
+		if stopRequested(stop) {
+			stats.Interrupted = true
+			return
+		}
+		ctx := logging.WithAttrs(ctx, "userHash", logging.HashUserID(outbreak.UserID))
+
+		title, body := buildInspectionMessage(outbreak)
+		count := len(outbreak.Infected) + len(outbreak.AtRisk)
+		if _, err := pushToUser(ctx, db, firebase, outbreak.UserID, title, body, "inspection", count, stats); err != nil {
+			slog.ErrorContext(ctx, "failed to send inspection reminder", "outbreakId", outbreak.ID, "error", err)
+			stats.Errors++
+			continue
+		}
+
+		sent := outbreak.InspectionsSent + 1
+		next := NextInspection(outbreak.DetectedAt, sent)
+		if _, err := db.MarkOutbreakInspected(ctx, outbreak.ID, sent, next, now); err != nil {
+			slog.ErrorContext(ctx, "failed to schedule next inspection", "outbreakId", outbreak.ID, "error", err)
+			stats.Errors++
+		}
+	}
+}
+
+func buildInspectionMessage(outbreak types.PestOutbreak) (string, string) {
+	title := fmt.Sprintf("Check %s for %s 🔍", outbreak.Room, strings.ToLower(string(outbreak.Pest)))
+	if len(outbreak.AtRisk) == 0 {
+		return title, "Look over your quarantined plants and update their pest history."
+	}
+	names := make([]string, 0, len(outbreak.AtRisk))
+	for _, plant := range outbreak.AtRisk {
+		names = append(names, plant.Name)
+	}
+	return title, fmt.Sprintf("Inspect %s for signs of spreading.", strings.Join(names, ", "))
+}
+
+// processTreatmentReminders reminds owners to apply the next treatment of a
+// plan when it is due, and daily while it is overdue, also when pushes are
+// disabled or failed. Plans whose plant was deleted or whose infection was
+// resolved are stopped instead.
+func processTreatmentReminders(
+	ctx context.Context,
+	stop <-chan struct{},
+	db *MongoDB,
+	firebase *FirebaseService,
+	batchSize int,
+	stats *NotificationStats,
+) {
+	now := time.Now()
+	plans, err := db.GetTreatmentPlansNeedingReminder(ctx, now, batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch treatment plans needing a reminder", "error", err)
+		stats.Errors++
+		return
+	}
+
+	for _, plan := range plans {
+		if stopRequested(stop) {
+			stats.Interrupted = true
+			return
+		}
+		ctx := logging.WithAttrs(ctx, "userHash", logging.HashUserID(plan.UserID))
+
+		plant, err := db.GetPlantByID(ctx, plan.PlantID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to fetch plant of treatment plan", "planId", plan.ID, "error", err)
+			stats.Errors++
+			continue
+		}
+		if TreatmentEnded(plan, plant) {
+			if _, err := db.StopTreatmentPlan(ctx, plan.ID, now); err != nil {
+				slog.ErrorContext(ctx, "failed to stop treatment plan", "planId", plan.ID, "error", err)
+				stats.Errors++
+			}
+			continue
+		}
+		title, body := buildTreatmentMessage(*plant, plan)
+		if _, err := pushToUser(ctx, db, firebase, plan.UserID, title, body, "treatment", 1, stats); err != nil {
+			slog.ErrorContext(ctx, "failed to send treatment reminder", "planId", plan.ID, "error", err)
+			stats.Errors++
+		}
+		// The reminder moves on even if nothing was sent, so a plan is tried
+		// again tomorrow rather than on every run. An application logged
+		// meanwhile already moved it, which leaves the plan unmatched here.
+		if _, err := db.RescheduleTreatmentReminder(
+			ctx, plan.ID, *plan.NextReminderAt, now.Add(TreatmentReminderRetry), now,
+		); err != nil {
+			slog.ErrorContext(ctx, "failed to update treatment plan", "planId", plan.ID, "error", err)
+			stats.Errors++
+		}
+	}
+}
+
+func buildTreatmentMessage(plant types.Plant, plan types.TreatmentPlan) (string, string) {
+	title := fmt.Sprintf("Time to treat %s again 🧴", plant.Name)
+	if len(plan.Applications) == 0 {
+		title = fmt.Sprintf("Time to treat %s 🧴", plant.Name)
+	}
+	body := fmt.Sprintf("Apply %s against %s (treatment %d of %d).",
+		plan.Product, strings.ToLower(string(plan.Pest)), len(plan.Applications)+1, plan.Repetitions)
+	return title, body
+}
+
+func buildVacationSummaryMessage(summary types.VacationSummary) (string, string) {
+	caretaker := summary.CaretakerName
+	if caretaker == "" {
+		caretaker = "Your caretaker"
+	}
+
+	var body string
+	switch {
+	case summary.TotalEvents == 0:
+		body = "No care was logged while you were away. Time to check on your plants!"
+	case len(summary.NotCaredFor) == 0:
+		body = fmt.Sprintf("%s logged %d care actions and looked after all of your plants.",
+			caretaker, summary.TotalEvents)
+	default:
+		body = fmt.Sprintf("%s logged %d care actions. Not cared for: %s.",
+			caretaker, summary.TotalEvents, strings.Join(summary.NotCaredFor, ", "))
+	}
+	return "Welcome back! 🌿", body
+}
+
+// groupPlantsByRecipient maps each user to remind to their due plants. Plants
+// shared with a household also go to every member who can care for them.
+// While an owner is on vacation their reminders go to the caretaker instead;
+// other members on vacation are skipped.
+func groupPlantsByRecipient(
+	plants []types.Plant,
+	households []types.Household,
+	vacations []types.Vacation,
+) map[string][]types.Plant {
+	byID := make(map[string]types.Household, len(households))
+	for _, household := range households {
+		byID[household.ID] = household
+	}
+	away := make(map[string]types.Vacation, len(vacations))
+	for _, vacation := range vacations {
+		away[vacation.UserID] = vacation
+	}
+
 	grouped := make(map[string][]types.Plant)
 	for _, plant := range plants {
-		grouped[plant.UserID] = append(grouped[plant.UserID], plant)
+		recipients := make(map[string]bool)
+		if vacation, ok := away[plant.UserID]; ok && vacation.CaretakerID != "" {
+			recipients[vacation.CaretakerID] = true
+		} else {
+			// Without a caretaker the owner keeps getting reminders
+			recipients[plant.UserID] = true
+		}
+
+		if household, ok := byID[plant.HouseholdID]; ok {
+			for _, member := range household.Members {
+				if member.UserID == plant.UserID || !member.Role.CanCare() {
+					continue
+				}
+				if _, onVacation := away[member.UserID]; onVacation {
+					continue
+				}
+				recipients[member.UserID] = true
+			}
+		}
+
+		for userID := range recipients {
+			grouped[userID] = append(grouped[userID], plant)
+		}
 	}
 	return grouped
 }
 
+// recipientCandidates returns the owners and household members who may be
+// reminded about the plants
+func recipientCandidates(plants []types.Plant, households []types.Household) []string {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	add := func(userID string) {
+		if !seen[userID] {
+			seen[userID] = true
+			ids = append(ids, userID)
+		}
+	}
+	for _, plant := range plants {
+		add(plant.UserID)
+	}
+	for _, household := range households {
+		for _, member := range household.Members {
+			add(member.UserID)
+		}
+	}
+	return ids
+}
+
+func householdIDs(plants []types.Plant) []string {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, plant := range plants {
+		if plant.HouseholdID != "" && !seen[plant.HouseholdID] {
+			seen[plant.HouseholdID] = true
+			ids = append(ids, plant.HouseholdID)
+		}
+	}
+	return ids
+}
+
 func sendNotificationsForUser(
 	ctx context.Context,
 	db *MongoDB,
@@ -207,6 +614,8 @@ func sendNotificationsForUser(
 	notificationType string,
 	stats *NotificationStats,
 ) {
+	ctx = logging.WithAttrs(ctx, "userHash", logging.HashUserID(userID))
+
 	// Get user's notification config
 	config, err := db.GetNotificationConfig(ctx, userID)
 	if err != nil || config == nil {
@@ -261,22 +670,19 @@ func sendNotificationsForUser(
 	// Handle failed tokens
 	if len(failedTokens) > 0 {
 		if err := db.MarkTokensAsInactive(ctx, userID, failedTokens); err != nil {
-			log.Printf("Error marking tokens as inactive for user %s: %v", userID, err)
+			slog.ErrorContext(ctx, "failed to mark tokens inactive", "error", err)
+		} else {
+			metrics.TokensDeactivated.Add(float64(len(failedTokens)))
 		}
 	}
 
 	// Update last notification sent timestamp
 	if err := db.UpdateNotificationLastSent(ctx, userID); err != nil {
-		log.Printf("Error updating last notification sent for user %s: %v", userID, err)
+		slog.ErrorContext(ctx, "failed to update last notification time", "error", err)
 	}
 
 	stats.UsersNotified++
-	log.Printf(
-		"Sent %s notifications to user %s for %d plants",
-		notificationType,
-		userID,
-		len(notifyPlants),
-	)
+	slog.InfoContext(ctx, "sent notifications", "type", notificationType, "plants", len(notifyPlants))
 }
 
 func isNotificationTypeEnabled(config *types.NotificationConfig, notificationType string) bool {
@@ -343,18 +749,21 @@ func sendNotificationBatches(
 
 		response, err := firebase.SendMulticastNotification(ctx, tokenBatch, title, body, data)
 		if err != nil {
-			log.Printf("Error sending notification batch: %v", err)
+			slog.ErrorContext(ctx, "failed to send notification batch", "type", notificationType, "error", err)
 			stats.NotificationsFailed += len(tokenBatch)
+			metrics.NotificationsFailed.WithLabelValues(notificationType).Add(float64(len(tokenBatch)))
 			continue
 		}
 
 		// Track success/failure
 		stats.NotificationsSent += response.SuccessCount
 		stats.NotificationsFailed += response.FailureCount
+		metrics.NotificationsSent.WithLabelValues(notificationType).Add(float64(response.SuccessCount))
+		metrics.NotificationsFailed.WithLabelValues(notificationType).Add(float64(response.FailureCount))
 
 		// Collect failed tokens
 		if response.FailureCount > 0 {
-			failed := extractFailedTokens(tokenBatch, response)
+			failed := extractFailedTokens(ctx, tokenBatch, response)
 			failedTokens = append(failedTokens, failed...)
 		}
 	}
@@ -362,19 +771,19 @@ func sendNotificationBatches(
 	return failedTokens
 }
 
-func extractFailedTokens(tokens []string, response *messaging.BatchResponse) []string {
+func extractFailedTokens(
+	ctx context.Context,
+	tokens []string,
+	response *messaging.BatchResponse,
+) []string {
 	var failed []string
 	for i, sendResponse := range response.Responses {
 		if !sendResponse.Success {
 			if i < len(tokens) {
 				failed = append(failed, tokens[i])
-				// Log the error for debugging
+				// Log the error without the token itself, which is a credential
 				if sendResponse.Error != nil {
-					log.Printf(
-						"Token failed: %s, Error: %v",
-						tokens[i][:20]+"...",
-						sendResponse.Error,
-					)
+					slog.WarnContext(ctx, "device token rejected", "index", i, "error", sendResponse.Error)
 				}
 			}
 		}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestGroupPlantsByRecipient_FansOutToCaringMembers(t *testing.T) {
+	plants := []types.Plant{
+		{ID: "p1", UserID: "owner", HouseholdID: "h1"},
+		{ID: "p2", UserID: "owner"},
+		{ID: "p3", UserID: "editor", HouseholdID: "h1"},
+	}
+	households := []types.Household{{
+		ID: "h1",
+		Members: []types.HouseholdMember{
+			{UserID: "owner", Role: types.RoleOwner},
+			{UserID: "editor", Role: types.RoleEditor},
+			{UserID: "sitter", Role: types.RoleCaretaker},
+			{UserID: "grandma", Role: types.RoleViewer},
+		},
+	}}
+
+	grouped := groupPlantsByRecipient(plants, households, nil)
+
+	want := map[string][]string{
+		"owner":  {"p1", "p2", "p3"},
+		"editor": {"p1", "p3"},
+		"sitter": {"p1", "p3"},
+	}
+	if len(grouped) != len(want) {
+		t.Fatalf("got %d recipients, want %d: %v", len(grouped), len(want), grouped)
+	}
+	for userID, ids := range want {
+		got := grouped[userID]
+		if len(got) != len(ids) {
+			t.Errorf("%s: got %d plants, want %v", userID, len(got), ids)
+			continue
+		}
+		for i, id := range ids {
+			if got[i].ID != id {
+				t.Errorf("%s: plant %d is %s, want %s", userID, i, got[i].ID, id)
+			}
+		}
+	}
+}
+
+func TestGroupPlantsByRecipient_RedirectsToVacationCaretaker(t *testing.T) {
+	plants := []types.Plant{
+		{ID: "p1", UserID: "owner", HouseholdID: "h1"},
+		{ID: "p2", UserID: "partner"},
+		{ID: "p3", UserID: "traveller"},
+	}
+	households := []types.Household{{
+		ID: "h1",
+		Members: []types.HouseholdMember{
+			{UserID: "owner", Role: types.RoleOwner},
+			{UserID: "partner", Role: types.RoleEditor},
+		},
+	}}
+	vacations := []types.Vacation{
+		{UserID: "owner", CaretakerID: "neighbour"},
+		{UserID: "partner"},
+		{UserID: "traveller"},
+	}
+
+	grouped := groupPlantsByRecipient(plants, households, vacations)
+
+	// The owner's plant goes to the caretaker, the partner on vacation is
+	// skipped for the shared plant, and owners without a caretaker keep theirs
+	want := map[string][]string{
+		"neighbour": {"p1"},
+		"partner":   {"p2"},
+		"traveller": {"p3"},
+	}
+	if len(grouped) != len(want) {
+		t.Fatalf("got %d recipients, want %d: %v", len(grouped), len(want), grouped)
+	}
+	for userID, ids := range want {
+		got := grouped[userID]
+		if len(got) != len(ids) || got[0].ID != ids[0] {
+			t.Errorf("%s: got %v, want %v", userID, got, ids)
+		}
+	}
+}
+
+func TestPushToUser_SkipsUsersWithoutNotificationConfig(t *testing.T) {
+	// A nil collection makes GetNotificationConfig report no document, like
+	// for a user who never set up push notifications
+	db := &MongoDB{collections: map[string]*mongo.Collection{
+		constants.MongoDBCollections.Notifications: nil,
+	}}
+	stats := &NotificationStats{}
+
+	sent, err := pushToUser(context.Background(), db, nil, "user", "title", "body", "vacation_summary", 1, stats)
+	if err != nil {
+		t.Fatalf("pushToUser: %v", err)
+	}
+	if sent {
+		t.Error("got sent, want not sent")
+	}
+	if stats.UsersNotified != 0 || stats.Errors != 0 {
+		t.Errorf("got %+v, want no users notified and no errors", *stats)
+	}
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// InspectionScheduleDays are the days after an outbreak is detected on which
+// the owner is reminded to inspect the plants at risk
+var InspectionScheduleDays = []int{3, 7, 14}
+
+// NextInspection returns when the inspection after the first sent ones is
+// due for an outbreak detected at detectedAt, or nil after the last one
+func NextInspection(detectedAt time.Time, sent int) *time.Time {
+	if sent >= len(InspectionScheduleDays) {
+		return nil
+	}
+	next := detectedAt.AddDate(0, 0, InspectionScheduleDays[sent])
+	return &next
+}
+
+// NewlyActiveInfections returns the active infections of after that were not
+// active in before
+func NewlyActiveInfections(before, after []types.PestInfection) []types.PestInfection {
+	active := make(map[string]bool, len(before))
+	for _, infection := range before {
+		if infection.Status == types.PestStatusActive {
+			active[infection.ID] = true
+		}
+	}
+	var infections []types.PestInfection
+	for _, infection := range after {
+		if infection.Status == types.PestStatusActive && !active[infection.ID] {
+			infections = append(infections, infection)
+		}
+	}
+	return infections
+}
+
+// HasActivePest reports whether plant has an active infection of pest
+func HasActivePest(plant types.Plant, pest types.PestType) bool {
+	for _, infection := range plant.PestHistory {
+		if infection.Pest == pest && infection.Status == types.PestStatusActive {
+			return true
+		}
+	}
+	return false
+}
+
+// outbreakLocation returns the key of the room plant is in, or "" if it is
+// not in a room. Plants not linked to a room are matched by name.
+func outbreakLocation(plant types.Plant) string {
+	if plant.Location == nil {
+		return ""
+	}
+	if plant.Location.RoomID != "" {
+		return plant.Location.RoomID
+	}
+	if key := types.RoomNameKey(plant.Location.Room); key != "" {
+		return "name:" + key
+	}
+	return ""
+}
+
+// PestTracker groups active pest infections of plants in the same room into
+// outbreaks and flags the other plants in the room as at risk. It also ends
+// the treatment plans of infections that are over.
+type PestTracker struct {
+	plants     PlantStore
+	outbreaks  PestOutbreakStore
+	treatments TreatmentPlanStore
+}
+
+// NewPestTracker creates a PestTracker
+func NewPestTracker(
+	plants PlantStore,
+	outbreaks PestOutbreakStore,
+	treatments TreatmentPlanStore,
+) *PestTracker {
+	return &PestTracker{plants: plants, outbreaks: outbreaks, treatments: treatments}
+}
+
+// Record updates the outbreaks of the owner of after, the saved state of a
+// plant that was created (before is nil) or updated. Newly active infections
+// start an outbreak in the plant's room or join the open one of the same
+// pest there; outbreaks are contained once none of their infected plants has
+// the pest active any more. Record returns the outbreaks started or joined.
+func (t *PestTracker) Record(
+	ctx context.Context,
+	before *types.Plant,
+	after types.Plant,
+	now time.Time,
+) ([]types.PestOutbreak, error) {
+	var previous []types.PestInfection
+	if before != nil {
+		previous = before.PestHistory
+	}
+	infections := NewlyActiveInfections(previous, after.PestHistory)
+
+	outbreaks, err := t.outbreaks.GetOutbreaks(ctx, after.UserID)
+	if err != nil {
+		return nil, err
+	}
+	involved := false
+	for _, outbreak := range outbreaks {
+		if outbreak.Status == types.OutbreakOpen && hasOutbreakPlant(outbreak.Infected, after.ID) {
+			involved = true
+			break
+		}
+	}
+	location := outbreakLocation(after)
+	if !involved && (location == "" || len(infections) == 0) {
+		return nil, nil
+	}
+
+	plants, err := t.plants.GetPlants(ctx, after.UserID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]types.Plant, len(plants))
+	for _, plant := range plants {
+		byID[plant.ID] = plant
+	}
+	byID[after.ID] = after
+
+	var changed []types.PestOutbreak
+	if location != "" {
+		seen := make(map[types.PestType]bool)
+		for _, infection := range infections {
+			if seen[infection.Pest] {
+				continue
+			}
+			seen[infection.Pest] = true
+
+			outbreak, err := t.spread(ctx, outbreaks, plants, after, location, infection.Pest, now)
+			if err != nil {
+				return nil, err
+			}
+			changed = append(changed, outbreak)
+		}
+	}
+
+	for _, outbreak := range outbreaks {
+		if outbreak.Status != types.OutbreakOpen || !hasOutbreakPlant(outbreak.Infected, after.ID) {
+			continue
+		}
+		contained := true
+		for _, infected := range outbreak.Infected {
+			if plant, ok := byID[infected.PlantID]; ok && HasActivePest(plant, outbreak.Pest) {
+				contained = false
+				break
+			}
+		}
+		if !contained {
+			continue
+		}
+		// Not contained after all if a plant joined since it was read
+		if _, err := t.outbreaks.ContainOutbreak(ctx, outbreak.ID, len(outbreak.Infected), now); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// spread adds plant to the open outbreak of pest at location, or starts one
+func (t *PestTracker) spread(
+	ctx context.Context,
+	outbreaks []types.PestOutbreak,
+	plants []types.Plant,
+	plant types.Plant,
+	location string,
+	pest types.PestType,
+	now time.Time,
+) (types.PestOutbreak, error) {
+	for _, outbreak := range outbreaks {
+		if outbreak.Status != types.OutbreakOpen || outbreak.LocationKey != location || outbreak.Pest != pest {
+			continue
+		}
+		joined := &outbreak
+		if !hasOutbreakPlant(outbreak.Infected, plant.ID) {
+			// The plant is added to the stored outbreak, so plants joining
+			// it at the same time are all kept
+			added, err := t.outbreaks.AddOutbreakPlant(ctx, outbreak.ID, outbreakPlant(plant), plant.Location.Room, now)
+			if err != nil {
+				return types.PestOutbreak{}, err
+			}
+			if added == nil {
+				// Contained since it was read
+				continue
+			}
+			joined = added
+		}
+		joined.AtRisk = atRiskPlants(plants, joined.Infected, location)
+		joined.Quarantine = quarantineMessage(*joined)
+		joined.UpdatedAt = now
+		// Skipped if another plant joined meanwhile; its own update saves
+		// the plants at risk of both
+		_, err := t.outbreaks.SetOutbreakAtRisk(ctx, joined.ID, len(joined.Infected), joined.AtRisk, joined.Quarantine, now)
+		if err != nil {
+			return types.PestOutbreak{}, err
+		}
+		return *joined, nil
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		return types.PestOutbreak{}, err
+	}
+	outbreak := types.PestOutbreak{
+		ID:          id,
+		UserID:      plant.UserID,
+		Pest:        pest,
+		RoomID:      plant.Location.RoomID,
+		Room:        plant.Location.Room,
+		LocationKey: location,
+		Status:      types.OutbreakOpen,
+		DetectedAt:  now,
+		UpdatedAt:   now,
+	}
+	// Plants in the room that already have the pest are part of it too
+	for _, other := range plants {
+		if other.ID == plant.ID || outbreakLocation(other) != location || !HasActivePest(other, pest) {
+			continue
+		}
+		outbreak.Infected = append(outbreak.Infected, outbreakPlant(other))
+	}
+	outbreak.Infected = append(outbreak.Infected, outbreakPlant(plant))
+	outbreak.AtRisk = atRiskPlants(plants, outbreak.Infected, location)
+	outbreak.Quarantine = quarantineMessage(outbreak)
+	outbreak.NextInspectionAt = NextInspection(now, 0)
+
+	created, err := t.outbreaks.CreateOutbreak(ctx, outbreak)
+	if err != nil {
+		return types.PestOutbreak{}, err
+	}
+	return *created, nil
+}
+
+func outbreakPlant(plant types.Plant) types.OutbreakPlant {
+	return types.OutbreakPlant{PlantID: plant.ID, Name: plant.Name}
+}
+
+func hasOutbreakPlant(plants []types.OutbreakPlant, plantID string) bool {
+	for _, plant := range plants {
+		if plant.PlantID == plantID {
+			return true
+		}
+	}
+	return false
+}
+
+// atRiskPlants returns the plants at location that are not infected
+func atRiskPlants(plants []types.Plant, infected []types.OutbreakPlant, location string) []types.OutbreakPlant {
+	atRisk := make([]types.OutbreakPlant, 0)
+	for _, plant := range plants {
+		if outbreakLocation(plant) == location && !hasOutbreakPlant(infected, plant.ID) {
+			atRisk = append(atRisk, outbreakPlant(plant))
+		}
+	}
+	sort.Slice(atRisk, func(i, j int) bool { return atRisk[i].Name < atRisk[j].Name })
+	return atRisk
+}
+
+func quarantineMessage(outbreak types.PestOutbreak) string {
+	names := make([]string, 0, len(outbreak.Infected))
+	for _, plant := range outbreak.Infected {
+		names = append(names, plant.Name)
+	}
+	message := fmt.Sprintf("Move %s out of %s and keep them apart until the %s are gone",
+		strings.Join(names, ", "), outbreak.Room, strings.ToLower(string(outbreak.Pest)))
+	if len(outbreak.AtRisk) > 0 {
+		message += fmt.Sprintf("; inspect the %d other plants there every few days", len(outbreak.AtRisk))
+	}
+	return message
+}
+
+// PestStatistics counts the infections of plants by pest and status
+func PestStatistics(plants []types.Plant, outbreaks []types.PestOutbreak) types.PestStats {
+	stats := types.PestStats{
+		ByStatus: make(map[types.PestStatus]int),
+		ByPest:   make([]types.PestTypeStats, 0),
+	}
+	byPest := make(map[types.PestType]*types.PestTypeStats)
+	var pests []types.PestType
+	for _, plant := range plants {
+		infested := false
+		for _, infection := range plant.PestHistory {
+			stats.Infections++
+			stats.ByStatus[infection.Status]++
+			pest, ok := byPest[infection.Pest]
+			if !ok {
+				pest = &types.PestTypeStats{Pest: infection.Pest, ByStatus: make(map[types.PestStatus]int)}
+				byPest[infection.Pest] = pest
+				pests = append(pests, infection.Pest)
+			}
+			pest.Total++
+			pest.ByStatus[infection.Status]++
+			if infection.Status == types.PestStatusActive {
+				infested = true
+			}
+		}
+		if infested {
+			stats.InfestedPlants++
+		}
+	}
+	for _, pest := range pests {
+		stats.ByPest = append(stats.ByPest, *byPest[pest])
+	}
+	sort.SliceStable(stats.ByPest, func(i, j int) bool {
+		if stats.ByPest[i].Total != stats.ByPest[j].Total {
+			return stats.ByPest[i].Total > stats.ByPest[j].Total
+		}
+		return stats.ByPest[i].Pest < stats.ByPest[j].Pest
+	})
+	for _, outbreak := range outbreaks {
+		if outbreak.Status == types.OutbreakOpen {
+			stats.OpenOutbreaks++
+		}
+	}
+	return stats
+}
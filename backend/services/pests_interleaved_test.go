@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/services/memstore"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// interleavedOutbreaks runs during once after the outbreaks were read, like a
+// request for another plant that is handled at the same time
+type interleavedOutbreaks struct {
+	*memstore.Store
+	during func()
+}
+
+func (s *interleavedOutbreaks) GetOutbreaks(ctx context.Context, userID string) ([]types.PestOutbreak, error) {
+	outbreaks, err := s.Store.GetOutbreaks(ctx, userID)
+	if during := s.during; during != nil {
+		s.during = nil
+		during()
+	}
+	return outbreaks, err
+}
+
+// kitchenPlants creates clean plants in the same room and returns them
+func kitchenPlants(t *testing.T, store *memstore.Store, names ...string) []types.Plant {
+	t.Helper()
+	var plants []types.Plant
+	for _, name := range names {
+		plant, err := store.CreatePlant(context.Background(), types.Plant{
+			UserID:   "user1",
+			Name:     name,
+			Slug:     name,
+			Location: &types.Location{RoomID: "kitchen", Room: "Kitchen"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		plants = append(plants, *plant)
+	}
+	return plants
+}
+
+func withAphids(plant types.Plant, status types.PestStatus) types.Plant {
+	plant.PestHistory = []types.PestInfection{{ID: "i-" + plant.Name, Pest: types.PestAphids, Status: status}}
+	return plant
+}
+
+func TestPestTracker_KeepsPlantsJoiningAtTheSameTime(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	outbreaks := &interleavedOutbreaks{Store: store}
+	tracker := services.NewPestTracker(store, outbreaks, store)
+	plants := kitchenPlants(t, store, "Basil", "Mint", "Thyme", "Sage")
+	basil, mint, thyme := plants[0], plants[1], plants[2]
+
+	now := time.Now()
+	if _, err := tracker.Record(ctx, &basil, withAphids(basil, types.PestStatusActive), now); err != nil {
+		t.Fatal(err)
+	}
+	outbreaks.during = func() {
+		if _, err := tracker.Record(ctx, &thyme, withAphids(thyme, types.PestStatusActive), now); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := tracker.Record(ctx, &mint, withAphids(mint, types.PestStatusActive), now); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.GetOutbreaks(ctx, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || len(stored[0].Infected) != 3 {
+		t.Fatalf("outbreaks: got %+v, want one with Basil, Thyme and Mint infected", stored)
+	}
+	if len(stored[0].AtRisk) != 1 || stored[0].AtRisk[0].Name != "Sage" {
+		t.Errorf("at risk: got %+v, want Sage", stored[0].AtRisk)
+	}
+}
+
+func TestPestTracker_DoesNotContainOutbreakAPlantJustJoined(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	outbreaks := &interleavedOutbreaks{Store: store}
+	tracker := services.NewPestTracker(store, outbreaks, store)
+	plants := kitchenPlants(t, store, "Basil", "Mint")
+	basil, mint := plants[0], plants[1]
+
+	now := time.Now()
+	infected := withAphids(basil, types.PestStatusActive)
+	if _, err := tracker.Record(ctx, &basil, infected, now); err != nil {
+		t.Fatal(err)
+	}
+	outbreaks.during = func() {
+		if _, err := tracker.Record(ctx, &mint, withAphids(mint, types.PestStatusActive), now); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := tracker.Record(ctx, &infected, withAphids(basil, types.PestStatusResolved), now); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.GetOutbreaks(ctx, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].Status != types.OutbreakOpen || len(stored[0].Infected) != 2 {
+		t.Errorf("outbreaks: got %+v, want one open outbreak with both plants", stored)
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestNewlyActiveInfections_IgnoresInfectionsAlreadyActive(t *testing.T) {
+	before := []types.PestInfection{
+		{ID: "a", Pest: types.PestAphids, Status: types.PestStatusActive},
+		{ID: "b", Pest: types.PestThrips, Status: types.PestStatusTreated},
+	}
+	after := []types.PestInfection{
+		{ID: "a", Pest: types.PestAphids, Status: types.PestStatusActive},
+		{ID: "b", Pest: types.PestThrips, Status: types.PestStatusActive},
+		{ID: "c", Pest: types.PestScale, Status: types.PestStatusResolved},
+		{ID: "d", Pest: types.PestFungusGnats, Status: types.PestStatusActive},
+	}
+
+	infections := NewlyActiveInfections(before, after)
+	if len(infections) != 2 || infections[0].ID != "b" || infections[1].ID != "d" {
+		t.Errorf("got %+v, want b and d", infections)
+	}
+}
+
+func TestNextInspection_FollowsSchedule(t *testing.T) {
+	detected := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC)
+	for sent, days := range InspectionScheduleDays {
+		next := NextInspection(detected, sent)
+		if next == nil || !next.Equal(detected.AddDate(0, 0, days)) {
+			t.Errorf("after %d inspections: got %v, want day %d", sent, next, days)
+		}
+	}
+	if next := NextInspection(detected, len(InspectionScheduleDays)); next != nil {
+		t.Errorf("after the last inspection: got %v, want nil", next)
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// maxRoomSuggestions bounds the better rooms suggested for a plant
+	maxRoomSuggestions = 3
+	// minSuggestionGain is how many points a room must beat the current one by
+	minSuggestionGain = 15
+)
+
+// lightLevels orders sunlight values from darkest to brightest
+var lightLevels = map[types.SunlightRequirement]int{
+	types.SunlightFullShade:          0,
+	types.SunlightPartialToFullShade: 1,
+	types.SunlightPartialShade:       2,
+	types.SunlightIndirectSun:        3,
+	types.SunlightFullSun:            4,
+}
+
+// PlacementStatusFor rates a score: good from 80, fair from 50
+func PlacementStatusFor(score int) types.PlacementStatus {
+	switch {
+	case score >= 80:
+		return types.PlacementGood
+	case score >= 50:
+		return types.PlacementFair
+	default:
+		return types.PlacementPoor
+	}
+}
+
+// PlacementChecks compares the needs of plant with the conditions of room.
+// Factors the plant or the room say nothing about are left out.
+func PlacementChecks(plant types.Plant, room types.Room) []types.PlacementCheck {
+	checks := make([]types.PlacementCheck, 0, 3)
+	if check, ok := lightCheck(plant, room); ok {
+		checks = append(checks, check)
+	}
+	if check, ok := temperatureCheck(plant, room); ok {
+		checks = append(checks, check)
+	}
+	if check, ok := humidityCheck(plant, room); ok {
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func lightCheck(plant types.Plant, room types.Room) (types.PlacementCheck, bool) {
+	if plant.Sunlight == nil || room.Light == nil {
+		return types.PlacementCheck{}, false
+	}
+	need, okNeed := lightLevels[*plant.Sunlight]
+	has, okHas := lightLevels[*room.Light]
+	if !okNeed || !okHas {
+		return types.PlacementCheck{}, false
+	}
+
+	// Each step of the scale costs more the further off it is
+	score := [...]int{100, 70, 30, 0, 0}[abs(need-has)]
+	message := fmt.Sprintf("%s gets %s, as %s needs", room.Name, *room.Light, plant.Name)
+	switch {
+	case has < need:
+		message = fmt.Sprintf("%s needs %s but %s only gets %s", plant.Name, *plant.Sunlight, room.Name, *room.Light)
+	case has > need:
+		message = fmt.Sprintf("%s needs %s but %s gets %s", plant.Name, *plant.Sunlight, room.Name, *room.Light)
+	}
+	return newCheck(types.FactorLight, score, message), true
+}
+
+func temperatureCheck(plant types.Plant, room types.Room) (types.PlacementCheck, bool) {
+	if room.TemperatureCelsius == nil {
+		return types.PlacementCheck{}, false
+	}
+	temp := *room.TemperatureCelsius
+
+	// Below the minimum the plant tolerates is unsuitable whatever it prefers
+	if plant.Seasonality != nil && plant.Seasonality.MinTempCelsius != 0 && temp < plant.Seasonality.MinTempCelsius {
+		return newCheck(types.FactorTemperature, 0, fmt.Sprintf(
+			"%s is %.0f°C, below the %.0f°C %s tolerates",
+			room.Name, temp, plant.Seasonality.MinTempCelsius, plant.Name,
+		)), true
+	}
+	if plant.PreferedTemperature == nil {
+		return types.PlacementCheck{}, false
+	}
+
+	// Within 3°C is ideal, 12°C off is unsuitable
+	diff := math.Abs(temp - *plant.PreferedTemperature)
+	score := linearScore(diff, 3, 12)
+	message := fmt.Sprintf("%s is %.0f°C, close to the %.0f°C %s prefers", room.Name, temp, *plant.PreferedTemperature, plant.Name)
+	if score < 100 {
+		direction := "warmer"
+		if temp < *plant.PreferedTemperature {
+			direction = "colder"
+		}
+		message = fmt.Sprintf("%s is %.0f°C, %.0f°C %s than %s prefers", room.Name, temp, diff, direction, plant.Name)
+	}
+	return newCheck(types.FactorTemperature, score, message), true
+}
+
+func humidityCheck(plant types.Plant, room types.Room) (types.PlacementCheck, bool) {
+	if room.HumidityPct == nil || plant.Humidity == nil || plant.Humidity.TargetHumidityPct <= 0 {
+		return types.PlacementCheck{}, false
+	}
+	humidity := *room.HumidityPct
+	target := plant.Humidity.TargetHumidityPct
+
+	// Plants cope with more humidity than they need; dry air is the danger.
+	// Within 10 points below the target is ideal, 40 points below unsuitable.
+	score := linearScore(target-humidity, 10, 40)
+	message := fmt.Sprintf("%s has %.0f%% humidity, enough for %s", room.Name, humidity, plant.Name)
+	if score < 100 {
+		message = fmt.Sprintf("%s has %.0f%% humidity but %s needs about %.0f%%", room.Name, humidity, plant.Name, target)
+	}
+	return newCheck(types.FactorHumidity, score, message), true
+}
+
+// linearScore is 100 up to ideal, 0 from worst and linear in between
+func linearScore(off, ideal, worst float64) int {
+	switch {
+	case off <= ideal:
+		return 100
+	case off >= worst:
+		return 0
+	default:
+		return int(math.Round(100 * (worst - off) / (worst - ideal)))
+	}
+}
+
+func newCheck(factor types.PlacementFactor, score int, message string) types.PlacementCheck {
+	return types.PlacementCheck{Factor: factor, Score: score, Status: PlacementStatusFor(score), Message: message}
+}
+
+// placementScore is the mean score of checks, or false without checks
+func placementScore(checks []types.PlacementCheck) (int, bool) {
+	if len(checks) == 0 {
+		return 0, false
+	}
+	total := 0
+	for _, check := range checks {
+		total += check.Score
+	}
+	return int(math.Round(float64(total) / float64(len(checks)))), true
+}
+
+// Placement scores plant in its room and suggests better ones from rooms,
+// the rooms of its owner. Plants without a room get the rooms that suit them
+// well as suggestions.
+func Placement(plant types.Plant, rooms []types.Room) types.PlacementReport {
+	report := types.PlacementReport{
+		PlantID:     plant.ID,
+		Name:        plant.Name,
+		Slug:        plant.Slug,
+		Status:      types.PlacementUnknown,
+		Checks:      []types.PlacementCheck{},
+		Suggestions: []types.RoomSuggestion{},
+	}
+	if plant.Location != nil {
+		report.RoomID = plant.Location.RoomID
+		report.Room = plant.Location.Room
+	}
+
+	// The lowest score a suggested room needs
+	threshold := 80
+	for _, room := range rooms {
+		if room.ID != report.RoomID {
+			continue
+		}
+		report.Checks = PlacementChecks(plant, room)
+		if score, ok := placementScore(report.Checks); ok {
+			report.Score = &score
+			report.Status = PlacementStatusFor(score)
+			threshold = score + minSuggestionGain
+		}
+		break
+	}
+	if report.Status == types.PlacementGood {
+		return report
+	}
+
+	for _, room := range rooms {
+		if room.ID == report.RoomID {
+			continue
+		}
+		score, ok := placementScore(PlacementChecks(plant, room))
+		if ok && score >= threshold {
+			report.Suggestions = append(report.Suggestions, types.RoomSuggestion{
+				RoomID: room.ID,
+				Name:   room.Name,
+				Score:  score,
+			})
+		}
+	}
+	sort.SliceStable(report.Suggestions, func(i, j int) bool {
+		return report.Suggestions[i].Score > report.Suggestions[j].Score
+	})
+	if len(report.Suggestions) > maxRoomSuggestions {
+		report.Suggestions = report.Suggestions[:maxRoomSuggestions]
+	}
+	return report
+}
+
+// SortPlacements orders reports worst placement first; plants that could not
+// be scored come last
+func SortPlacements(reports []types.PlacementReport) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		a, b := reports[i].Score, reports[j].Score
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case *a != *b:
+			return *a < *b
+		default:
+			return reports[i].Name < reports[j].Name
+		}
+	})
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
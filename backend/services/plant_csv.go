@@ -0,0 +1,323 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+	"github.com/qreepex/water-me-app/backend/util"
+)
+
+// PlantCSVColumns are the columns of plant CSV exports. Imports accept them
+// in any order; only name is required. Pest and growth history are JSON only.
+var PlantCSVColumns = []string{
+	"name",
+	"species",
+	"speciesId",
+	"isToxic",
+	"toxicityCats",
+	"toxicityDogs",
+	"toxicityHumans",
+	"toxicitySymptoms",
+	"sunlight",
+	"preferedTemperature",
+	"room",
+	"position",
+	"isOutdoors",
+	"wateringIntervalDays",
+	"wateringMethod",
+	"waterType",
+	"lastWatered",
+	"fertilizerType",
+	"fertilizingIntervalDays",
+	"npkRatio",
+	"concentrationPercent",
+	"lastFertilized",
+	"activeInWinter",
+	"requiresMisting",
+	"mistingIntervalDays",
+	"requiresHumidifier",
+	"targetHumidityPct",
+	"soilType",
+	"soilComponents",
+	"repottingCycle",
+	"lastRepotted",
+	"winterRestPeriod",
+	"winterWaterFactor",
+	"minTempCelsius",
+	"flags",
+	"notes",
+}
+
+// csvListSeparator joins list values such as flags within a single cell.
+// Notes are joined by newlines instead, as they may contain semicolons.
+const csvListSeparator = ";"
+
+var (
+	wateringColumns    = []string{"wateringIntervalDays", "wateringMethod", "waterType", "lastWatered"}
+	fertilizingColumns = []string{
+		"fertilizerType", "fertilizingIntervalDays", "npkRatio",
+		"concentrationPercent", "lastFertilized", "activeInWinter",
+	}
+	humidityColumns    = []string{"requiresMisting", "mistingIntervalDays", "requiresHumidifier", "targetHumidityPct"}
+	soilColumns        = []string{"soilType", "soilComponents", "repottingCycle", "lastRepotted"}
+	seasonalityColumns = []string{"winterRestPeriod", "winterWaterFactor", "minTempCelsius"}
+	locationColumns    = []string{"room", "position", "isOutdoors"}
+	toxicityColumns    = []string{"toxicityCats", "toxicityDogs", "toxicityHumans", "toxicitySymptoms"}
+)
+
+// PlantToCreateRequest converts a plant back into the request that creates
+// it, for exports. The household and room ID are left out since they are not
+// portable; the room name links imported plants to rooms.
+func PlantToCreateRequest(plant types.Plant) types.CreatePlantRequest {
+	req := types.CreatePlantRequest{
+		Name:                plant.Name,
+		Species:             plant.Species,
+		SpeciesID:           plant.SpeciesID,
+		IsToxic:             plant.IsToxic,
+		Toxicity:            plant.Toxicity,
+		Sunlight:            plant.Sunlight,
+		PreferedTemperature: plant.PreferedTemperature,
+		Location:            plant.Location,
+		Watering:            plant.Watering,
+		Fertilizing:         plant.Fertilizing,
+		Humidity:            plant.Humidity,
+		Soil:                plant.Soil,
+		Seasonality:         plant.Seasonality,
+		PestHistory:         plant.PestHistory,
+		Flags:               plant.Flags,
+		Notes:               plant.Notes,
+		PhotoIDs:            plant.PhotoIDs,
+		GrowthHistory:       plant.GrowthHistory,
+	}
+	// Plants created without a schedule still get a watering and fertilizing
+	// config holding only the last care date, which would not validate
+	if req.Watering != nil && req.Watering.IntervalDays == 0 {
+		req.Watering = nil
+	}
+	if req.Fertilizing != nil && req.Fertilizing.Type == "" {
+		req.Fertilizing = nil
+	}
+	if req.Location != nil && req.Location.RoomID != "" {
+		location := *req.Location
+		location.RoomID = ""
+		req.Location = &location
+	}
+	return req
+}
+
+// WritePlantsCSV writes one row per plant with the PlantCSVColumns header
+func WritePlantsCSV(w io.Writer, plants []types.Plant) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(PlantCSVColumns); err != nil {
+		return err
+	}
+	for _, plant := range plants {
+		if err := out.Write(plantCSVRecord(PlantToCreateRequest(plant))); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func plantCSVRecord(plant types.CreatePlantRequest) []string {
+	values := map[string]string{
+		"name":      plant.Name,
+		"species":   plant.Species,
+		"speciesId": plant.SpeciesID,
+		"isToxic":   formatCSVBool(plant.IsToxic),
+	}
+	if t := plant.Toxicity; t != nil {
+		values["toxicityCats"] = string(t.Cats)
+		values["toxicityDogs"] = string(t.Dogs)
+		values["toxicityHumans"] = string(t.Humans)
+		values["toxicitySymptoms"] = strings.Join(t.Symptoms, csvListSeparator)
+	}
+	if plant.Sunlight != nil {
+		values["sunlight"] = string(*plant.Sunlight)
+	}
+	if plant.PreferedTemperature != nil {
+		values["preferedTemperature"] = formatCSVFloat(*plant.PreferedTemperature)
+	}
+	if loc := plant.Location; loc != nil {
+		values["room"] = loc.Room
+		values["position"] = loc.Position
+		values["isOutdoors"] = formatCSVBool(loc.IsOutdoors)
+	}
+	if wc := plant.Watering; wc != nil {
+		values["wateringIntervalDays"] = strconv.Itoa(wc.IntervalDays)
+		values["wateringMethod"] = string(wc.Method)
+		values["waterType"] = string(wc.WaterType)
+		values["lastWatered"] = formatCSVTime(wc.LastWatered)
+	}
+	if fc := plant.Fertilizing; fc != nil {
+		values["fertilizerType"] = string(fc.Type)
+		values["fertilizingIntervalDays"] = strconv.Itoa(fc.IntervalDays)
+		values["npkRatio"] = fc.NPKRatio
+		values["concentrationPercent"] = formatCSVFloat(fc.ConcentrationPercent)
+		values["lastFertilized"] = formatCSVTime(fc.LastFertilized)
+		values["activeInWinter"] = formatCSVBool(fc.ActiveInWinter)
+	}
+	if hc := plant.Humidity; hc != nil {
+		values["requiresMisting"] = formatCSVBool(hc.RequiresMisting)
+		values["mistingIntervalDays"] = strconv.Itoa(hc.MistingIntervalDays)
+		values["requiresHumidifier"] = formatCSVBool(hc.RequiresHumidifier)
+		values["targetHumidityPct"] = formatCSVFloat(hc.TargetHumidityPct)
+	}
+	if sc := plant.Soil; sc != nil {
+		values["soilType"] = sc.Type
+		values["soilComponents"] = strings.Join(sc.Components, csvListSeparator)
+		values["repottingCycle"] = strconv.Itoa(sc.RepottingCycle)
+		values["lastRepotted"] = formatCSVTime(sc.LastRepotted)
+	}
+	if sa := plant.Seasonality; sa != nil {
+		values["winterRestPeriod"] = formatCSVBool(sa.WinterRestPeriod)
+		values["winterWaterFactor"] = formatCSVFloat(sa.WinterWaterFactor)
+		values["minTempCelsius"] = formatCSVFloat(sa.MinTempCelsius)
+	}
+	flags := make([]string, len(plant.Flags))
+	for i, flag := range plant.Flags {
+		flags[i] = string(flag)
+	}
+	values["flags"] = strings.Join(flags, csvListSeparator)
+	values["notes"] = strings.Join(plant.Notes, "\n")
+
+	record := make([]string, len(PlantCSVColumns))
+	for i, column := range PlantCSVColumns {
+		record[i] = values[column]
+	}
+	return record
+}
+
+// ReadPlantsCSV parses a plant CSV file. It fails only if the file itself is
+// malformed; cells that cannot be parsed are reported per row in rowErrors,
+// which has one entry per returned request.
+func ReadPlantsCSV(r io.Reader) ([]types.CreatePlantRequest, [][]types.ValidationError, error) {
+	in, err := util.NewCSVReader(r, isPlantCSVColumn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !in.HasColumn("name") {
+		return nil, nil, errors.New("CSV file needs a name column")
+	}
+
+	var (
+		requests  []types.CreatePlantRequest
+		rowErrors [][]types.ValidationError
+	)
+	for {
+		row, err := in.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		requests = append(requests, plantFromCSV(row))
+		rowErrors = append(rowErrors, row.Errors)
+	}
+	return requests, rowErrors, nil
+}
+
+func isPlantCSVColumn(name string) bool {
+	return slices.Contains(PlantCSVColumns, name)
+}
+
+func plantFromCSV(row *util.CSVRow) types.CreatePlantRequest {
+	req := types.CreatePlantRequest{
+		Name:      row.Str("name"),
+		Species:   row.Str("species"),
+		SpeciesID: row.Str("speciesId"),
+		IsToxic:   row.Bool("isToxic"),
+	}
+	if row.Has(toxicityColumns...) {
+		req.Toxicity = &types.Toxicity{
+			Cats:     types.ToxicityLevel(row.Str("toxicityCats")),
+			Dogs:     types.ToxicityLevel(row.Str("toxicityDogs")),
+			Humans:   types.ToxicityLevel(row.Str("toxicityHumans")),
+			Symptoms: row.List("toxicitySymptoms", csvListSeparator),
+		}
+	}
+	if v := row.Str("sunlight"); v != "" {
+		sunlight := types.SunlightRequirement(v)
+		req.Sunlight = &sunlight
+	}
+	if row.Has("preferedTemperature") {
+		temperature := row.Float("preferedTemperature")
+		req.PreferedTemperature = &temperature
+	}
+	if row.Has(locationColumns...) {
+		req.Location = &types.Location{
+			Room:       row.Str("room"),
+			Position:   row.Str("position"),
+			IsOutdoors: row.Bool("isOutdoors"),
+		}
+	}
+	if row.Has(wateringColumns...) {
+		req.Watering = &types.WateringConfig{
+			IntervalDays: row.Int("wateringIntervalDays"),
+			Method:       types.WateringMethod(row.Str("wateringMethod")),
+			WaterType:    types.WaterType(row.Str("waterType")),
+			LastWatered:  row.Time("lastWatered"),
+		}
+	}
+	if row.Has(fertilizingColumns...) {
+		req.Fertilizing = &types.FertilizerConfig{
+			Type:                 types.FertilizerType(row.Str("fertilizerType")),
+			IntervalDays:         row.Int("fertilizingIntervalDays"),
+			NPKRatio:             row.Str("npkRatio"),
+			ConcentrationPercent: row.Float("concentrationPercent"),
+			LastFertilized:       row.Time("lastFertilized"),
+			ActiveInWinter:       row.Bool("activeInWinter"),
+		}
+	}
+	if row.Has(humidityColumns...) {
+		req.Humidity = &types.HumidityConfig{
+			RequiresMisting:     row.Bool("requiresMisting"),
+			MistingIntervalDays: row.Int("mistingIntervalDays"),
+			RequiresHumidifier:  row.Bool("requiresHumidifier"),
+			TargetHumidityPct:   row.Float("targetHumidityPct"),
+		}
+	}
+	if row.Has(soilColumns...) {
+		req.Soil = &types.SoilConfig{
+			Type:           row.Str("soilType"),
+			Components:     row.List("soilComponents", csvListSeparator),
+			RepottingCycle: row.Int("repottingCycle"),
+			LastRepotted:   row.Time("lastRepotted"),
+		}
+	}
+	if row.Has(seasonalityColumns...) {
+		req.Seasonality = &types.SeasonalAdjustments{
+			WinterRestPeriod:  row.Bool("winterRestPeriod"),
+			WinterWaterFactor: row.Float("winterWaterFactor"),
+			MinTempCelsius:    row.Float("minTempCelsius"),
+		}
+	}
+	for _, flag := range row.List("flags", csvListSeparator) {
+		req.Flags = append(req.Flags, types.PlantFlag(flag))
+	}
+	req.Notes = row.List("notes", "\n")
+	return req
+}
+
+func formatCSVBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func formatCSVFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
-	"log"
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/qreepex/water-me-app/backend/logging"
 )
 
 // RateLimiter tracks requests per identifier
@@ -80,7 +82,11 @@ func (rl *RateLimiter) IsRateLimited(userID string, ip string) bool {
 	}
 	limiter.count++
 	if limiter.count > userRequestsPerMinute {
-		log.Printf("Rate limit exceeded for user %s: %d requests", userID, limiter.count)
+		slog.Warn(
+			"rate limit exceeded for user",
+			"userHash", logging.HashUserID(userID),
+			"requests", limiter.count,
+		)
 		return true
 	}
 
@@ -91,7 +97,7 @@ func (rl *RateLimiter) IsRateLimited(userID string, ip string) bool {
 	}
 	limiter.requestsIP++
 	if limiter.requestsIP > ipRequestsPerMinute {
-		log.Printf("Rate limit exceeded for IP %s: %d requests", ip, limiter.requestsIP)
+		slog.Warn("rate limit exceeded for IP", "ip", ip, "requests", limiter.requestsIP)
 		return true
 	}
 
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+var (
+	ErrRoomNameTaken = errors.New("room name already used")
+	ErrUnknownRoom   = errors.New("unknown room")
+	ErrRoomLimit     = errors.New("room limit reached")
+)
+
+// NewRoom builds a room of userID from req
+func NewRoom(userID string, req types.CreateRoomRequest, now time.Time) (types.Room, error) {
+	id, err := gonanoid.New()
+	if err != nil {
+		return types.Room{}, err
+	}
+	name := strings.Join(strings.Fields(req.Name), " ")
+	return types.Room{
+		ID:                 id,
+		UserID:             userID,
+		Name:               name,
+		NameKey:            types.RoomNameKey(name),
+		Light:              req.Light,
+		HumidityPct:        req.HumidityPct,
+		TemperatureCelsius: req.TemperatureCelsius,
+		IsOutdoors:         req.IsOutdoors,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}, nil
+}
+
+// ApplyRoomUpdate applies a PATCH request to room
+func ApplyRoomUpdate(room *types.Room, req types.UpdateRoomRequest, now time.Time) {
+	if req.Name != nil {
+		room.Name = strings.Join(strings.Fields(*req.Name), " ")
+		room.NameKey = types.RoomNameKey(room.Name)
+	}
+	if req.Light != nil {
+		room.Light = req.Light
+	}
+	if req.HumidityPct != nil {
+		room.HumidityPct = req.HumidityPct
+	}
+	if req.TemperatureCelsius != nil {
+		room.TemperatureCelsius = req.TemperatureCelsius
+	}
+	if req.IsOutdoors != nil {
+		room.IsOutdoors = *req.IsOutdoors
+	}
+	room.UpdatedAt = now
+}
+
+// PlaceInRoom links loc to room and copies the room's name and outdoor flag
+func PlaceInRoom(loc *types.Location, room types.Room) {
+	loc.RoomID = room.ID
+	loc.Room = room.Name
+	loc.IsOutdoors = room.IsOutdoors
+}
+
+// ResolveRoom links loc to a room of userID. A location with a RoomID must
+// reference one of the user's rooms (ErrUnknownRoom otherwise). A location
+// with only a room name is linked to the room of that name; if there is none
+// and create is set, the room is created, up to maxRooms (ErrRoomLimit).
+func ResolveRoom(
+	ctx context.Context,
+	rooms RoomStore,
+	userID string,
+	loc *types.Location,
+	create bool,
+	maxRooms int,
+) error {
+	if loc == nil {
+		return nil
+	}
+	if loc.RoomID != "" {
+		room, err := rooms.GetRoom(ctx, userID, loc.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil {
+			return ErrUnknownRoom
+		}
+		PlaceInRoom(loc, *room)
+		return nil
+	}
+	if strings.TrimSpace(loc.Room) == "" {
+		loc.Room = ""
+		return nil
+	}
+
+	room, err := rooms.GetRoomByName(ctx, userID, loc.Room)
+	if err != nil {
+		return err
+	}
+	if room == nil {
+		if !create {
+			return nil
+		}
+		existing, err := rooms.GetRooms(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(existing) >= maxRooms {
+			return ErrRoomLimit
+		}
+		created, err := NewRoom(userID, types.CreateRoomRequest{Name: loc.Room, IsOutdoors: loc.IsOutdoors}, time.Now())
+		if err != nil {
+			return err
+		}
+		room, err = rooms.CreateRoom(ctx, created)
+		if errors.Is(err, ErrRoomNameTaken) {
+			// Created concurrently under the same name
+			room, err = rooms.GetRoomByName(ctx, userID, loc.Room)
+			if err == nil && room == nil {
+				err = ErrRoomNameTaken
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	PlaceInRoom(loc, *room)
+	return nil
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/qreepex/water-me-app/backend/constants"
+	"github.com/qreepex/water-me-app/backend/tracing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -25,6 +28,8 @@ type S3Service struct {
 	Bucket    string
 	URLExpire time.Duration
 	PublicURL string // optional CDN/base URL for viewing
+
+	httpClient *http.Client
 }
 
 func NewS3Service(ctx context.Context) (*S3Service, error) {
@@ -39,15 +44,21 @@ func NewS3Service(ctx context.Context) (*S3Service, error) {
 		"",
 	)
 
+	// Own the HTTP client so idle connections can be released on shutdown
+	httpClient := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+
 	// Load AWS config with explicit credentials and no EC2 IMDS fallback
 	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithRegion(region),
 		config.WithCredentialsProvider(creds),
+		config.WithHTTPClient(httpClient),
 		config.WithClientLogMode(aws.LogRequestWithBody|aws.LogResponseWithBody),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("load aws config: %w", err)
 	}
+	// Trace every S3 call, presigning included
+	cfg.APIOptions = append(cfg.APIOptions, tracing.AWSMiddleware)
 
 	// Create S3 client with custom endpoint
 	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
@@ -61,9 +72,17 @@ func NewS3Service(ctx context.Context) (*S3Service, error) {
 		Bucket:    bucket,
 		URLExpire: 1 * time.Hour,
 		// PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		httpClient: httpClient,
 	}, nil
 }
 
+// Close releases idle connections held by the S3 client.
+func (s *S3Service) Close() {
+	if s.httpClient != nil {
+		s.httpClient.CloseIdleConnections()
+	}
+}
+
 // SetupCORS configures CORS rules for the bucket to allow browser uploads.
 // Call this once during setup or when CORS rules need to be updated.
 func (s *S3Service) SetupCORS(ctx context.Context, allowedOrigins []string) error {
@@ -132,15 +151,19 @@ func (s *S3Service) SetupBucketPolicy(ctx context.Context) error {
 }
 
 // GenerateObjectKey builds a unique, user-scoped object key.
-func (s *S3Service) GenerateObjectKey(userID, filename string) string {
+func GenerateObjectKey(userID, filename string) string {
 	id := uuid.New().String()
 	return fmt.Sprintf("users/%s/%s_%s", userID, id, sanitizeFilename(filename))
 }
 
+// UserPrefix returns the key prefix of all objects owned by a user.
+func UserPrefix(userID string) string {
+	return fmt.Sprintf("users/%s/", userID)
+}
+
 // KeyBelongsToUser checks if an object key is scoped under the user's prefix.
 func KeyBelongsToUser(key, userID string) bool {
-	prefix := fmt.Sprintf("users/%s/", userID)
-	return strings.HasPrefix(key, prefix)
+	return strings.HasPrefix(key, UserPrefix(userID))
 }
 
 // PresignPutURL generates a pre-signed PUT URL for direct upload.
@@ -191,6 +214,35 @@ func (s *S3Service) HeadObjectInfo(ctx context.Context, key string) (int64, stri
 	return size, ctype, nil
 }
 
+// GetObject opens an object for reading. The caller must close it.
+func (s *S3Service) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{Bucket: &s.Bucket, Key: &key})
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+	return out.Body, nil
+}
+
+// PutObject uploads a server-generated object such as a data export.
+func (s *S3Service) PutObject(
+	ctx context.Context,
+	key, contentType string,
+	body io.Reader,
+	size int64,
+) error {
+	_, err := s.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        &s.Bucket,
+		Key:           &key,
+		Body:          body,
+		ContentType:   &contentType,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	return nil
+}
+
 // DeleteObject removes an object from S3. Returns error if object doesn't exist or deletion fails.
 func (s *S3Service) DeleteObject(ctx context.Context, key string) error {
 	_, err := s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
@@ -228,7 +280,7 @@ func (s *S3Service) GetUserUsage(
 	ctx context.Context,
 	userID string,
 ) (total int64, count int, err error) {
-	prefix := fmt.Sprintf("users/%s/", userID)
+	prefix := UserPrefix(userID)
 	var token *string
 	for {
 		out, err := s.Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
@@ -252,6 +304,50 @@ func (s *S3Service) GetUserUsage(
 	return total, count, nil
 }
 
+// DeletePrefix removes all objects under prefix, one listed page (up to 1000
+// keys) at a time. Objects S3 fails to delete are reported as an error after
+// the page, so a retry picks them up again.
+func (s *S3Service) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	deleted := 0
+	var token *string
+	for {
+		out, err := s.Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            &s.Bucket,
+			Prefix:            &prefix,
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("list objects: %w", err)
+		}
+
+		if len(out.Contents) > 0 {
+			objects := make([]types.ObjectIdentifier, len(out.Contents))
+			for i, obj := range out.Contents {
+				objects[i] = types.ObjectIdentifier{Key: obj.Key}
+			}
+			result, err := s.Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: &s.Bucket,
+				Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				return deleted, fmt.Errorf("delete objects: %w", err)
+			}
+			deleted += len(objects) - len(result.Errors)
+			if len(result.Errors) > 0 {
+				return deleted, fmt.Errorf("delete objects: %d of %d failed, first: %s",
+					len(result.Errors), len(objects), aws.ToString(result.Errors[0].Message))
+			}
+		}
+
+		if aws.ToBool(out.IsTruncated) && out.NextContinuationToken != nil {
+			token = out.NextContinuationToken
+		} else {
+			break
+		}
+	}
+	return deleted, nil
+}
+
 func sanitizeFilename(name string) string {
 	// Basic sanitization; could expand if needed
 	return url.PathEscape(name)
@@ -64,7 +64,7 @@ func TestBucketSizeLimit(t *testing.T) {
 	contentType := "image/jpeg"
 	filename := "test-large-file.jpg"
 
-	key := s3Service.GenerateObjectKey(userID, filename)
+	key := GenerateObjectKey(userID, filename)
 	presignURL, headers, err := s3Service.PresignPutURL(ctx, key, contentType, userID)
 	if err != nil {
 		t.Fatalf("Failed to generate presign URL: %v", err)
@@ -146,7 +146,7 @@ func TestValidUploadSize(t *testing.T) {
 	contentType := "image/jpeg"
 	filename := "test-valid-file.jpg"
 
-	key := s3Service.GenerateObjectKey(userID, filename)
+	key := GenerateObjectKey(userID, filename)
 	presignURL, headers, err := s3Service.PresignPutURL(ctx, key, contentType, userID)
 	if err != nil {
 		t.Fatalf("Failed to generate presign URL: %v", err)
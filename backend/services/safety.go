@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// SpeciesToxicity returns the toxicity of species for a plant linked to it,
+// or nil if the catalog has no toxicity data for the species
+func SpeciesToxicity(species types.Species) *types.Toxicity {
+	t := species.Toxicity
+	if t.Cats == "" && t.Dogs == "" && t.Humans == "" {
+		return nil
+	}
+	t.Symptoms = append([]string(nil), t.Symptoms...)
+	return &t
+}
+
+// PlantRisks returns the dangers plant poses to the residents of profile that
+// can reach it, most severe first. Plants marked toxic without details are
+// reported with ToxicityUnknown for every resident.
+func PlantRisks(plant types.Plant, profile *types.SafetyProfile) []types.PlantRisk {
+	if profile == nil || (!plant.IsToxic && plant.Toxicity == nil) {
+		return nil
+	}
+	roomID := ""
+	if plant.Location != nil {
+		roomID = plant.Location.RoomID
+	}
+
+	byKind := make(map[types.ResidentKind]*types.PlantRisk)
+	var risks []*types.PlantRisk
+	for _, resident := range profile.Residents {
+		severity := types.ToxicityUnknown
+		if plant.Toxicity != nil {
+			severity = plant.Toxicity.For(resident.Kind)
+		}
+		if severity.Rank() == 0 || !resident.CanReach(roomID) {
+			continue
+		}
+		risk, ok := byKind[resident.Kind]
+		if !ok {
+			risk = &types.PlantRisk{Resident: resident.Kind, Severity: severity}
+			byKind[resident.Kind] = risk
+			risks = append(risks, risk)
+		}
+		if name := strings.TrimSpace(resident.Name); name != "" {
+			risk.Names = append(risk.Names, name)
+		}
+	}
+
+	result := make([]types.PlantRisk, 0, len(risks))
+	for _, risk := range risks {
+		risk.Message = riskMessage(plant.Name, *risk)
+		result = append(result, *risk)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Severity.Rank() > result[j].Severity.Rank()
+	})
+	return result
+}
+
+func riskMessage(plantName string, risk types.PlantRisk) string {
+	who := map[types.ResidentKind]string{
+		types.ResidentCat:   "cats",
+		types.ResidentDog:   "dogs",
+		types.ResidentChild: "children",
+	}[risk.Resident]
+	if len(risk.Names) > 0 {
+		who = strings.Join(risk.Names, ", ")
+	}
+	if risk.Severity == types.ToxicityUnknown {
+		return fmt.Sprintf("%s is marked toxic and within reach of %s", plantName, who)
+	}
+	return fmt.Sprintf("%s is %s toxic to %s and within reach", plantName, risk.Severity, who)
+}
+
+// SafetyChecker finds the safety profile that applies to a plant: the
+// profile of its household if that has one, otherwise the owner's own
+type SafetyChecker struct {
+	households HouseholdStore
+	profiles   SafetyProfileStore
+}
+
+// NewSafetyChecker creates a SafetyChecker
+func NewSafetyChecker(households HouseholdStore, profiles SafetyProfileStore) *SafetyChecker {
+	return &SafetyChecker{households: households, profiles: profiles}
+}
+
+// profileCache holds the profiles looked up while checking several plants
+type profileCache struct {
+	byUser      map[string]*types.SafetyProfile
+	byHousehold map[string]*types.SafetyProfile
+}
+
+func newProfileCache() *profileCache {
+	return &profileCache{
+		byUser:      make(map[string]*types.SafetyProfile),
+		byHousehold: make(map[string]*types.SafetyProfile),
+	}
+}
+
+// Profile returns the profile for plant, or nil if none applies
+func (c *SafetyChecker) Profile(ctx context.Context, plant types.Plant) (*types.SafetyProfile, error) {
+	return c.profile(ctx, plant, newProfileCache())
+}
+
+func (c *SafetyChecker) profile(
+	ctx context.Context,
+	plant types.Plant,
+	cache *profileCache,
+) (*types.SafetyProfile, error) {
+	if plant.HouseholdID != "" {
+		profile, ok := cache.byHousehold[plant.HouseholdID]
+		if !ok {
+			household, err := c.households.GetHousehold(ctx, plant.HouseholdID)
+			if err != nil {
+				return nil, err
+			}
+			if household != nil {
+				profile = household.Profile
+			}
+			cache.byHousehold[plant.HouseholdID] = profile
+		}
+		if profile != nil {
+			return profile, nil
+		}
+	}
+
+	profile, ok := cache.byUser[plant.UserID]
+	if !ok {
+		var err error
+		profile, err = c.profiles.GetSafetyProfile(ctx, plant.UserID)
+		if err != nil {
+			return nil, err
+		}
+		cache.byUser[plant.UserID] = profile
+	}
+	return profile, nil
+}
+
+// Risks returns PlantRisks for plant under the profile that applies to it
+func (c *SafetyChecker) Risks(ctx context.Context, plant types.Plant) ([]types.PlantRisk, error) {
+	profile, err := c.Profile(ctx, plant)
+	if err != nil {
+		return nil, err
+	}
+	return PlantRisks(plant, profile), nil
+}
+
+// RiskyPlants returns the plants that are a danger to residents who can
+// reach them, most severe first
+func (c *SafetyChecker) RiskyPlants(ctx context.Context, plants []types.Plant) ([]types.RiskyPlant, error) {
+	cache := newProfileCache()
+	risky := make([]types.RiskyPlant, 0)
+	for _, plant := range plants {
+		profile, err := c.profile(ctx, plant, cache)
+		if err != nil {
+			return nil, err
+		}
+		risks := PlantRisks(plant, profile)
+		if len(risks) == 0 {
+			continue
+		}
+		entry := types.RiskyPlant{
+			PlantID:     plant.ID,
+			Name:        plant.Name,
+			Slug:        plant.Slug,
+			HouseholdID: plant.HouseholdID,
+			Severity:    risks[0].Severity,
+			Risks:       risks,
+			Symptoms:    []string{},
+		}
+		if plant.Location != nil {
+			entry.Room = plant.Location.Room
+		}
+		if plant.Toxicity != nil && plant.Toxicity.Symptoms != nil {
+			entry.Symptoms = plant.Toxicity.Symptoms
+		}
+		risky = append(risky, entry)
+	}
+	sort.SliceStable(risky, func(i, j int) bool {
+		if a, b := risky[i].Severity.Rank(), risky[j].Severity.Rank(); a != b {
+			return a > b
+		}
+		return risky[i].Name < risky[j].Name
+	})
+	return risky, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// PublicSharePath is the prefix of public plant pages, served without authentication
+const PublicSharePath = "/public/plants/"
+
+// shareTokenBytes makes share tokens unguessable; they are the only secret
+// protecting a shared plant
+const shareTokenBytes = 32
+
+// GenerateShareToken returns a random, URL-safe plant share token
+func GenerateShareToken() (string, error) {
+	secret := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// PublicPlantView returns the fields of plant that share exposes. Photo URLs
+// are left to the caller.
+func PublicPlantView(plant types.Plant, share types.PlantShare) types.PublicPlant {
+	view := types.PublicPlant{
+		Name:                plant.Name,
+		Species:             plant.Species,
+		IsToxic:             plant.IsToxic,
+		Toxicity:            plant.Toxicity,
+		Sunlight:            plant.Sunlight,
+		PreferedTemperature: plant.PreferedTemperature,
+		Watering:            plant.Watering,
+		Fertilizing:         plant.Fertilizing,
+		Humidity:            plant.Humidity,
+		Soil:                plant.Soil,
+		Seasonality:         plant.Seasonality,
+		Flags:               plant.Flags,
+		PhotoURLs:           []string{},
+	}
+	if view.Flags == nil {
+		view.Flags = []types.PlantFlag{}
+	}
+	if share.ShowLocation {
+		view.Location = plant.Location
+	}
+	if share.ShowNotes {
+		view.Notes = plant.Notes
+	}
+	if share.ShowPestHistory {
+		view.PestHistory = plant.PestHistory
+	}
+	return view
+}
+
+// SharedPhotoIDs returns the photos selected for share that still belong to plant
+func SharedPhotoIDs(plant types.Plant, share types.PlantShare) []string {
+	onPlant := make(map[string]bool, len(plant.PhotoIDs))
+	for _, id := range plant.PhotoIDs {
+		onPlant[id] = true
+	}
+	ids := make([]string, 0, len(share.PhotoIDs))
+	for _, id := range share.PhotoIDs {
+		if onPlant[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// MaxSpeciesResults bounds species search results
+const MaxSpeciesResults = 25
+
+// SpeciesSearchPattern returns a case-insensitive regular expression matching
+// names with a word that starts with query, for typeahead. The syntax is
+// shared by Go and MongoDB.
+func SpeciesSearchPattern(query string) string {
+	return `(?i)(^|[\s-])` + regexp.QuoteMeta(strings.TrimSpace(query))
+}
+
+// ApplySpeciesDefaults fills the care sections of req that were left empty
+// with the defaults of species. Sections that are set are kept as they are.
+func ApplySpeciesDefaults(req *types.CreatePlantRequest, species types.Species) {
+	if strings.TrimSpace(req.Species) == "" {
+		req.Species = species.ScientificName
+	}
+	if req.Sunlight == nil && species.Sunlight != "" {
+		sunlight := species.Sunlight
+		req.Sunlight = &sunlight
+	}
+	if req.Watering == nil && species.WateringIntervalDays > 0 {
+		req.Watering = &types.WateringConfig{
+			IntervalDays: species.WateringIntervalDays,
+			Method:       types.MethodTopWatering,
+			WaterType:    types.WaterTap,
+		}
+	}
+	if req.Fertilizing == nil && species.FertilizingIntervalDays > 0 {
+		req.Fertilizing = &types.FertilizerConfig{
+			Type:           types.FertilizerLiquid,
+			IntervalDays:   species.FertilizingIntervalDays,
+			NPKRatio:       species.NPKRatio,
+			ActiveInWinter: species.Winter.Fertilize,
+		}
+	}
+	if req.Humidity == nil {
+		req.Humidity = &types.HumidityConfig{
+			RequiresMisting:     species.MistingIntervalDays > 0,
+			MistingIntervalDays: species.MistingIntervalDays,
+			TargetHumidityPct:   species.TargetHumidityPct,
+		}
+	}
+	if req.Seasonality == nil && species.Winter.WaterFactor > 0 {
+		req.Seasonality = &types.SeasonalAdjustments{
+			WinterRestPeriod:  species.Winter.RestPeriod,
+			WinterWaterFactor: species.Winter.WaterFactor,
+			MinTempCelsius:    species.Winter.MinTempCelsius,
+		}
+	}
+}
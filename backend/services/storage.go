@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Object storage backends selectable with STORAGE_BACKEND
+const (
+	StorageBackendS3    = "s3"
+	StorageBackendLocal = "local"
+)
+
+// NewObjectStoreFromEnv creates the object storage backend selected by
+// STORAGE_BACKEND: "s3" (default) or "local". The local backend is configured
+// with LOCAL_STORAGE_DIR, STORAGE_PUBLIC_URL and STORAGE_SIGNING_KEY.
+func NewObjectStoreFromEnv(ctx context.Context) (ObjectStore, error) {
+	switch backend := getenv("STORAGE_BACKEND", StorageBackendS3); backend {
+	case StorageBackendS3:
+		s3, err := NewS3Service(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	case StorageBackendLocal:
+		secret := []byte(os.Getenv("STORAGE_SIGNING_KEY"))
+		if len(secret) == 0 {
+			// Fine for a single local process; signed URLs break on restart
+			// and are not shared between replicas
+			slog.Warn("STORAGE_SIGNING_KEY not set, using a random key")
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return nil, fmt.Errorf("generate signing key: %w", err)
+			}
+		}
+		local, err := NewLocalStorage(
+			getenv("LOCAL_STORAGE_DIR", "./data/uploads"),
+			getenv("STORAGE_PUBLIC_URL", "http://localhost:8080"),
+			secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
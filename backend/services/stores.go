@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// PlantStore persists plants. All lookups are scoped to the owning user.
+type PlantStore interface {
+	GetPlants(ctx context.Context, userID string) ([]types.Plant, error)
+	// GetPlant and GetPlantBySlug return nil without error when nothing matches
+	GetPlant(ctx context.Context, userID string, id string) (*types.Plant, error)
+	GetPlantBySlug(ctx context.Context, userID string, slug string) (*types.Plant, error)
+	CreatePlant(ctx context.Context, plant types.Plant) (*types.Plant, error)
+	UpdatePlant(
+		ctx context.Context,
+		id string,
+		userID string,
+		update types.UpdatePlantRequest,
+	) (*types.Plant, bool, error)
+	DeletePlant(ctx context.Context, id string, userID string) (bool, error)
+	WaterPlants(ctx context.Context, userID string, plantIDs []string) (int64, error)
+	// SnoozeWatering holds back watering reminders for a plant of userID with
+	// a watering interval until the given time
+	SnoozeWatering(ctx context.Context, id, userID string, until time.Time) (bool, error)
+	// SetWateringInterval changes the watering interval of a plant of userID
+	// that has one
+	SetWateringInterval(ctx context.Context, id, userID string, days int) (bool, error)
+	// GetPlantByID is not scoped to a user; callers must check access (see PlantAccess)
+	GetPlantByID(ctx context.Context, id string) (*types.Plant, error)
+	GetHouseholdPlants(ctx context.Context, householdIDs []string) ([]types.Plant, error)
+	// SetPlantHousehold moves a plant of userID into a household; an empty
+	// householdID removes it from its household
+	SetPlantHousehold(ctx context.Context, id, userID, householdID string) (bool, error)
+	// ClearPlantHousehold removes the plants of userID from a household, or all
+	// of its plants when userID is empty
+	ClearPlantHousehold(ctx context.Context, householdID, userID string) (int64, error)
+	// MovePlantsToRoom puts the plants of userID with the given IDs into
+	// room, keeping their position
+	MovePlantsToRoom(ctx context.Context, userID string, plantIDs []string, room types.Room) (int64, error)
+	// SyncRoomPlants copies the name and outdoor flag of room to its plants
+	SyncRoomPlants(ctx context.Context, room types.Room) (int64, error)
+	// ClearPlantRoom removes a room from the plants of userID
+	ClearPlantRoom(ctx context.Context, userID, roomID string) (int64, error)
+	CountActiveUsers(ctx context.Context) (int64, error)
+	CountPlants(ctx context.Context) (int64, error)
+}
+
+// NotificationStore persists per-user notification configs. GetNotificationConfig
+// returns types.ErrNoDocuments when the user has none.
+type NotificationStore interface {
+	GetNotificationConfig(ctx context.Context, userID string) (*types.NotificationConfig, error)
+	CreateNotificationConfig(
+		ctx context.Context,
+		config types.NotificationConfig,
+	) (*types.NotificationConfig, error)
+	UpdateNotificationConfig(
+		ctx context.Context,
+		config types.NotificationConfig,
+	) (*types.NotificationConfig, error)
+	DeleteNotificationConfig(ctx context.Context, userID string) (bool, error)
+}
+
+// UploadStore tracks uploaded object keys so unused ones can be cleaned up
+type UploadStore interface {
+	GetUserUploadCount(ctx context.Context, userID string) (int64, error)
+	RegisterUpload(ctx context.Context, userID string, key string, size int64) error
+	GetUploadsCreatedBefore(ctx context.Context, cutoff time.Time) ([]types.Upload, error)
+	DeleteUploadRecords(ctx context.Context, keys []string) (int64, error)
+	// IsUploadReferenced reports whether any plant uses the key as a photo
+	IsUploadReferenced(ctx context.Context, key string) (bool, error)
+	GetStorageUsageByUser(ctx context.Context) (map[string]int64, error)
+}
+
+// UserStore persists accounts of the local email/password auth mode. Lookups
+// return nil without error when nothing matches.
+type UserStore interface {
+	// CreateUser returns ErrEmailTaken if the email is already registered
+	CreateUser(ctx context.Context, user types.User) (*types.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*types.User, error)
+	GetUserByID(ctx context.Context, id string) (*types.User, error)
+	UpdateUserPassword(ctx context.Context, userID, hash string, changedAt time.Time) error
+}
+
+// APITokenStore persists personal API tokens
+type APITokenStore interface {
+	CreateAPIToken(ctx context.Context, token types.APIToken) (*types.APIToken, error)
+	GetAPITokens(ctx context.Context, userID string) ([]types.APIToken, error)
+	// GetAPITokenByHash returns nil without error when nothing matches
+	GetAPITokenByHash(ctx context.Context, hash string) (*types.APIToken, error)
+	DeleteAPIToken(ctx context.Context, userID string, id string) (bool, error)
+	TouchAPIToken(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// HouseholdStore persists households and their invites. Lookups return nil
+// without error when nothing matches.
+type HouseholdStore interface {
+	CreateHousehold(ctx context.Context, household types.Household) (*types.Household, error)
+	GetHousehold(ctx context.Context, id string) (*types.Household, error)
+	GetHouseholdsForUser(ctx context.Context, userID string) ([]types.Household, error)
+	// DeleteHousehold also deletes the household's invites
+	DeleteHousehold(ctx context.Context, id string) (bool, error)
+	// AddHouseholdMember returns false if the user is already a member
+	AddHouseholdMember(ctx context.Context, householdID string, member types.HouseholdMember) (bool, error)
+	UpdateHouseholdMemberRole(
+		ctx context.Context,
+		householdID, userID string,
+		role types.HouseholdRole,
+	) (bool, error)
+	RemoveHouseholdMember(ctx context.Context, householdID, userID string) (bool, error)
+	SetHouseholdProfile(ctx context.Context, householdID string, profile types.SafetyProfile) (bool, error)
+
+	CreateHouseholdInvite(ctx context.Context, invite types.HouseholdInvite) (*types.HouseholdInvite, error)
+	GetHouseholdInvites(ctx context.Context, householdID string) ([]types.HouseholdInvite, error)
+	GetHouseholdInviteByCode(ctx context.Context, code string) (*types.HouseholdInvite, error)
+	DeleteHouseholdInvite(ctx context.Context, householdID, id string) (bool, error)
+}
+
+// CareEventStore logs care actions
+type CareEventStore interface {
+	LogCareEvents(ctx context.Context, events []types.CareEvent) error
+	// GetCareEvents returns the care events on ownerID's plants in [from, to), oldest first
+	GetCareEvents(ctx context.Context, ownerID string, from, to time.Time) ([]types.CareEvent, error)
+}
+
+// VacationStore persists vacations. Lookups return nil without error when
+// nothing matches.
+type VacationStore interface {
+	CreateVacation(ctx context.Context, vacation types.Vacation) (*types.Vacation, error)
+	// GetVacations returns userID's vacations, newest first
+	GetVacations(ctx context.Context, userID string) ([]types.Vacation, error)
+	GetVacation(ctx context.Context, userID, id string) (*types.Vacation, error)
+	GetVacationByCode(ctx context.Context, code string) (*types.Vacation, error)
+	// SetVacationCaretaker returns false if the vacation already has a caretaker
+	SetVacationCaretaker(ctx context.Context, id, caretakerID, caretakerName string) (bool, error)
+	EndVacation(ctx context.Context, userID, id string, endsAt time.Time) (bool, error)
+	DeleteVacation(ctx context.Context, userID, id string) (bool, error)
+	// GetCaretakerVacations returns the vacations caretakerID is caretaker for at now
+	GetCaretakerVacations(ctx context.Context, caretakerID string, now time.Time) ([]types.Vacation, error)
+}
+
+// PlantShareStore persists public plant share links. Lookups return nil
+// without error when nothing matches.
+type PlantShareStore interface {
+	CreatePlantShare(ctx context.Context, share types.PlantShare) (*types.PlantShare, error)
+	GetPlantShares(ctx context.Context, plantID string) ([]types.PlantShare, error)
+	GetPlantShareByToken(ctx context.Context, token string) (*types.PlantShare, error)
+	DeletePlantShare(ctx context.Context, plantID, id string) (bool, error)
+}
+
+// ExportStore persists data export jobs. Lookups return nil without error
+// when nothing matches.
+type ExportStore interface {
+	// CreateExport returns ErrExportInProgress if the user already has an
+	// active export
+	CreateExport(ctx context.Context, export types.DataExport) (*types.DataExport, error)
+	// GetExports returns userID's exports, newest first
+	GetExports(ctx context.Context, userID string) ([]types.DataExport, error)
+	GetExport(ctx context.Context, userID, id string) (*types.DataExport, error)
+	UpdateExport(ctx context.Context, export types.DataExport) error
+	// GetExpiredExports returns completed exports whose archive expired before now
+	GetExpiredExports(ctx context.Context, now time.Time) ([]types.DataExport, error)
+	DeleteExport(ctx context.Context, id string) error
+}
+
+// SpeciesStore persists the species catalog
+type SpeciesStore interface {
+	// SearchSpecies returns up to limit species with a scientific or common
+	// name containing a word that starts with query, ordered by scientific name
+	SearchSpecies(ctx context.Context, query string, limit int) ([]types.Species, error)
+	// GetSpecies returns nil without error when nothing matches
+	GetSpecies(ctx context.Context, id string) (*types.Species, error)
+	// UpsertSpecies inserts the entry or replaces the one with the same ID
+	UpsertSpecies(ctx context.Context, species types.Species) error
+}
+
+// RoomStore persists the rooms of users. Lookups return nil without error
+// when nothing matches.
+type RoomStore interface {
+	// CreateRoom returns ErrRoomNameTaken if the user has a room of that name
+	CreateRoom(ctx context.Context, room types.Room) (*types.Room, error)
+	// GetRooms returns userID's rooms ordered by name
+	GetRooms(ctx context.Context, userID string) ([]types.Room, error)
+	GetRoom(ctx context.Context, userID, id string) (*types.Room, error)
+	// GetRoomByName matches names the way types.RoomNameKey normalizes them
+	GetRoomByName(ctx context.Context, userID, name string) (*types.Room, error)
+	// UpdateRoom replaces the room; it returns ErrRoomNameTaken like CreateRoom
+	UpdateRoom(ctx context.Context, room types.Room) (bool, error)
+	DeleteRoom(ctx context.Context, userID, id string) (bool, error)
+}
+
+// PestOutbreakStore persists pest outbreaks
+type PestOutbreakStore interface {
+	CreateOutbreak(ctx context.Context, outbreak types.PestOutbreak) (*types.PestOutbreak, error)
+	// GetOutbreaks returns userID's outbreaks, newest first
+	GetOutbreaks(ctx context.Context, userID string) ([]types.PestOutbreak, error)
+	// AddOutbreakPlant adds plant to the infected plants of an open outbreak
+	// in room and returns the outbreak after the change, or nil if it is no
+	// longer open
+	AddOutbreakPlant(
+		ctx context.Context,
+		id string,
+		plant types.OutbreakPlant,
+		room string,
+		now time.Time,
+	) (*types.PestOutbreak, error)
+	// SetOutbreakAtRisk saves the plants at risk of an open outbreak and the
+	// quarantine advice for it. It returns false if the outbreak is no longer
+	// open or no longer has infected plants, as plants joined it.
+	SetOutbreakAtRisk(
+		ctx context.Context,
+		id string,
+		infected int,
+		atRisk []types.OutbreakPlant,
+		quarantine string,
+		now time.Time,
+	) (bool, error)
+	// ContainOutbreak marks an open outbreak with infected plants contained.
+	// It returns false if it is no longer open or plants joined it.
+	ContainOutbreak(ctx context.Context, id string, infected int, now time.Time) (bool, error)
+	// GetOutbreaksNeedingInspection returns open outbreaks whose next
+	// inspection is due at now
+	GetOutbreaksNeedingInspection(ctx context.Context, now time.Time, limit int) ([]types.PestOutbreak, error)
+	// MarkOutbreakInspected records a sent inspection reminder and schedules
+	// the next one (none if next is nil). It returns false if the outbreak
+	// is no longer open.
+	MarkOutbreakInspected(ctx context.Context, id string, sent int, next *time.Time, now time.Time) (bool, error)
+}
+
+// TreatmentPlanStore persists pest treatment plans
+type TreatmentPlanStore interface {
+	CreateTreatmentPlan(ctx context.Context, plan types.TreatmentPlan) (*types.TreatmentPlan, error)
+	GetTreatmentPlan(ctx context.Context, id string) (*types.TreatmentPlan, error)
+	// GetTreatmentPlans returns the plans of a plant, newest first
+	GetTreatmentPlans(ctx context.Context, plantID string) ([]types.TreatmentPlan, error)
+	// LogTreatmentApplication adds the last application of plan and saves
+	// the schedule ApplyTreatment set. It returns false if the plan is no
+	// longer active or got another application since it was read.
+	LogTreatmentApplication(ctx context.Context, plan types.TreatmentPlan) (bool, error)
+	// RescheduleTreatmentReminder moves the next reminder of an active plan
+	// from due to next. It returns false if the plan ended or its reminder
+	// moved since it was read.
+	RescheduleTreatmentReminder(ctx context.Context, id string, due, next time.Time, now time.Time) (bool, error)
+	// StopTreatmentPlan stops the plan if it is still active
+	StopTreatmentPlan(ctx context.Context, id string, now time.Time) (bool, error)
+	// GetTreatmentPlansNeedingReminder returns active plans whose next
+	// reminder is due at now
+	GetTreatmentPlansNeedingReminder(ctx context.Context, now time.Time, limit int) ([]types.TreatmentPlan, error)
+}
+
+// SafetyProfileStore persists the safety profiles of users. GetSafetyProfile
+// returns nil without error when the user has none.
+type SafetyProfileStore interface {
+	GetSafetyProfile(ctx context.Context, userID string) (*types.SafetyProfile, error)
+	// PutSafetyProfile creates or replaces the profile of profile.UserID
+	PutSafetyProfile(ctx context.Context, profile types.SafetyProfile) error
+}
+
+// AccountStore erases all data of a user across collections and keeps the
+// audit trail of erasures. The Delete methods are idempotent, so an erasure
+// that failed part-way can simply be repeated.
+type AccountStore interface {
+	DeleteUserPlants(ctx context.Context, userID string) (int64, error)
+	DeleteUserUploads(ctx context.Context, userID string) (int64, error)
+	DeleteUserAPITokens(ctx context.Context, userID string) (int64, error)
+	// DeleteUserVacations deletes userID's vacations and removes them as
+	// caretaker from the vacations of others
+	DeleteUserVacations(ctx context.Context, userID string) (int64, error)
+	// DeleteUserCareEvents deletes the events on userID's plants and
+	// anonymizes the events they logged on plants of others
+	DeleteUserCareEvents(ctx context.Context, userID string) (int64, error)
+	DeleteUserPlantShares(ctx context.Context, userID string) (int64, error)
+	DeleteUserExports(ctx context.Context, userID string) (int64, error)
+	DeleteUserSafetyProfile(ctx context.Context, userID string) (int64, error)
+	DeleteUserRooms(ctx context.Context, userID string) (int64, error)
+	DeleteUserOutbreaks(ctx context.Context, userID string) (int64, error)
+	DeleteUserTreatmentPlans(ctx context.Context, userID string) (int64, error)
+	DeleteUser(ctx context.Context, userID string) (bool, error)
+
+	// StartAccountErasure records an erasure attempt; the first one sets StartedAt
+	StartAccountErasure(ctx context.Context, userHash string, at time.Time) error
+	CompleteAccountErasure(ctx context.Context, userHash string, at time.Time) (*types.AccountErasure, error)
+}
+
+// Store combines the persistence interfaces served by a single database
+type Store interface {
+	PlantStore
+	NotificationStore
+	UploadStore
+	UserStore
+	APITokenStore
+	HouseholdStore
+	CareEventStore
+	VacationStore
+	PlantShareStore
+	ExportStore
+	SpeciesStore
+	RoomStore
+	SafetyProfileStore
+	PestOutbreakStore
+	TreatmentPlanStore
+	AccountStore
+}
+
+// ObjectStore holds uploaded files. Clients upload and download directly
+// through presigned URLs.
+type ObjectStore interface {
+	PresignPutURL(
+		ctx context.Context,
+		key, contentType string,
+		userID string,
+	) (string, map[string]string, error)
+	PresignGetURL(ctx context.Context, key string) (string, error)
+	// HeadObjectInfo returns the size and content type of an existing object
+	HeadObjectInfo(ctx context.Context, key string) (int64, string, error)
+	// GetObject opens an object for reading; the caller must close it
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	// PutObject stores an object generated by the server, such as an export
+	PutObject(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	DeleteObject(ctx context.Context, key string) error
+	DeleteObjects(ctx context.Context, keys []string) error
+	// DeletePrefix removes every object whose key starts with prefix and
+	// returns how many were deleted
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+	// Ready verifies the backend is reachable, for readiness probes
+	Ready(ctx context.Context) error
+}
+
+var (
+	_ Store       = (*MongoDB)(nil)
+	_ ObjectStore = (*S3Service)(nil)
+	_ ObjectStore = (*LocalStorage)(nil)
+)
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// TreatmentReminderRetry is how long the worker waits before reminding of an
+// overdue application again
+const TreatmentReminderRetry = 24 * time.Hour
+
+// FindInfection returns the infection of plant with the given ID, or nil
+func FindInfection(plant types.Plant, id string) *types.PestInfection {
+	for i := range plant.PestHistory {
+		if plant.PestHistory[i].ID == id {
+			return &plant.PestHistory[i]
+		}
+	}
+	return nil
+}
+
+// NewTreatmentPlan builds a plan against infection of plant from req. The
+// first application is due right away and reminded of a day later.
+func NewTreatmentPlan(
+	plant types.Plant,
+	infection types.PestInfection,
+	req types.CreateTreatmentPlanRequest,
+	now time.Time,
+) (types.TreatmentPlan, error) {
+	id, err := gonanoid.New()
+	if err != nil {
+		return types.TreatmentPlan{}, err
+	}
+	reminder := now.Add(TreatmentReminderRetry)
+	return types.TreatmentPlan{
+		ID:             id,
+		PlantID:        plant.ID,
+		UserID:         plant.UserID,
+		InfectionID:    infection.ID,
+		Pest:           infection.Pest,
+		Product:        strings.TrimSpace(req.Product),
+		Method:         req.Method,
+		IntervalDays:   req.IntervalDays,
+		Repetitions:    req.Repetitions,
+		Applications:   []types.TreatmentApplication{},
+		Status:         types.TreatmentActive,
+		NextDueAt:      &now,
+		NextReminderAt: &reminder,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// ApplyTreatment logs an application of plan by userID at at. The next one
+// is due IntervalDays after the latest application; the plan is completed
+// once it was applied Repetitions times.
+func ApplyTreatment(plan *types.TreatmentPlan, userID string, at time.Time, notes string, now time.Time) {
+	plan.Applications = append(plan.Applications, types.TreatmentApplication{
+		At:     at,
+		UserID: userID,
+		Notes:  strings.TrimSpace(notes),
+	})
+	plan.UpdatedAt = now
+	if len(plan.Applications) >= plan.Repetitions {
+		plan.Status = types.TreatmentCompleted
+		plan.EndedAt = &now
+		plan.NextDueAt = nil
+		plan.NextReminderAt = nil
+		return
+	}
+
+	latest := at
+	for _, application := range plan.Applications {
+		if application.At.After(latest) {
+			latest = application.At
+		}
+	}
+	next := latest.AddDate(0, 0, plan.IntervalDays)
+	plan.NextDueAt = &next
+	plan.NextReminderAt = &next
+}
+
+// StopTreatment ends plan before it is done
+func StopTreatment(plan *types.TreatmentPlan, now time.Time) {
+	plan.Status = types.TreatmentStopped
+	plan.EndedAt = &now
+	plan.NextDueAt = nil
+	plan.NextReminderAt = nil
+	plan.UpdatedAt = now
+}
+
+// TreatmentEnded reports whether plan is no longer needed because its plant
+// was deleted (plant is nil) or its infection was removed or resolved
+func TreatmentEnded(plan types.TreatmentPlan, plant *types.Plant) bool {
+	if plant == nil {
+		return true
+	}
+	infection := FindInfection(*plant, plan.InfectionID)
+	return infection == nil || infection.Status == types.PestStatusResolved
+}
+
+// StopTreatments stops the active treatment plans of plant whose infection
+// was removed or resolved
+func (t *PestTracker) StopTreatments(ctx context.Context, plant types.Plant, now time.Time) error {
+	plans, err := t.treatments.GetTreatmentPlans(ctx, plant.ID)
+	if err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		if plan.Status != types.TreatmentActive || !TreatmentEnded(plan, &plant) {
+			continue
+		}
+		if _, err := t.treatments.StopTreatmentPlan(ctx, plan.ID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
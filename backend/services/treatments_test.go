@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestApplyTreatment_SchedulesFromLatestApplication(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	plant := types.Plant{ID: "p1", UserID: "owner"}
+	infection := types.PestInfection{ID: "i1", Pest: types.PestThrips, Status: types.PestStatusActive}
+	plan, err := NewTreatmentPlan(plant, infection, types.CreateTreatmentPlanRequest{
+		Product:      " Spinosad ",
+		Method:       types.TreatmentSpray,
+		IntervalDays: 5,
+		Repetitions:  3,
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Product != "Spinosad" || !plan.NextReminderAt.Equal(now.Add(TreatmentReminderRetry)) {
+		t.Errorf("new plan: got %+v", plan)
+	}
+
+	ApplyTreatment(&plan, "owner", now, "", now)
+	// A backdated application does not move the schedule back
+	ApplyTreatment(&plan, "owner", now.AddDate(0, 0, -2), "", now)
+	want := now.AddDate(0, 0, 5)
+	if plan.Status != types.TreatmentActive || !plan.NextDueAt.Equal(want) || !plan.NextReminderAt.Equal(want) {
+		t.Errorf("next due: got %s %v, want %v", plan.Status, plan.NextDueAt, want)
+	}
+
+	ApplyTreatment(&plan, "owner", now, "", now)
+	if plan.Status != types.TreatmentCompleted || plan.NextDueAt != nil || plan.NextReminderAt != nil {
+		t.Errorf("after the last repetition: got %+v", plan)
+	}
+}
+
+func TestTreatmentEnded(t *testing.T) {
+	plan := types.TreatmentPlan{InfectionID: "i1"}
+	plant := types.Plant{PestHistory: []types.PestInfection{{ID: "i1", Status: types.PestStatusTreated}}}
+	if TreatmentEnded(plan, &plant) {
+		t.Error("treated infection: want plan to go on")
+	}
+	plant.PestHistory[0].Status = types.PestStatusResolved
+	if !TreatmentEnded(plan, &plant) {
+		t.Error("resolved infection: want plan to end")
+	}
+	if !TreatmentEnded(plan, &types.Plant{}) || !TreatmentEnded(plan, nil) {
+		t.Error("removed infection or deleted plant: want plan to end")
+	}
+}
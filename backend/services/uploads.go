@@ -3,66 +3,40 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/qreepex/water-me-app/backend/logging"
 )
 
-// Upload represents a temporary uploaded file waiting to be attached to a plant
-type Upload struct {
-	ID        string    `bson:"_id"`
-	Key       string    `bson:"key"`
-	UserID    string    `bson:"userId"`
-	CreatedAt time.Time `bson:"createdAt"`
-}
-
 // UploadService manages upload records and cleanup
 type UploadService struct {
-	collection *mongo.Collection
-	s3Service  *S3Service
+	uploads UploadStore
+	objects ObjectStore
 }
 
 // NewUploadService creates a new upload service
-func NewUploadService(db *MongoDB, s3Service *S3Service) *UploadService {
+func NewUploadService(uploads UploadStore, objects ObjectStore) *UploadService {
 	return &UploadService{
-		collection: db.GetCollection("uploads"),
-		s3Service:  s3Service,
-	}
-}
-
-// RegisterUpload records an uploaded file in the database
-func (us *UploadService) RegisterUpload(ctx context.Context, key, userID string) (string, error) {
-	upload := Upload{
-		ID:        key, // Use key as ID for easy reference
-		Key:       key,
-		UserID:    userID,
-		CreatedAt: time.Now(),
-	}
-
-	_, err := us.collection.InsertOne(ctx, upload)
-	if err != nil {
-		return "", fmt.Errorf("register upload: %w", err)
+		uploads: uploads,
+		objects: objects,
 	}
-
-	return key, nil
 }
 
-// DeleteUpload removes an upload record and deletes the file from S3
+// DeleteUpload removes an upload record and deletes the file from object storage
 func (us *UploadService) DeleteUpload(ctx context.Context, key, userID string) error {
 	// Verify the key belongs to this user before deleting
 	if !KeyBelongsToUser(key, userID) {
 		return fmt.Errorf("unauthorized: key does not belong to user")
 	}
 
-	// Delete from S3
-	if err := us.s3Service.DeleteObject(ctx, key); err != nil {
+	// Delete from object storage
+	if err := us.objects.DeleteObject(ctx, key); err != nil {
 		return fmt.Errorf("delete from s3: %w", err)
 	}
 
 	// Delete from database
-	_, err := us.collection.DeleteOne(ctx, bson.M{"_id": key})
-	if err != nil {
+	if _, err := us.uploads.DeleteUploadRecords(ctx, []string{key}); err != nil {
 		return fmt.Errorf("delete upload record: %w", err)
 	}
 
@@ -77,18 +51,10 @@ func (us *UploadService) CleanupOrphanedUploads(
 	cutoffTime := time.Now().Add(-maxAge)
 
 	// Find all uploads older than maxAge
-	cursor, err := us.collection.Find(ctx, bson.M{
-		"createdAt": bson.M{"$lt": cutoffTime},
-	})
+	uploads, err := us.uploads.GetUploadsCreatedBefore(ctx, cutoffTime)
 	if err != nil {
 		return 0, fmt.Errorf("query orphaned uploads: %w", err)
 	}
-	defer cursor.Close(ctx)
-
-	var uploads []Upload
-	if err = cursor.All(ctx, &uploads); err != nil {
-		return 0, fmt.Errorf("decode uploads: %w", err)
-	}
 
 	if len(uploads) == 0 {
 		return 0, nil
@@ -96,21 +62,24 @@ func (us *UploadService) CleanupOrphanedUploads(
 
 	// Filter to only unreferenced uploads
 	var keysToDelete []string
-	var idsToDelete []interface{}
 
 	for _, u := range uploads {
 		// Check if this upload is referenced in any plant
-		referenced, err := us.IsUploadReferenced(ctx, u.Key)
+		referenced, err := us.uploads.IsUploadReferenced(ctx, u.Key)
 		if err != nil {
 			// Log error but continue checking others
-			fmt.Printf("Error checking reference for %s: %v\n", u.Key, err)
+			slog.ErrorContext(
+				ctx,
+				"failed to check upload reference",
+				"userHash", logging.HashUserID(u.UserID),
+				"error", err,
+			)
 			continue
 		}
 
 		// Only delete if NOT referenced
 		if !referenced {
 			keysToDelete = append(keysToDelete, u.Key)
-			idsToDelete = append(idsToDelete, u.ID)
 		}
 	}
 
@@ -118,30 +87,16 @@ func (us *UploadService) CleanupOrphanedUploads(
 		return 0, nil
 	}
 
-	// Delete from S3
-	if err := us.s3Service.DeleteObjects(ctx, keysToDelete); err != nil {
+	// Delete from object storage
+	if err := us.objects.DeleteObjects(ctx, keysToDelete); err != nil {
 		return 0, fmt.Errorf("batch delete from s3: %w", err)
 	}
 
 	// Delete from database
-	result, err := us.collection.DeleteMany(ctx, bson.M{
-		"_id": bson.M{"$in": idsToDelete},
-	})
+	deleted, err := us.uploads.DeleteUploadRecords(ctx, keysToDelete)
 	if err != nil {
 		return 0, fmt.Errorf("batch delete uploads: %w", err)
 	}
 
-	return int(result.DeletedCount), nil
-}
-
-// IsUploadReferenced checks if an upload is referenced in any plant
-func (us *UploadService) IsUploadReferenced(ctx context.Context, key string) (bool, error) {
-	plantsCollection := us.collection.Database().Collection("plants")
-	count, err := plantsCollection.CountDocuments(ctx, bson.M{
-		"photoIds": key,
-	})
-	if err != nil {
-		return false, fmt.Errorf("check reference: %w", err)
-	}
-	return count > 0, nil
+	return int(deleted), nil
 }
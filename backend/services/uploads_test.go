@@ -0,0 +1,52 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/services"
+	"github.com/qreepex/water-me-app/backend/services/memstore"
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func TestCleanupOrphanedUploads_KeepsReferencedUploads(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	objects := memstore.NewObjectStore()
+
+	for _, key := range []string{"users/u1/orphan.jpg", "users/u1/used.jpg"} {
+		objects.Put(key, 100, "image/jpeg")
+		if err := store.RegisterUpload(ctx, "u1", key, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := store.CreatePlant(ctx, types.Plant{
+		UserID:   "u1",
+		Slug:     "ficus",
+		PhotoIDs: []string{"users/u1/used.jpg"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploadSvc := services.NewUploadService(store, objects)
+	// A negative max age makes every upload old enough to be cleaned up
+	deleted, err := uploadSvc.CleanupOrphanedUploads(ctx, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted != 1 {
+		t.Errorf("deleted %d uploads, want 1", deleted)
+	}
+	if objects.Has("users/u1/orphan.jpg") {
+		t.Error("orphaned object was not deleted")
+	}
+	if !objects.Has("users/u1/used.jpg") {
+		t.Error("referenced object was deleted")
+	}
+	if count, _ := store.GetUserUploadCount(ctx, "u1"); count != 1 {
+		t.Errorf("%d upload records left, want 1", count)
+	}
+}
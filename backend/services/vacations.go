@@ -0,0 +1,57 @@
+package services
+
+import (
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// BuildVacationSummary summarizes the care events logged on the owner's
+// plants during a vacation. plants are the owner's current plants; events of
+// plants deleted since are counted in the totals only.
+func BuildVacationSummary(
+	vacation types.Vacation,
+	plants []types.Plant,
+	events []types.CareEvent,
+) types.VacationSummary {
+	summary := types.VacationSummary{
+		VacationID:    vacation.ID,
+		StartsAt:      vacation.StartsAt,
+		EndsAt:        vacation.EndsAt,
+		CaretakerName: vacation.CaretakerName,
+		ByType:        make(map[types.CareEventType]int),
+		Plants:        make([]types.PlantCareSummary, 0, len(plants)),
+		NotCaredFor:   make([]string, 0),
+	}
+
+	perPlant := make(map[string]*types.PlantCareSummary, len(plants))
+	for _, plant := range plants {
+		summary.Plants = append(summary.Plants, types.PlantCareSummary{
+			PlantID: plant.ID,
+			Name:    plant.Name,
+		})
+	}
+	for i := range summary.Plants {
+		perPlant[summary.Plants[i].PlantID] = &summary.Plants[i]
+	}
+
+	for _, event := range events {
+		if !event.Type.IsCare() {
+			continue
+		}
+		summary.TotalEvents++
+		summary.ByType[event.Type]++
+		if plant, ok := perPlant[event.PlantID]; ok {
+			plant.Events++
+			at := event.At
+			if plant.LastCare == nil || at.After(*plant.LastCare) {
+				plant.LastCare = &at
+			}
+		}
+	}
+
+	for _, plant := range summary.Plants {
+		if plant.Events == 0 {
+			summary.NotCaredFor = append(summary.NotCaredFor, plant.Name)
+		}
+	}
+	return summary
+}
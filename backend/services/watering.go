@@ -0,0 +1,258 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	// RecommendationWindow is how much care history recommendations look at
+	RecommendationWindow = 180 * 24 * time.Hour
+	// minRecommendationIntervals is how many gaps between waterings a
+	// recommendation needs; maxRecommendationIntervals how many recent
+	// ones it uses
+	minRecommendationIntervals = 3
+	maxRecommendationIntervals = 8
+	// maxWateringIntervalDays matches the limit of plant validation
+	maxWateringIntervalDays = 365
+)
+
+// IsWinter reports whether t falls in the northern winter, December to
+// February, when the winter water factor of plants applies
+func IsWinter(t time.Time) bool {
+	month := t.Month()
+	return month == time.December || month <= time.February
+}
+
+// winterFactor is how much the plant is watered in winter compared to the
+// rest of the year, 1 without seasonal adjustments
+func winterFactor(plant types.Plant) float64 {
+	if plant.Seasonality == nil || plant.Seasonality.WinterWaterFactor <= 0 {
+		return 1
+	}
+	return plant.Seasonality.WinterWaterFactor
+}
+
+var healthRanks = map[types.HealthStatus]int{
+	types.HealthPoor:      1,
+	types.HealthFair:      2,
+	types.HealthGood:      3,
+	types.HealthExcellent: 4,
+}
+
+// PlantHealthTrend compares the two latest rated entries of the growth log.
+// Dormant entries say nothing about health and are skipped.
+func PlantHealthTrend(plant types.Plant) types.HealthTrend {
+	logs := make([]types.GrowthLog, 0, len(plant.GrowthHistory))
+	for _, log := range plant.GrowthHistory {
+		if healthRanks[log.Health] > 0 {
+			logs = append(logs, log)
+		}
+	}
+	if len(logs) < 2 {
+		return types.HealthTrendUnknown
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Date.Before(logs[j].Date) })
+
+	latest := healthRanks[logs[len(logs)-1].Health]
+	previous := healthRanks[logs[len(logs)-2].Health]
+	switch {
+	case latest > previous:
+		return types.HealthImproving
+	case latest < previous:
+		return types.HealthDeclining
+	default:
+		return types.HealthStable
+	}
+}
+
+// RecommendWatering suggests a watering interval for plant from how it was
+// actually watered and snoozed in events, its health trend and the season.
+// Gaps of more than four intervals, e.g. during a vacation, are ignored, and
+// winter gaps are scaled by the winter water factor so the suggestion is for
+// the rest of the year like the interval itself.
+func RecommendWatering(plant types.Plant, events []types.CareEvent, now time.Time) types.WateringRecommendation {
+	rec := types.WateringRecommendation{
+		PlantID:     plant.ID,
+		Name:        plant.Name,
+		Reasons:     []string{},
+		HealthTrend: PlantHealthTrend(plant),
+	}
+	if plant.Watering == nil || plant.Watering.IntervalDays <= 0 {
+		rec.Reasons = append(rec.Reasons, "No watering interval is set")
+		rec.Explanation = explain(rec.Reasons)
+		return rec
+	}
+	current := plant.Watering.IntervalDays
+	rec.CurrentIntervalDays = current
+	rec.SuggestedIntervalDays = current
+	factor := winterFactor(plant)
+	rec.Winter = factor != 1 && IsWinter(now)
+
+	var waterings []time.Time
+	snoozeDays := 0
+	for _, event := range events {
+		if event.PlantID != plant.ID {
+			continue
+		}
+		switch event.Type {
+		case types.CareWatered:
+			waterings = append(waterings, event.At)
+		case types.CareSnoozed:
+			rec.Snoozes++
+			snoozeDays += event.Days
+		}
+	}
+	sort.Slice(waterings, func(i, j int) bool { return waterings[i].Before(waterings[j]) })
+	rec.Waterings = len(waterings)
+
+	intervals := make([]float64, 0, len(waterings))
+	winterIntervals := 0
+	for i := 1; i < len(waterings); i++ {
+		days := waterings[i].Sub(waterings[i-1]).Hours() / 24
+		if days < 0.5 {
+			// Logged twice, e.g. by two household members
+			continue
+		}
+		if factor != 1 && IsWinter(waterings[i]) {
+			days *= factor
+			winterIntervals++
+		}
+		if days > 4*float64(current) {
+			continue
+		}
+		intervals = append(intervals, days)
+	}
+	if len(intervals) > maxRecommendationIntervals {
+		intervals = intervals[len(intervals)-maxRecommendationIntervals:]
+	}
+	if len(intervals) < minRecommendationIntervals {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"Only %d regular intervals between waterings were logged in the last %d days; at least %d are needed",
+			len(intervals), int(RecommendationWindow.Hours()/24), minRecommendationIntervals,
+		))
+		rec.Explanation = explain(rec.Reasons)
+		return rec
+	}
+
+	observed := median(intervals)
+	rounded := math.Round(observed*10) / 10
+	rec.ObservedIntervalDays = &rounded
+	rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+		"You watered every %.1f days over the last %d waterings, while the interval is %d days",
+		rounded, len(intervals)+1, current,
+	))
+
+	// More and more regular waterings make the observation more reliable
+	mean, spread := meanAndDeviation(intervals)
+	variation := math.Min(spread/mean, 1)
+	confidence := float64(len(intervals)) / maxRecommendationIntervals * (1 - variation/2)
+	suggested := observed
+
+	if rec.Snoozes > 0 {
+		averageSnooze := float64(snoozeDays) / float64(rec.Snoozes)
+		switch {
+		case suggested > float64(current):
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+				"You also snoozed %d reminders by %.1f days on average, so it usually did not need water yet",
+				rec.Snoozes, averageSnooze,
+			))
+			confidence += 0.1
+		case float64(rec.Snoozes) >= float64(rec.Waterings)/4:
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+				"But you snoozed %d reminders, so watering more often is less certain", rec.Snoozes,
+			))
+			confidence -= 0.2
+		}
+	}
+
+	switch rec.HealthTrend {
+	case types.HealthDeclining:
+		suggested = float64(current) + (suggested-float64(current))/2
+		rec.Reasons = append(rec.Reasons, "Its health declined recently, so only half of the change is suggested")
+		confidence -= 0.2
+	case types.HealthImproving:
+		rec.Reasons = append(rec.Reasons, "Its health improved on this routine")
+		confidence += 0.1
+	}
+
+	if winterIntervals > 0 {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"Winter waterings were adjusted by its winter water factor of %.1f", factor,
+		))
+	}
+
+	days := int(math.Round(suggested))
+	days = max(1, min(days, maxWateringIntervalDays))
+	if abs(days-current) < max(1, int(math.Round(float64(current)*0.15))) {
+		rec.Reasons = append(rec.Reasons, "That is close enough to keep the current interval")
+	} else {
+		rec.SuggestedIntervalDays = days
+		rec.Change = true
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("Water every %d days instead of %d", days, current))
+	}
+	if rec.Winter {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"In winter that is about every %d days", int(math.Round(float64(rec.SuggestedIntervalDays)/factor)),
+		))
+	}
+
+	rec.Confidence = math.Round(math.Max(0.05, math.Min(confidence, 0.95))*100) / 100
+	rec.Explanation = explain(rec.Reasons)
+	return rec
+}
+
+func explain(reasons []string) string {
+	return strings.Join(reasons, ". ") + "."
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func meanAndDeviation(values []float64) (float64, float64) {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+// NewCareEvent creates a care event by userID on plant
+func NewCareEvent(
+	plant types.Plant,
+	userID string,
+	eventType types.CareEventType,
+	at time.Time,
+) (types.CareEvent, error) {
+	id, err := gonanoid.New()
+	if err != nil {
+		return types.CareEvent{}, fmt.Errorf("generate care event id: %w", err)
+	}
+	return types.CareEvent{
+		ID:      id,
+		PlantID: plant.ID,
+		OwnerID: plant.UserID,
+		UserID:  userID,
+		Type:    eventType,
+		At:      at,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+func wateringEvents(plantID string, start time.Time, count, intervalDays int) []types.CareEvent {
+	events := make([]types.CareEvent, 0, count)
+	for i := range count {
+		events = append(events, types.CareEvent{
+			PlantID: plantID,
+			Type:    types.CareWatered,
+			At:      start.AddDate(0, 0, i*intervalDays),
+		})
+	}
+	return events
+}
+
+func TestRecommendWatering_DecliningHealthHalvesChange(t *testing.T) {
+	now := time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC)
+	plant := types.Plant{
+		ID:       "p1",
+		Name:     "Fern",
+		Watering: &types.WateringConfig{IntervalDays: 4},
+		GrowthHistory: []types.GrowthLog{
+			{Date: now.AddDate(0, 0, -40), Health: types.HealthGood},
+			{Date: now.AddDate(0, 0, -30), Health: types.HealthDormant},
+			{Date: now.AddDate(0, 0, -5), Health: types.HealthFair},
+		},
+	}
+	events := wateringEvents("p1", now.AddDate(0, 0, -50), 7, 8)
+
+	rec := RecommendWatering(plant, events, now)
+	if rec.HealthTrend != types.HealthDeclining || rec.SuggestedIntervalDays != 6 || !rec.Change {
+		t.Errorf("recommendation: got %+v", rec)
+	}
+}
+
+func TestRecommendWatering_ScalesWinterWaterings(t *testing.T) {
+	now := time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC)
+	plant := types.Plant{
+		ID:          "p1",
+		Name:        "Monstera",
+		Watering:    &types.WateringConfig{IntervalDays: 7},
+		Seasonality: &types.SeasonalAdjustments{WinterWaterFactor: 0.5},
+	}
+	// Every 14 days in winter is every 7 days the rest of the year
+	events := wateringEvents("p1", now.AddDate(0, 0, -60), 5, 14)
+	events = append(events, types.CareEvent{PlantID: "p2", Type: types.CareWatered, At: now})
+
+	rec := RecommendWatering(plant, events, now)
+	if rec.Change || *rec.ObservedIntervalDays != 7 || !rec.Winter {
+		t.Errorf("recommendation: got %+v", rec)
+	}
+	if !strings.Contains(rec.Explanation, "winter water factor") || !strings.Contains(rec.Explanation, "every 14 days") {
+		t.Errorf("explanation: got %q", rec.Explanation)
+	}
+}
+
+func TestRecommendWatering_CountsOnlyRegularIntervals(t *testing.T) {
+	now := time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC)
+	plant := types.Plant{ID: "p1", Name: "Fern", Watering: &types.WateringConfig{IntervalDays: 3}}
+	// Ten waterings, but every gap is more than four intervals long
+	events := wateringEvents("p1", now.AddDate(0, 0, -171), 10, 19)
+
+	rec := RecommendWatering(plant, events, now)
+	if rec.ObservedIntervalDays != nil {
+		t.Errorf("recommendation: got %+v", rec)
+	}
+	if !strings.Contains(rec.Explanation, "Only 0 regular intervals") {
+		t.Errorf("explanation: got %q", rec.Explanation)
+	}
+}
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// AccountErasure is the audit record of a deleted account. It is keyed by the
+// hashed user ID so no personal data remains after the erasure.
+type AccountErasure struct {
+	UserHash    string     `json:"userHash"              bson:"_id"`
+	StartedAt   time.Time  `json:"startedAt"             bson:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
+	// Attempts counts erasure runs, including ones that failed part-way
+	Attempts int `json:"attempts" bson:"attempts"`
+}
@@ -0,0 +1,78 @@
+package types
+
+import "time"
+
+// GrowthPoint is one growth log entry with the growth since the entry before;
+// the rates are nil for the first entry
+type GrowthPoint struct {
+	Date            time.Time `json:"date"`
+	HeightCm        float64   `json:"heightCm"`
+	LeafCount       int       `json:"leafCount"`
+	HeightCmPerWeek *float64  `json:"heightCmPerWeek"`
+	LeavesPerWeek   *float64  `json:"leavesPerWeek"`
+}
+
+// HealthPoint is the health of a plant at one growth log entry
+type HealthPoint struct {
+	Date   time.Time    `json:"date"`
+	Health HealthStatus `json:"health"`
+}
+
+// CareRegularity tells how punctually a plant was watered. A watering is late
+// when it came more than a day after the interval was up.
+type CareRegularity struct {
+	Waterings int `json:"waterings"`
+	OnTime    int `json:"onTime"`
+	Late      int `json:"late"`
+	// OnTimeRate is OnTime over all rated waterings; nil without any
+	OnTimeRate       *float64 `json:"onTimeRate"`
+	AverageDelayDays float64  `json:"averageDelayDays"`
+	// Correlation is the Pearson correlation of the on-time rate in the 30
+	// days before each growth log entry with the health recorded in it; nil
+	// with fewer than three comparable entries
+	Correlation *float64 `json:"correlation"`
+	Message     string   `json:"message"`
+}
+
+// ScoreComponent is one part of a composite health score
+type ScoreComponent struct {
+	Name   string  `json:"name"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
+}
+
+// PlantAnalytics describes how a plant develops and how well it is looked after
+type PlantAnalytics struct {
+	PlantID     string         `json:"plantId"`
+	Name        string         `json:"name"`
+	Growth      []GrowthPoint  `json:"growth"`
+	Health      []HealthPoint  `json:"health"`
+	HealthTrend HealthTrend    `json:"healthTrend"`
+	Care        CareRegularity `json:"care"`
+	// HealthScore is 0 to 100, the weighted mean of Components; nil when
+	// nothing could be scored
+	HealthScore *int             `json:"healthScore"`
+	Components  []ScoreComponent `json:"components"`
+	Declining   bool             `json:"declining"`
+	// DecliningReasons explains Declining
+	DecliningReasons []string `json:"decliningReasons"`
+}
+
+// DecliningPlant is a plant in the analytics summary whose condition is
+// getting worse
+type DecliningPlant struct {
+	PlantID     string   `json:"plantId"`
+	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
+	HealthScore *int     `json:"healthScore"`
+	Reasons     []string `json:"reasons"`
+}
+
+// AnalyticsSummary sums up the analytics of all plants a user can access
+type AnalyticsSummary struct {
+	Plants int `json:"plants"`
+	Scored int `json:"scored"`
+	// AverageHealthScore is nil when no plant could be scored
+	AverageHealthScore *int             `json:"averageHealthScore"`
+	Declining          []DecliningPlant `json:"declining"`
+}
@@ -0,0 +1,55 @@
+package types
+
+import "time"
+
+// TokenScope limits what a personal API token may do
+type TokenScope string
+
+const (
+	ScopePlantsRead          TokenScope = "plants:read"
+	ScopePlantsCare          TokenScope = "plants:care"
+	ScopeNotificationsManage TokenScope = "notifications:manage"
+)
+
+// APIToken is a user-created token for scripts and home automation. Only the
+// SHA-256 hash of the token is stored.
+type APIToken struct {
+	ID     string `json:"id"                   bson:"_id"`
+	UserID string `json:"-"                    bson:"userId"`
+	Name   string `json:"name"                 bson:"name"`
+	Hash   string `json:"-"                    bson:"hash"`
+	// Prefix is the start of the token, shown so users can tell tokens apart
+	Prefix     string       `json:"prefix"               bson:"prefix"`
+	Scopes     []TokenScope `json:"scopes"               bson:"scopes"`
+	ExpiresAt  *time.Time   `json:"expiresAt,omitempty"  bson:"expiresAt,omitempty"`
+	LastUsedAt *time.Time   `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"            bson:"createdAt"`
+}
+
+// HasScope reports whether the token grants scope
+func (t APIToken) HasScope(scope TokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the token has expired at now
+func (t APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// CreateAPITokenRequest is the request body for creating an API token
+type CreateAPITokenRequest struct {
+	Name      string       `json:"name"`
+	Scopes    []TokenScope `json:"scopes"`
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"`
+}
+
+// CreateAPITokenResponse returns the plain token. It is shown only once.
+type CreateAPITokenResponse struct {
+	APIToken
+	Token string `json:"token"`
+}
@@ -1,6 +1,10 @@
 package types
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 // ValidationError mirrors the TS validation shape.
 type ValidationError struct {
@@ -8,14 +12,15 @@ type ValidationError struct {
 	Message string `json:"message"`
 }
 
-// User represents a user account.
+// User represents a user account of the local email/password auth mode.
 type User struct {
-	ID           string `json:"id"`
-	Username     string `json:"username"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"-"`
-	Language     string `json:"language"`
-	CreatedAt    string `json:"createdAt"`
+	ID                string    `json:"id"        bson:"_id"`
+	Username          string    `json:"username"  bson:"username"`
+	Email             string    `json:"email"     bson:"email"`
+	PasswordHash      string    `json:"-"         bson:"passwordHash"`
+	PasswordChangedAt time.Time `json:"-"         bson:"passwordChangedAt"`
+	Language          string    `json:"language"  bson:"language"`
+	CreatedAt         time.Time `json:"createdAt" bson:"createdAt"`
 }
 
 // SignupRequest is the request body for user registration.
@@ -30,10 +35,18 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
-// LoginResponse contains the JWT token after successful login.
+// LoginResponse contains the JWT access token after successful login and a
+// refresh token to obtain new access tokens.
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	RefreshToken string    `json:"refreshToken"`
+	User         User      `json:"user"`
+}
+
+// RefreshRequest exchanges a refresh token for a new token pair.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 // UpdateUserRequest is for updating user profile information.
@@ -49,7 +62,10 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"newPassword"`
 }
 
+// Claims are the JWT claims issued by the local auth mode. TokenType is
+// "access" or "refresh".
 type Claims struct {
-	UserID string `json:"uid"`
+	UserID    string `json:"uid"`
+	TokenType string `json:"typ"`
 	jwt.RegisteredClaims
 }
@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// CareEventType is the kind of care action performed on a plant
+type CareEventType string
+
+const (
+	CareWatered CareEventType = "watered"
+	// CareSnoozed postpones a watering reminder by Days
+	CareSnoozed CareEventType = "snoozed"
+	// CareIntervalChanged records an accepted watering recommendation
+	CareIntervalChanged CareEventType = "intervalChanged"
+)
+
+// IsCare reports whether the event is care given to the plant rather than a
+// change to its reminders
+func (t CareEventType) IsCare() bool {
+	return t != CareSnoozed && t != CareIntervalChanged
+}
+
+// IntervalChange is the watering interval before and after a change
+type IntervalChange struct {
+	FromDays int `json:"fromDays" bson:"fromDays"`
+	ToDays   int `json:"toDays"   bson:"toDays"`
+}
+
+// CareEvent records one care action. OwnerID is the plant's owner, UserID the
+// user who performed it, which differs for household members and caretakers.
+type CareEvent struct {
+	ID      string        `json:"id"      bson:"_id"`
+	PlantID string        `json:"plantId" bson:"plantId"`
+	OwnerID string        `json:"ownerId" bson:"ownerId"`
+	UserID  string        `json:"userId"  bson:"userId"`
+	Type    CareEventType `json:"type"    bson:"type"`
+	At      time.Time     `json:"at"      bson:"at"`
+	// Days is set for snoozes
+	Days int `json:"days,omitempty" bson:"days,omitempty"`
+	// Interval is set for interval changes
+	Interval *IntervalChange `json:"interval,omitempty" bson:"interval,omitempty"`
+}
@@ -0,0 +1,37 @@
+package types
+
+import "time"
+
+type ExportStatus string
+
+const (
+	ExportPending   ExportStatus = "pending"
+	ExportRunning   ExportStatus = "running"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+// DataExport is an asynchronous export of all of a user's data into a ZIP
+// archive stored under their object prefix
+type DataExport struct {
+	ID          string       `json:"id"                    bson:"_id"`
+	UserID      string       `json:"-"                     bson:"userId"`
+	Status      ExportStatus `json:"status"                bson:"status"`
+	PhotosTotal int          `json:"photosTotal"           bson:"photosTotal"`
+	PhotosDone  int          `json:"photosDone"            bson:"photosDone"`
+	Error       string       `json:"error,omitempty"       bson:"error,omitempty"`
+	Key         string       `json:"-"                     bson:"key,omitempty"`
+	SizeBytes   int64        `json:"sizeBytes,omitempty"   bson:"sizeBytes,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"             bson:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"             bson:"updatedAt"`
+	CompletedAt *time.Time   `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
+	// ExpiresAt is when the archive is deleted
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	// DownloadURL is a short-lived presigned URL; set in responses only
+	DownloadURL string `json:"downloadUrl,omitempty" bson:"-"`
+}
+
+// Active reports whether the export is still being built
+func (e DataExport) Active() bool {
+	return e.Status == ExportPending || e.Status == ExportRunning
+}
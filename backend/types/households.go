@@ -0,0 +1,94 @@
+package types
+
+import "time"
+
+// HouseholdRole is a member's role in a household
+type HouseholdRole string
+
+const (
+	RoleOwner     HouseholdRole = "owner"
+	RoleEditor    HouseholdRole = "editor"
+	RoleCaretaker HouseholdRole = "caretaker" // Can only perform care actions
+	RoleViewer    HouseholdRole = "viewer"
+)
+
+// CanEditPlants reports whether the role may create, change and delete plants
+func (r HouseholdRole) CanEditPlants() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// CanCare reports whether the role may perform care actions such as watering
+func (r HouseholdRole) CanCare() bool {
+	return r.CanEditPlants() || r == RoleCaretaker
+}
+
+// HouseholdMember is a user in a household
+type HouseholdMember struct {
+	UserID      string        `json:"userId"                bson:"userId"`
+	DisplayName string        `json:"displayName,omitempty" bson:"displayName,omitempty"`
+	Role        HouseholdRole `json:"role"                  bson:"role"`
+	JoinedAt    time.Time     `json:"joinedAt"              bson:"joinedAt"`
+}
+
+// Household shares plants between its members. Plants join a household through
+// their householdId; the creating user stays the plant's owner.
+type Household struct {
+	ID      string            `json:"id"        bson:"_id"`
+	Name    string            `json:"name"      bson:"name"`
+	Members []HouseholdMember `json:"members"   bson:"members"`
+	// Profile lists the pets and children living with the shared plants
+	Profile   *SafetyProfile `json:"profile,omitempty" bson:"profile,omitempty"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Member returns the membership of userID
+func (h Household) Member(userID string) (HouseholdMember, bool) {
+	for _, member := range h.Members {
+		if member.UserID == userID {
+			return member, true
+		}
+	}
+	return HouseholdMember{}, false
+}
+
+// HouseholdInvite lets the holder of its code join a household once
+type HouseholdInvite struct {
+	ID          string        `json:"id"          bson:"_id"`
+	HouseholdID string        `json:"householdId" bson:"householdId"`
+	Code        string        `json:"code"        bson:"code"`
+	Role        HouseholdRole `json:"role"        bson:"role"`
+	CreatedBy   string        `json:"createdBy"   bson:"createdBy"`
+	ExpiresAt   time.Time     `json:"expiresAt"   bson:"expiresAt"`
+	CreatedAt   time.Time     `json:"createdAt"   bson:"createdAt"`
+	Link        string        `json:"link"        bson:"-"`
+}
+
+// CreateHouseholdRequest is the request body for creating a household
+type CreateHouseholdRequest struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// CreateHouseholdInviteRequest is the request body for inviting a member
+type CreateHouseholdInviteRequest struct {
+	Role           HouseholdRole `json:"role"`
+	ExpiresInHours int           `json:"expiresInHours,omitempty"`
+}
+
+// JoinHouseholdRequest is the request body for accepting an invite
+type JoinHouseholdRequest struct {
+	Code        string `json:"code"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// UpdateHouseholdMemberRequest changes a member's role
+type UpdateHouseholdMemberRequest struct {
+	Role HouseholdRole `json:"role"`
+}
+
+// SetPlantHouseholdRequest moves a plant into a household, or out of it when
+// HouseholdID is empty
+type SetPlantHouseholdRequest struct {
+	HouseholdID string `json:"householdId"`
+}
@@ -0,0 +1,24 @@
+package types
+
+// PlantCollection is the JSON format of plant exports and imports, so an
+// export can be imported again as is
+type PlantCollection struct {
+	Plants []CreatePlantRequest `json:"plants"`
+}
+
+// ImportRowResult reports the outcome for one row of an import. Row is
+// 1-based and counts plants, not lines of a CSV file.
+type ImportRowResult struct {
+	Row    int               `json:"row"`
+	Name   string            `json:"name"`
+	Slug   string            `json:"slug,omitempty"`
+	ID     string            `json:"id,omitempty"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// ImportPlantsResponse is returned by imports and their dry-run previews
+type ImportPlantsResponse struct {
+	DryRun   bool              `json:"dryRun"`
+	Imported int               `json:"imported"`
+	Rows     []ImportRowResult `json:"rows"`
+}
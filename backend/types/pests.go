@@ -0,0 +1,66 @@
+package types
+
+import "time"
+
+// OutbreakStatus tells whether a pest outbreak is still spreading
+type OutbreakStatus string
+
+const (
+	OutbreakOpen OutbreakStatus = "open"
+	// OutbreakContained is set once no infected plant has an active
+	// infection of the pest any more
+	OutbreakContained OutbreakStatus = "contained"
+)
+
+// OutbreakPlant is a plant involved in an outbreak
+type OutbreakPlant struct {
+	PlantID string `json:"plantId" bson:"plantId"`
+	Name    string `json:"name"    bson:"name"`
+}
+
+// PestOutbreak is an active pest infection in a room, with the other plants
+// in the room that are at risk. Outbreaks belong to the owner of the plants.
+type PestOutbreak struct {
+	ID     string   `json:"id"               bson:"_id"`
+	UserID string   `json:"userId"           bson:"userId"`
+	Pest   PestType `json:"pest"             bson:"pest"`
+	RoomID string   `json:"roomId,omitempty" bson:"roomId,omitempty"`
+	Room   string   `json:"room"             bson:"room"`
+	// LocationKey identifies the room: its ID, or the normalized name for
+	// plants not linked to a room
+	LocationKey string `json:"-" bson:"locationKey"`
+
+	Infected []OutbreakPlant `json:"infected" bson:"infected"`
+	AtRisk   []OutbreakPlant `json:"atRisk"   bson:"atRisk"`
+	// Quarantine suggests how to keep the pest from spreading
+	Quarantine string         `json:"quarantine" bson:"quarantine"`
+	Status     OutbreakStatus `json:"status"     bson:"status"`
+
+	// NextInspectionAt is when the worker reminds the owner to inspect the
+	// plants at risk next; nil once all inspections are due or the
+	// outbreak is contained
+	NextInspectionAt *time.Time `json:"nextInspectionAt,omitempty" bson:"nextInspectionAt,omitempty"`
+	InspectionsSent  int        `json:"inspectionsSent"            bson:"inspectionsSent"`
+
+	DetectedAt  time.Time  `json:"detectedAt"            bson:"detectedAt"`
+	ContainedAt *time.Time `json:"containedAt,omitempty" bson:"containedAt,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"             bson:"updatedAt"`
+}
+
+// PestTypeStats counts the infections of one pest by status
+type PestTypeStats struct {
+	Pest     PestType           `json:"pest"`
+	Total    int                `json:"total"`
+	ByStatus map[PestStatus]int `json:"byStatus"`
+}
+
+// PestStats sums up the pest history of all plants a user can access
+type PestStats struct {
+	Infections int                `json:"infections"`
+	ByStatus   map[PestStatus]int `json:"byStatus"`
+	// ByPest is ordered by Total, most frequent first
+	ByPest []PestTypeStats `json:"byPest"`
+	// InfestedPlants have at least one active infection
+	InfestedPlants int `json:"infestedPlants"`
+	OpenOutbreaks  int `json:"openOutbreaks"`
+}
@@ -0,0 +1,52 @@
+package types
+
+// PlacementFactor is a room condition a placement is scored on
+type PlacementFactor string
+
+const (
+	FactorLight       PlacementFactor = "light"
+	FactorTemperature PlacementFactor = "temperature"
+	FactorHumidity    PlacementFactor = "humidity"
+)
+
+// PlacementStatus rates a placement score
+type PlacementStatus string
+
+const (
+	PlacementGood PlacementStatus = "good"
+	PlacementFair PlacementStatus = "fair"
+	PlacementPoor PlacementStatus = "poor"
+	// PlacementUnknown is used when the plant has no room, or neither the
+	// plant nor its room say anything about the factors
+	PlacementUnknown PlacementStatus = "unknown"
+)
+
+// PlacementCheck compares one need of a plant with its room
+type PlacementCheck struct {
+	Factor PlacementFactor `json:"factor"`
+	// Score is 0 (unsuitable) to 100 (ideal)
+	Score   int             `json:"score"`
+	Status  PlacementStatus `json:"status"`
+	Message string          `json:"message"`
+}
+
+// RoomSuggestion is a room of the owner that suits a plant better
+type RoomSuggestion struct {
+	RoomID string `json:"roomId"`
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+}
+
+// PlacementReport scores how well a plant's room suits it. Score is the mean
+// of the checks and nil when nothing could be compared.
+type PlacementReport struct {
+	PlantID     string           `json:"plantId"`
+	Name        string           `json:"name"`
+	Slug        string           `json:"slug"`
+	RoomID      string           `json:"roomId,omitempty"`
+	Room        string           `json:"room,omitempty"`
+	Score       *int             `json:"score"`
+	Status      PlacementStatus  `json:"status"`
+	Checks      []PlacementCheck `json:"checks"`
+	Suggestions []RoomSuggestion `json:"suggestions"`
+}
@@ -90,9 +90,11 @@ const (
 // --- SUB-STRUCTURES ---
 
 type Location struct {
-	Room       string `json:"room"       bson:"room"`
-	Position   string `json:"position"   bson:"position"`
-	IsOutdoors bool   `json:"isOutdoors" bson:"isOutdoors"`
+	// RoomID references a Room; Room and IsOutdoors are copied from it
+	RoomID     string `json:"roomId,omitempty" bson:"roomId,omitempty"`
+	Room       string `json:"room"             bson:"room"`
+	Position   string `json:"position"         bson:"position"`
+	IsOutdoors bool   `json:"isOutdoors"       bson:"isOutdoors"`
 }
 
 type WateringConfig struct {
@@ -100,6 +102,8 @@ type WateringConfig struct {
 	Method       WateringMethod `json:"method"       bson:"method"`
 	WaterType    WaterType      `json:"waterType"    bson:"waterType"`
 	LastWatered  *time.Time     `json:"lastWatered"  bson:"lastWatered"`
+	// SnoozedUntil holds back watering reminders; watering clears it
+	SnoozedUntil *time.Time `json:"snoozedUntil,omitempty" bson:"snoozedUntil,omitempty"`
 }
 
 type HumidityConfig struct {
@@ -161,6 +165,21 @@ type Plant struct {
 	Name    string `json:"name"    bson:"name"`
 	Species string `json:"species" bson:"species"`
 	IsToxic bool   `json:"isToxic" bson:"isToxic"`
+	// Toxicity details IsToxic per animal; IsToxic follows it when set
+	Toxicity *Toxicity `json:"toxicity,omitempty" bson:"toxicity,omitempty"`
+	// SpeciesID links the plant to an entry of the species catalog
+	SpeciesID string `json:"speciesId,omitempty" bson:"speciesId,omitempty"`
+
+	// HouseholdID shares the plant with the members of a household
+	HouseholdID string `json:"householdId,omitempty" bson:"householdId,omitempty"`
+	// Role is the requesting user's role for the plant; set in responses only
+	Role HouseholdRole `json:"role,omitempty" bson:"-"`
+	// SafetyWarnings lists residents that can reach the plant and that it is
+	// toxic to; set in create and update responses only
+	SafetyWarnings []PlantRisk `json:"safetyWarnings,omitempty" bson:"-"`
+	// Outbreaks lists the pest outbreaks this change started or spread;
+	// set in create and update responses only
+	Outbreaks []PestOutbreak `json:"outbreaks,omitempty" bson:"-"`
 
 	Sunlight            *SunlightRequirement `json:"sunlight"  bson:"sunlight,omitempty"`
 	PreferedTemperature *float64             `json:"preferedTemperature" bson:"preferedTemperature,omitempty"`
@@ -188,6 +207,7 @@ type CreatePlantRequest struct {
 	Name                string               `json:"name"`
 	Species             string               `json:"species,omitempty"`
 	IsToxic             bool                 `json:"isToxic"`
+	Toxicity            *Toxicity            `json:"toxicity,omitempty"`
 	Sunlight            *SunlightRequirement `json:"sunlight,omitempty"`
 	PreferedTemperature *float64             `json:"preferedTemperature,omitempty"`
 	Location            *Location            `json:"location,omitempty"`
@@ -201,13 +221,22 @@ type CreatePlantRequest struct {
 	Notes               []string             `json:"notes"`
 	PhotoIDs            []string             `json:"photoIds"`
 	GrowthHistory       []GrowthLog          `json:"growthHistory"`
+	HouseholdID         string               `json:"householdId,omitempty"`
+	SpeciesID           string               `json:"speciesId,omitempty"`
+	// AutofillCare fills empty care sections from the species given by SpeciesID
+	AutofillCare bool `json:"autofillCare,omitempty"`
 }
 
 // UpdatePlantRequest is for PATCH operations with optional fields.
 type UpdatePlantRequest struct {
-	Name                *string              `json:"name,omitempty"`
-	Species             *string              `json:"species,omitempty"`
-	IsToxic             *bool                `json:"isToxic,omitempty"`
+	Name    *string `json:"name,omitempty"`
+	Species *string `json:"species,omitempty"`
+	// SpeciesID links the plant to a catalog entry; an empty string unlinks it
+	SpeciesID *string `json:"speciesId,omitempty"`
+	IsToxic   *bool   `json:"isToxic,omitempty"`
+	// Toxicity replaces the toxicity details; an empty object clears them.
+	// IsToxic follows it unless it is sent as well.
+	Toxicity            *Toxicity            `json:"toxicity,omitempty"`
 	Sunlight            *SunlightRequirement `json:"sunlight,omitempty"`
 	PreferedTemperature *float64             `json:"preferedTemperature,omitempty"`
 	Location            *Location            `json:"location,omitempty"`
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Room is a place where a user keeps plants. Plants reference it through
+// Location.RoomID; the room's name and outdoor flag are copied into their
+// location so renaming a room renames it everywhere.
+type Room struct {
+	ID     string `json:"id"     bson:"_id"`
+	UserID string `json:"userId" bson:"userId"`
+	Name   string `json:"name"   bson:"name"`
+	// NameKey is the normalized name that makes names unique per user
+	NameKey string `json:"-" bson:"nameKey"`
+	// Light is the light the room gets, on the scale of plant sunlight needs
+	Light *SunlightRequirement `json:"light,omitempty" bson:"light,omitempty"`
+	// HumidityPct and TemperatureCelsius are typical values of the room
+	HumidityPct        *float64  `json:"humidityPct,omitempty"        bson:"humidityPct,omitempty"`
+	TemperatureCelsius *float64  `json:"temperatureCelsius,omitempty" bson:"temperatureCelsius,omitempty"`
+	IsOutdoors         bool      `json:"isOutdoors"                   bson:"isOutdoors"`
+	CreatedAt          time.Time `json:"createdAt"                    bson:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"                    bson:"updatedAt"`
+
+	// PlantCount is set in list responses only
+	PlantCount int `json:"plantCount" bson:"-"`
+}
+
+// RoomNameKey normalizes a room name so that names differing only in case
+// or spacing refer to the same room
+func RoomNameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// CreateRoomRequest is the request body for creating a room
+type CreateRoomRequest struct {
+	Name               string               `json:"name"`
+	Light              *SunlightRequirement `json:"light,omitempty"`
+	HumidityPct        *float64             `json:"humidityPct,omitempty"`
+	TemperatureCelsius *float64             `json:"temperatureCelsius,omitempty"`
+	IsOutdoors         bool                 `json:"isOutdoors"`
+}
+
+// UpdateRoomRequest is for PATCH operations with optional fields
+type UpdateRoomRequest struct {
+	Name               *string              `json:"name,omitempty"`
+	Light              *SunlightRequirement `json:"light,omitempty"`
+	HumidityPct        *float64             `json:"humidityPct,omitempty"`
+	TemperatureCelsius *float64             `json:"temperatureCelsius,omitempty"`
+	IsOutdoors         *bool                `json:"isOutdoors,omitempty"`
+}
+
+// MovePlantsRequest moves plants into a room
+type MovePlantsRequest struct {
+	PlantIDs []string `json:"plantIds"`
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"slices"
+	"time"
+)
+
+type ToxicityLevel string
+
+const (
+	ToxicityNone     ToxicityLevel = "none"
+	ToxicityMild     ToxicityLevel = "mild"
+	ToxicityModerate ToxicityLevel = "moderate"
+	ToxicitySevere   ToxicityLevel = "severe"
+	// ToxicityUnknown is reported for plants marked toxic without details;
+	// it cannot be stored
+	ToxicityUnknown ToxicityLevel = "unknown"
+)
+
+// Rank orders levels by severity, none being 0
+func (l ToxicityLevel) Rank() int {
+	switch l {
+	case ToxicityUnknown:
+		return 1
+	case ToxicityMild:
+		return 2
+	case ToxicityModerate:
+		return 3
+	case ToxicitySevere:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Toxicity is how poisonous a species or plant is when eaten
+type Toxicity struct {
+	Cats     ToxicityLevel `json:"cats"     bson:"cats"`
+	Dogs     ToxicityLevel `json:"dogs"     bson:"dogs"`
+	Humans   ToxicityLevel `json:"humans"   bson:"humans"`
+	Symptoms []string      `json:"symptoms" bson:"symptoms,omitempty"`
+}
+
+// Toxic reports whether the plant is poisonous to anyone
+func (t Toxicity) Toxic() bool {
+	for _, level := range []ToxicityLevel{t.Cats, t.Dogs, t.Humans} {
+		if level.Rank() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// For returns the level for a kind of resident; children count as humans
+func (t Toxicity) For(kind ResidentKind) ToxicityLevel {
+	switch kind {
+	case ResidentCat:
+		return t.Cats
+	case ResidentDog:
+		return t.Dogs
+	case ResidentChild:
+		return t.Humans
+	default:
+		return ToxicityNone
+	}
+}
+
+// ResidentKind is a kind of household member at risk from toxic plants
+type ResidentKind string
+
+const (
+	ResidentCat   ResidentKind = "cat"
+	ResidentDog   ResidentKind = "dog"
+	ResidentChild ResidentKind = "child"
+)
+
+// Resident is a pet or child living with the plants
+type Resident struct {
+	Kind ResidentKind `json:"kind"            bson:"kind"`
+	Name string       `json:"name,omitempty"  bson:"name,omitempty"`
+	// RoomIDs lists the rooms the resident can reach; empty means all of them
+	RoomIDs []string `json:"roomIds,omitempty" bson:"roomIds,omitempty"`
+}
+
+// CanReach reports whether the resident can get to plants in the room with
+// roomID. Plants without a room are assumed to be reachable.
+func (r Resident) CanReach(roomID string) bool {
+	if len(r.RoomIDs) == 0 || roomID == "" {
+		return true
+	}
+	return slices.Contains(r.RoomIDs, roomID)
+}
+
+// SafetyProfile lists the pets and children of a home. A household has one
+// for its shared plants; each user has one for their own plants.
+type SafetyProfile struct {
+	// UserID is set for a user's own profile
+	UserID    string     `json:"-"         bson:"_id,omitempty"`
+	Residents []Resident `json:"residents" bson:"residents"`
+	UpdatedAt time.Time  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// UpdateSafetyProfileRequest replaces the residents of a safety profile
+type UpdateSafetyProfileRequest struct {
+	Residents []Resident `json:"residents"`
+}
+
+// PlantRisk is the danger a plant poses to one kind of resident
+type PlantRisk struct {
+	Resident ResidentKind  `json:"resident"`
+	Severity ToxicityLevel `json:"severity"`
+	// Names of the residents of this kind that can reach the plant
+	Names   []string `json:"names,omitempty"`
+	Message string   `json:"message"`
+}
+
+// RiskyPlant is a plant that residents can reach and that is toxic to them
+type RiskyPlant struct {
+	PlantID     string `json:"plantId"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Room        string `json:"room,omitempty"`
+	HouseholdID string `json:"householdId,omitempty"`
+	// Severity is the highest severity of Risks
+	Severity ToxicityLevel `json:"severity"`
+	Risks    []PlantRisk   `json:"risks"`
+	Symptoms []string      `json:"symptoms"`
+}
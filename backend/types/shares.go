@@ -0,0 +1,52 @@
+package types
+
+import "time"
+
+// PlantShare is a public, read-only link to one plant. Anyone with the token
+// can view the plant until the share is deleted. Private fields are only
+// included when the matching Show flag is set.
+type PlantShare struct {
+	ID      string `json:"id"      bson:"_id"`
+	PlantID string `json:"plantId" bson:"plantId"`
+	// OwnerID is the plant's owner, CreatedBy the user who created the share
+	OwnerID   string `json:"-"         bson:"ownerId"`
+	CreatedBy string `json:"createdBy" bson:"createdBy"`
+	Token     string `json:"token"     bson:"token"`
+	Link      string `json:"link"      bson:"-"`
+	// PhotoIDs are the plant photos shown on the public page
+	PhotoIDs        []string  `json:"photoIds"        bson:"photoIds"`
+	ShowLocation    bool      `json:"showLocation"    bson:"showLocation"`
+	ShowNotes       bool      `json:"showNotes"       bson:"showNotes"`
+	ShowPestHistory bool      `json:"showPestHistory" bson:"showPestHistory"`
+	CreatedAt       time.Time `json:"createdAt"       bson:"createdAt"`
+}
+
+// CreatePlantShareRequest is the request body for sharing a plant publicly
+type CreatePlantShareRequest struct {
+	PhotoIDs        []string `json:"photoIds"`
+	ShowLocation    bool     `json:"showLocation"`
+	ShowNotes       bool     `json:"showNotes"`
+	ShowPestHistory bool     `json:"showPestHistory"`
+}
+
+// PublicPlant is the read-only view of a shared plant
+type PublicPlant struct {
+	Name                string               `json:"name"`
+	Species             string               `json:"species"`
+	IsToxic             bool                 `json:"isToxic"`
+	Toxicity            *Toxicity            `json:"toxicity,omitempty"`
+	Sunlight            *SunlightRequirement `json:"sunlight"`
+	PreferedTemperature *float64             `json:"preferedTemperature"`
+	Watering            *WateringConfig      `json:"watering"`
+	Fertilizing         *FertilizerConfig    `json:"fertilizing"`
+	Humidity            *HumidityConfig      `json:"humidity"`
+	Soil                *SoilConfig          `json:"soil"`
+	Seasonality         *SeasonalAdjustments `json:"seasonality"`
+	Flags               []PlantFlag          `json:"flags"`
+	PhotoURLs           []string             `json:"photoUrls"`
+
+	// Only set when the owner opted in
+	Location    *Location       `json:"location,omitempty"`
+	Notes       []string        `json:"notes,omitempty"`
+	PestHistory []PestInfection `json:"pestHistory,omitempty"`
+}
@@ -0,0 +1,37 @@
+package types
+
+// CommonName is a common name of a species in one language
+type CommonName struct {
+	// Lang is an ISO 639-1 language code such as "en" or "de"
+	Lang string `json:"lang" bson:"lang"`
+	Name string `json:"name" bson:"name"`
+}
+
+// SpeciesWinter is how a species is cared for during winter
+type SpeciesWinter struct {
+	RestPeriod     bool    `json:"restPeriod"     bson:"restPeriod"`
+	WaterFactor    float64 `json:"waterFactor"    bson:"waterFactor"`
+	MinTempCelsius float64 `json:"minTempCelsius" bson:"minTempCelsius"`
+	Fertilize      bool    `json:"fertilize"      bson:"fertilize"`
+}
+
+// Species is an entry of the species catalog with default care values for
+// new plants
+type Species struct {
+	// ID is the slug of the scientific name, e.g. "monstera-deliciosa"
+	ID             string       `json:"id"             bson:"_id"`
+	ScientificName string       `json:"scientificName" bson:"scientificName"`
+	Family         string       `json:"family"         bson:"family"`
+	CommonNames    []CommonName `json:"commonNames"    bson:"commonNames"`
+
+	Sunlight                SunlightRequirement `json:"sunlight"                bson:"sunlight"`
+	WateringIntervalDays    int                 `json:"wateringIntervalDays"    bson:"wateringIntervalDays"`
+	FertilizingIntervalDays int                 `json:"fertilizingIntervalDays" bson:"fertilizingIntervalDays"`
+	NPKRatio                string              `json:"npkRatio"                bson:"npkRatio"`
+	// MistingIntervalDays is 0 for species that do not need misting
+	MistingIntervalDays int     `json:"mistingIntervalDays" bson:"mistingIntervalDays"`
+	TargetHumidityPct   float64 `json:"targetHumidityPct"   bson:"targetHumidityPct"`
+
+	Toxicity Toxicity      `json:"toxicity" bson:"toxicity"`
+	Winter   SpeciesWinter `json:"winter"   bson:"winter"`
+}
@@ -0,0 +1,79 @@
+package types
+
+import "time"
+
+// TreatmentMethod is how a pest treatment is applied
+type TreatmentMethod string
+
+const (
+	TreatmentSpray      TreatmentMethod = "spray"
+	TreatmentWipe       TreatmentMethod = "wipe"
+	TreatmentDrench     TreatmentMethod = "drench"
+	TreatmentGranules   TreatmentMethod = "granules"
+	TreatmentBiological TreatmentMethod = "biological"
+	TreatmentOther      TreatmentMethod = "other"
+)
+
+// TreatmentStatus tells whether a treatment plan still needs applications
+type TreatmentStatus string
+
+const (
+	TreatmentActive TreatmentStatus = "active"
+	// TreatmentCompleted is set once all repetitions were applied
+	TreatmentCompleted TreatmentStatus = "completed"
+	// TreatmentStopped is set when the infection was resolved or removed
+	// before the plan was done
+	TreatmentStopped TreatmentStatus = "stopped"
+)
+
+// TreatmentApplication logs one application of a treatment plan. UserID is
+// the user who applied it, which differs from the owner for household members.
+type TreatmentApplication struct {
+	At     time.Time `json:"at"              bson:"at"`
+	UserID string    `json:"userId"          bson:"userId"`
+	Notes  string    `json:"notes,omitempty" bson:"notes,omitempty"`
+}
+
+// TreatmentPlan repeats a treatment against one pest infection of a plant
+// every IntervalDays until it was applied Repetitions times. Plans belong to
+// the owner of the plant.
+type TreatmentPlan struct {
+	ID           string          `json:"id"           bson:"_id"`
+	PlantID      string          `json:"plantId"      bson:"plantId"`
+	UserID       string          `json:"userId"       bson:"userId"`
+	InfectionID  string          `json:"infectionId"  bson:"infectionId"`
+	Pest         PestType        `json:"pest"         bson:"pest"`
+	Product      string          `json:"product"      bson:"product"`
+	Method       TreatmentMethod `json:"method"       bson:"method"`
+	IntervalDays int             `json:"intervalDays" bson:"intervalDays"`
+	Repetitions  int             `json:"repetitions"  bson:"repetitions"`
+
+	Applications []TreatmentApplication `json:"applications" bson:"applications"`
+	Status       TreatmentStatus        `json:"status"       bson:"status"`
+	// NextDueAt is when the next application is due; nil once the plan ended
+	NextDueAt *time.Time `json:"nextDueAt,omitempty" bson:"nextDueAt,omitempty"`
+	// NextReminderAt is when the worker sends the next "treat again"
+	// reminder: when an application is due, then daily until it is logged
+	NextReminderAt *time.Time `json:"-" bson:"nextReminderAt,omitempty"`
+
+	CreatedAt time.Time  `json:"createdAt"         bson:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"         bson:"updatedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty" bson:"endedAt,omitempty"`
+}
+
+// CreateTreatmentPlanRequest is the request body for starting a treatment
+// plan. The first application is due right away.
+type CreateTreatmentPlanRequest struct {
+	InfectionID  string          `json:"infectionId"`
+	Product      string          `json:"product"`
+	Method       TreatmentMethod `json:"method"`
+	IntervalDays int             `json:"intervalDays"`
+	Repetitions  int             `json:"repetitions"`
+}
+
+// LogTreatmentRequest is the request body for logging an application; At
+// defaults to now
+type LogTreatmentRequest struct {
+	At    *time.Time `json:"at,omitempty"`
+	Notes string     `json:"notes"`
+}
@@ -0,0 +1,57 @@
+package types
+
+import "time"
+
+// Vacation redirects a user's reminders to a caretaker between StartsAt and
+// EndsAt. The caretaker joins with the invite code and has care-only access to
+// all of the user's plants during that period.
+type Vacation struct {
+	ID            string     `json:"id"                      bson:"_id"`
+	UserID        string     `json:"userId"                  bson:"userId"`
+	StartsAt      time.Time  `json:"startsAt"                bson:"startsAt"`
+	EndsAt        time.Time  `json:"endsAt"                  bson:"endsAt"`
+	CaretakerID   string     `json:"caretakerId,omitempty"   bson:"caretakerId,omitempty"`
+	CaretakerName string     `json:"caretakerName,omitempty" bson:"caretakerName,omitempty"`
+	InviteCode    string     `json:"inviteCode"              bson:"inviteCode"`
+	InviteLink    string     `json:"inviteLink"              bson:"-"`
+	SummarySentAt *time.Time `json:"summarySentAt,omitempty" bson:"summarySentAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"               bson:"createdAt"`
+}
+
+// ActiveAt reports whether the vacation is running at t
+func (v Vacation) ActiveAt(t time.Time) bool {
+	return !t.Before(v.StartsAt) && t.Before(v.EndsAt)
+}
+
+// CreateVacationRequest is the request body for planning a vacation
+type CreateVacationRequest struct {
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+}
+
+// JoinVacationRequest is the request body for a caretaker accepting an invite
+type JoinVacationRequest struct {
+	Code        string `json:"code"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// VacationSummary reports the care logged while a user was away
+type VacationSummary struct {
+	VacationID    string                `json:"vacationId"`
+	StartsAt      time.Time             `json:"startsAt"`
+	EndsAt        time.Time             `json:"endsAt"`
+	CaretakerName string                `json:"caretakerName,omitempty"`
+	TotalEvents   int                   `json:"totalEvents"`
+	ByType        map[CareEventType]int `json:"byType"`
+	Plants        []PlantCareSummary    `json:"plants"`
+	// NotCaredFor lists plants without any care event during the vacation
+	NotCaredFor []string `json:"notCaredFor"`
+}
+
+// PlantCareSummary is the care one plant received during a vacation
+type PlantCareSummary struct {
+	PlantID  string     `json:"plantId"`
+	Name     string     `json:"name"`
+	Events   int        `json:"events"`
+	LastCare *time.Time `json:"lastCare,omitempty"`
+}
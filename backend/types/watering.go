@@ -0,0 +1,49 @@
+package types
+
+// HealthTrend is the direction of a plant's health in its growth log
+type HealthTrend string
+
+const (
+	HealthImproving HealthTrend = "improving"
+	HealthStable    HealthTrend = "stable"
+	HealthDeclining HealthTrend = "declining"
+	// HealthTrendUnknown is used with fewer than two rated growth log entries
+	HealthTrendUnknown HealthTrend = "unknown"
+)
+
+// WateringRecommendation suggests a watering interval that matches how a
+// plant is actually watered. SuggestedIntervalDays equals the current
+// interval when Change is false.
+type WateringRecommendation struct {
+	PlantID               string `json:"plantId"`
+	Name                  string `json:"name"`
+	CurrentIntervalDays   int    `json:"currentIntervalDays"`
+	SuggestedIntervalDays int    `json:"suggestedIntervalDays"`
+	Change                bool   `json:"change"`
+	// Confidence is 0 (a guess) to 1 (certain)
+	Confidence float64 `json:"confidence"`
+	// Explanation sums up Reasons in one text
+	Explanation string   `json:"explanation"`
+	Reasons     []string `json:"reasons"`
+
+	// ObservedIntervalDays is the typical time between waterings, in
+	// summer terms; nil without enough history
+	ObservedIntervalDays *float64    `json:"observedIntervalDays"`
+	Waterings            int         `json:"waterings"`
+	Snoozes              int         `json:"snoozes"`
+	HealthTrend          HealthTrend `json:"healthTrend"`
+	// Winter is set when the plant's winter water factor applies now
+	Winter bool `json:"winter"`
+}
+
+// SnoozeRequest is the request body for postponing a watering reminder
+type SnoozeRequest struct {
+	Days int `json:"days"`
+}
+
+// AcceptWateringRecommendationResponse is returned when a recommendation is
+// accepted
+type AcceptWateringRecommendationResponse struct {
+	Plant          *Plant                 `json:"plant"`
+	Recommendation WateringRecommendation `json:"recommendation"`
+}
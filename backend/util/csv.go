@@ -0,0 +1,183 @@
+package util
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// CSVReader reads a CSV file with a header row and addresses cells by column
+// name, so columns may come in any order
+type CSVReader struct {
+	in      *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVReader reads the header row. Columns for which known returns false
+// are rejected, so typos do not silently drop data.
+func NewCSVReader(r io.Reader, known func(column string) bool) (*CSVReader, error) {
+	in := csv.NewReader(r)
+	in.TrimLeadingSpace = true
+
+	header, err := in.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet programs often prepend a byte order mark
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !known(name) {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	return &CSVReader{in: in, columns: columns}, nil
+}
+
+// Columns returns the column names of the header, sorted
+func (c *CSVReader) Columns() []string {
+	names := make([]string, 0, len(c.columns))
+	for name := range c.columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasColumn reports whether the header contains the column
+func (c *CSVReader) HasColumn(name string) bool {
+	_, ok := c.columns[name]
+	return ok
+}
+
+// Next returns the next row, or io.EOF after the last one
+func (c *CSVReader) Next() (*CSVRow, error) {
+	record, err := c.in.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	line, _ := c.in.FieldPos(0)
+	return &CSVRow{
+		Line:    line,
+		Errors:  make([]types.ValidationError, 0),
+		columns: c.columns,
+		record:  record,
+	}, nil
+}
+
+// CSVRow reads typed cells of one record and collects the cells that cannot
+// be parsed in Errors. Empty cells yield zero values.
+type CSVRow struct {
+	// Line is the line of the file the record starts on
+	Line   int
+	Errors []types.ValidationError
+
+	columns map[string]int
+	record  []string
+}
+
+// Str returns the trimmed cell
+func (row *CSVRow) Str(column string) string {
+	i, ok := row.columns[column]
+	if !ok || i >= len(row.record) {
+		return ""
+	}
+	return strings.TrimSpace(row.record[i])
+}
+
+// Has reports whether any of the columns has a value
+func (row *CSVRow) Has(columns ...string) bool {
+	for _, column := range columns {
+		if row.Str(column) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (row *CSVRow) invalid(column, expected string) {
+	row.Errors = append(row.Errors, types.ValidationError{
+		Field:   column,
+		Message: fmt.Sprintf("%s must be %s", column, expected),
+	})
+}
+
+func (row *CSVRow) Bool(column string) bool {
+	v := row.Str(column)
+	if v == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		row.invalid(column, "true or false")
+	}
+	return b
+}
+
+func (row *CSVRow) Int(column string) int {
+	v := row.Str(column)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		row.invalid(column, "a whole number")
+	}
+	return n
+}
+
+func (row *CSVRow) Float(column string) float64 {
+	v := row.Str(column)
+	if v == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		row.invalid(column, "a number")
+	}
+	return f
+}
+
+// Time accepts RFC 3339 timestamps and plain dates
+func (row *CSVRow) Time(column string) *time.Time {
+	v := row.Str(column)
+	if v == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t
+		}
+	}
+	row.invalid(column, "a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	return nil
+}
+
+// List splits the cell by separator and drops empty items
+func (row *CSVRow) List(column, separator string) []string {
+	v := row.Str(column)
+	if v == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(v, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
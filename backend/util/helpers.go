@@ -3,8 +3,14 @@ package util
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+
+	"github.com/qreepex/water-me-app/backend/logging"
+	"github.com/qreepex/water-me-app/backend/types"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // DecodeJSON decodes JSON from the request body with strict error handling.
@@ -29,14 +35,15 @@ func BadRequest(w http.ResponseWriter, message string, details any) {
 	RespondJSON(w, http.StatusBadRequest, map[string]any{"error": message, "details": details})
 }
 
-// ServerError responds with a 500 error.
-func ServerError(w http.ResponseWriter, err error) {
-	log.Printf("server error: %v", err)
-	RespondJSON(
-		w,
-		http.StatusInternalServerError,
-		map[string]string{"error": "Internal server error"},
-	)
+// ServerError logs err with the request's correlation IDs and responds with a 500 error.
+// The request ID is included in the response so users can quote it in bug reports.
+func ServerError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.ErrorContext(r.Context(), "server error", "error", err)
+	response := map[string]string{"error": "Internal server error"}
+	if requestID := logging.RequestID(r.Context()); requestID != "" {
+		response["requestId"] = requestID
+	}
+	RespondJSON(w, http.StatusInternalServerError, response)
 }
 
 // NotFound responds with a 404 error.
@@ -44,23 +51,51 @@ func NotFound(w http.ResponseWriter) {
 	RespondJSON(w, http.StatusNotFound, map[string]string{"error": "Not found"})
 }
 
+// Forbidden responds with a 403 error.
+func Forbidden(w http.ResponseWriter) {
+	RespondJSON(w, http.StatusForbidden, map[string]string{"error": "Forbidden"})
+}
+
 // MethodNotAllowed responds with a 405 error.
 func MethodNotAllowed(w http.ResponseWriter) {
 	RespondJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
 }
 
-// Crypto helper stubs (defined in main backend for now)
+// bcryptCost is the work factor for password hashes
+const bcryptCost = 12
+
+// HashPassword hashes a password with bcrypt. Passwords over 72 bytes are rejected.
 func HashPassword(password string) (string, error) {
-	// Implementation in main backend file
-	return "", nil
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(hash), nil
 }
 
+// VerifyPassword returns nil if password matches the bcrypt hash
 func VerifyPassword(hash string, password string) error {
-	// Implementation in main backend file
-	return nil
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
+
+// GenerateJWT signs the claims with HMAC-SHA256
+func GenerateJWT(claims types.Claims, secret []byte) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+	return token, nil
 }
 
-func GenerateJWT(userID string, secret string) (string, error) {
-	// Implementation in main backend file
-	return "", nil
+// ParseJWT verifies an HMAC-SHA256 signed token, including its expiry, and
+// returns its claims
+func ParseJWT(tokenString string, secret []byte) (*types.Claims, error) {
+	claims := &types.Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
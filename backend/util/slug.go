@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+var (
+	nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+	repeatDashes = regexp.MustCompile(`-+`)
+)
+
+// Slugify converts a string to a URL-friendly slug
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+	s = nonSlugChars.ReplaceAllString(s, "-")
+	s = repeatDashes.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	return s
+}
+
+// GenerateUniqueSlug creates a unique slug for the plant within the user's collection
+func GenerateUniqueSlug(
+	name string,
+	location *types.Location,
+	existingPlants []types.Plant,
+) string {
+	baseSlug := Slugify(name)
+	if baseSlug == "" {
+		baseSlug = "plant"
+	}
+	if !slugExists(baseSlug, existingPlants) {
+		return baseSlug
+	}
+	var locationPart string
+	if location != nil {
+		locationPart = Slugify(location.Room)
+		if locationPart == "" {
+			locationPart = Slugify(location.Position)
+		}
+	}
+	if locationPart != "" {
+		slugWithLocation := baseSlug + "-" + locationPart
+		if !slugExists(slugWithLocation, existingPlants) {
+			return slugWithLocation
+		}
+	}
+	counter := 1
+	for {
+		numberedSlug := fmt.Sprintf("%s-%d", baseSlug, counter)
+		if !slugExists(numberedSlug, existingPlants) {
+			return numberedSlug
+		}
+		counter++
+	}
+}
+
+// slugExists checks if a slug already exists in the user's plants
+func slugExists(slug string, plants []types.Plant) bool {
+	for _, plant := range plants {
+		if plant.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// MaxAPITokensPerUser is the maximum number of API tokens a user can hold
+	MaxAPITokensPerUser   = 20
+	apiTokenNameMaxLength = 100
+)
+
+// ValidateCreateAPITokenRequest validates a new API token
+func ValidateCreateAPITokenRequest(req types.CreateAPITokenRequest, now time.Time) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > apiTokenNameMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "name",
+			Message: "Name is required and must be 100 characters or less",
+		})
+	}
+
+	if len(req.Scopes) == 0 {
+		errors = append(errors, types.ValidationError{
+			Field:   "scopes",
+			Message: "At least one scope is required",
+		})
+	}
+	for _, scope := range req.Scopes {
+		if !IsTokenScope(scope) {
+			errors = append(errors, types.ValidationError{
+				Field:   "scopes",
+				Message: "Unknown scope: " + string(scope),
+			})
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		errors = append(errors, types.ValidationError{
+			Field:   "expiresAt",
+			Message: "Expiry must be in the future",
+		})
+	}
+
+	return errors
+}
+
+// IsTokenScope reports whether scope is a known API token scope
+func IsTokenScope(scope types.TokenScope) bool {
+	switch scope {
+	case types.ScopePlantsRead, types.ScopePlantsCare, types.ScopeNotificationsManage:
+		return true
+	}
+	return false
+}
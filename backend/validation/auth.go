@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	passwordMinLength = 8
+	// bcrypt only uses the first 72 bytes
+	passwordMaxLength = 72
+	emailMaxLength    = 254
+)
+
+// ValidateSignupRequest validates the email and password of a new account.
+func ValidateSignupRequest(req types.SignupRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" || len(email) > emailMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "email",
+			Message: "Email is required",
+		})
+	} else if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		errors = append(errors, types.ValidationError{
+			Field:   "email",
+			Message: "Email is not a valid address",
+		})
+	}
+
+	errors = append(errors, validatePassword("password", req.Password)...)
+	return errors
+}
+
+// ValidateChangePasswordRequest validates a password change.
+func ValidateChangePasswordRequest(req types.ChangePasswordRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if req.CurrentPassword == "" {
+		errors = append(errors, types.ValidationError{
+			Field:   "currentPassword",
+			Message: "Current password is required",
+		})
+	}
+	errors = append(errors, validatePassword("newPassword", req.NewPassword)...)
+	return errors
+}
+
+func validatePassword(field, password string) []types.ValidationError {
+	if len(password) < passwordMinLength || len(password) > passwordMaxLength {
+		return []types.ValidationError{{
+			Field:   field,
+			Message: "Password must be between 8 and 72 characters",
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// MaxHouseholdsPerUser is the maximum number of households a user can belong to
+	MaxHouseholdsPerUser = 10
+	// MaxHouseholdMembers is the maximum number of members of one household
+	MaxHouseholdMembers = 20
+	// MaxInviteHours is the longest an invite can stay valid (30 days)
+	MaxInviteHours = 30 * 24
+
+	householdNameMaxLength = 100
+	displayNameMaxLength   = 50
+)
+
+// ValidateCreateHouseholdRequest validates a new household
+func ValidateCreateHouseholdRequest(req types.CreateHouseholdRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > householdNameMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "name",
+			Message: "Name is required and must be 100 characters or less",
+		})
+	}
+	errors = append(errors, validateDisplayName(req.DisplayName)...)
+	return errors
+}
+
+// ValidateCreateHouseholdInviteRequest validates an invite. The owner role
+// cannot be handed out.
+func ValidateCreateHouseholdInviteRequest(
+	req types.CreateHouseholdInviteRequest,
+) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if !IsAssignableRole(req.Role) {
+		errors = append(errors, types.ValidationError{
+			Field:   "role",
+			Message: "Role must be editor, caretaker or viewer",
+		})
+	}
+	if req.ExpiresInHours < 0 || req.ExpiresInHours > MaxInviteHours {
+		errors = append(errors, types.ValidationError{
+			Field:   "expiresInHours",
+			Message: "ExpiresInHours must be between 1 and 720",
+		})
+	}
+	return errors
+}
+
+// ValidateJoinHouseholdRequest validates an invite acceptance
+func ValidateJoinHouseholdRequest(req types.JoinHouseholdRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if strings.TrimSpace(req.Code) == "" {
+		errors = append(errors, types.ValidationError{
+			Field:   "code",
+			Message: "Code is required",
+		})
+	}
+	errors = append(errors, validateDisplayName(req.DisplayName)...)
+	return errors
+}
+
+// IsAssignableRole reports whether role can be given to a member. There is
+// exactly one owner per household, the user who created it.
+func IsAssignableRole(role types.HouseholdRole) bool {
+	switch role {
+	case types.RoleEditor, types.RoleCaretaker, types.RoleViewer:
+		return true
+	}
+	return false
+}
+
+func validateDisplayName(name string) []types.ValidationError {
+	if len(name) > displayNameMaxLength {
+		return []types.ValidationError{{
+			Field:   "displayName",
+			Message: "DisplayName must be 50 characters or less",
+		}}
+	}
+	return nil
+}
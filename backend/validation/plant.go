@@ -125,6 +125,20 @@ func ValidateCreatePlantRequest(req types.CreatePlantRequest) []types.Validation
 		)
 	}
 
+	if req.AutofillCare && req.SpeciesID == "" {
+		errors = append(
+			errors,
+			types.ValidationError{
+				Field:   "autofillCare",
+				Message: "AutofillCare requires a speciesId",
+			},
+		)
+	}
+
+	if req.Toxicity != nil {
+		errors = append(errors, validateToxicity(*req.Toxicity)...)
+	}
+
 	// Sunlight is optional, but if provided must be valid
 	if req.Sunlight != nil && !isSunlightRequirement(*req.Sunlight) {
 		errors = append(
@@ -337,6 +351,10 @@ func ValidateUpdatePlantRequest(req types.UpdatePlantRequest) []types.Validation
 		}
 	}
 
+	if req.Toxicity != nil && !isEmptyToxicity(*req.Toxicity) {
+		errors = append(errors, validateToxicity(*req.Toxicity)...)
+	}
+
 	if req.Location != nil {
 		errors = append(errors, validateLocation(*req.Location)...)
 	}
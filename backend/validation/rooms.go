@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// MaxRoomsPerUser is the maximum number of rooms a user can create
+const MaxRoomsPerUser = 50
+
+// ValidateCreateRoomRequest validates a new room
+func ValidateCreateRoomRequest(req types.CreateRoomRequest) []types.ValidationError {
+	errors := validateRoomName(req.Name)
+	return append(errors, validateRoomConditions(req.Light, req.HumidityPct, req.TemperatureCelsius)...)
+}
+
+// ValidateUpdateRoomRequest validates the fields a room update sets
+func ValidateUpdateRoomRequest(req types.UpdateRoomRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+	if req.Name != nil {
+		errors = append(errors, validateRoomName(*req.Name)...)
+	}
+	return append(errors, validateRoomConditions(req.Light, req.HumidityPct, req.TemperatureCelsius)...)
+}
+
+func validateRoomName(name string) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" || len(trimmed) > constraints.locationRoomMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "name",
+			Message: "Name is required and must be 100 characters or less",
+		})
+	}
+	return errors
+}
+
+func validateRoomConditions(
+	light *types.SunlightRequirement,
+	humidity, temperature *float64,
+) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+	if light != nil && !isSunlightRequirement(*light) {
+		errors = append(errors, types.ValidationError{
+			Field:   "light",
+			Message: "Light must be one of: Full Sun, Indirect Sun, Partial Shade, Partial to Full Shade, Full Shade",
+		})
+	}
+	if humidity != nil && (*humidity < constraints.humidityMin || *humidity > constraints.humidityMax) {
+		errors = append(errors, types.ValidationError{
+			Field:   "humidityPct",
+			Message: "HumidityPct must be between 0 and 100",
+		})
+	}
+	if temperature != nil && (*temperature < constraints.minTempMin || *temperature > constraints.minTempMax) {
+		errors = append(errors, types.ValidationError{
+			Field:   "temperatureCelsius",
+			Message: "TemperatureCelsius must be between -50 and 50",
+		})
+	}
+	return errors
+}
@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// MaxResidents is the maximum number of pets and children in a safety profile
+	MaxResidents = 20
+
+	residentNameMaxLength    = 50
+	residentRoomsMaxItems    = 50
+	toxicitySymptomsMaxItems = 20
+	toxicitySymptomMaxLength = 100
+)
+
+// ValidateSafetyProfileRequest validates the residents of a safety profile
+func ValidateSafetyProfileRequest(req types.UpdateSafetyProfileRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if len(req.Residents) > MaxResidents {
+		errors = append(errors, types.ValidationError{
+			Field:   "residents",
+			Message: "At most 20 residents are allowed",
+		})
+		return errors
+	}
+	for _, resident := range req.Residents {
+		if !IsResidentKind(resident.Kind) {
+			errors = append(errors, types.ValidationError{
+				Field:   "residents.kind",
+				Message: "Kind must be one of: cat, dog, child",
+			})
+			break
+		}
+	}
+	for _, resident := range req.Residents {
+		if len(strings.TrimSpace(resident.Name)) > residentNameMaxLength {
+			errors = append(errors, types.ValidationError{
+				Field:   "residents.name",
+				Message: "Names must be 50 characters or less",
+			})
+			break
+		}
+	}
+	for _, resident := range req.Residents {
+		if len(resident.RoomIDs) > residentRoomsMaxItems {
+			errors = append(errors, types.ValidationError{
+				Field:   "residents.roomIds",
+				Message: "At most 50 rooms are allowed per resident",
+			})
+			break
+		}
+		if slices.Contains(resident.RoomIDs, "") {
+			errors = append(errors, types.ValidationError{
+				Field:   "residents.roomIds",
+				Message: "Room IDs must not be empty",
+			})
+			break
+		}
+	}
+	return errors
+}
+
+func IsResidentKind(kind types.ResidentKind) bool {
+	switch kind {
+	case types.ResidentCat, types.ResidentDog, types.ResidentChild:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsToxicityLevel reports whether level can be stored; ToxicityUnknown is
+// only used in reports
+func IsToxicityLevel(level types.ToxicityLevel) bool {
+	switch level {
+	case types.ToxicityNone, types.ToxicityMild, types.ToxicityModerate, types.ToxicitySevere:
+		return true
+	default:
+		return false
+	}
+}
+
+func validateToxicity(toxicity types.Toxicity) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	levels := []struct {
+		field string
+		level types.ToxicityLevel
+	}{
+		{"toxicity.cats", toxicity.Cats},
+		{"toxicity.dogs", toxicity.Dogs},
+		{"toxicity.humans", toxicity.Humans},
+	}
+	for _, t := range levels {
+		if !IsToxicityLevel(t.level) {
+			errors = append(errors, types.ValidationError{
+				Field:   t.field,
+				Message: "Toxicity must be one of: none, mild, moderate, severe",
+			})
+		}
+	}
+
+	if len(toxicity.Symptoms) > toxicitySymptomsMaxItems {
+		errors = append(errors, types.ValidationError{
+			Field:   "toxicity.symptoms",
+			Message: "At most 20 symptoms are allowed",
+		})
+	} else {
+		for _, symptom := range toxicity.Symptoms {
+			trimmed := strings.TrimSpace(symptom)
+			if trimmed == "" || len(trimmed) > toxicitySymptomMaxLength {
+				errors = append(errors, types.ValidationError{
+					Field:   "toxicity.symptoms",
+					Message: "Symptoms must be between 1 and 100 characters",
+				})
+				break
+			}
+		}
+	}
+	return errors
+}
+
+// isEmptyToxicity reports whether an update clears the toxicity details
+func isEmptyToxicity(t types.Toxicity) bool {
+	return t.Cats == "" && t.Dogs == "" && t.Humans == "" && len(t.Symptoms) == 0
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// MaxSharesPerPlant limits how many public links a plant can have at once
+	MaxSharesPerPlant = 10
+	// MaxSharedPhotos limits the photos shown on a public plant page
+	MaxSharedPhotos = 10
+)
+
+// ValidateCreatePlantShareRequest validates a share of plant
+func ValidateCreatePlantShareRequest(
+	req types.CreatePlantShareRequest,
+	plant types.Plant,
+) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if len(req.PhotoIDs) > MaxSharedPhotos {
+		errors = append(errors, types.ValidationError{
+			Field:   "photoIds",
+			Message: fmt.Sprintf("At most %d photos can be shared", MaxSharedPhotos),
+		})
+		return errors
+	}
+
+	onPlant := make(map[string]bool, len(plant.PhotoIDs))
+	for _, id := range plant.PhotoIDs {
+		onPlant[id] = true
+	}
+	seen := make(map[string]bool, len(req.PhotoIDs))
+	for _, id := range req.PhotoIDs {
+		if !onPlant[id] || seen[id] {
+			errors = append(errors, types.ValidationError{
+				Field:   "photoIds",
+				Message: "Photo IDs must be unique photos of the plant",
+			})
+			break
+		}
+		seen[id] = true
+	}
+	return errors
+}
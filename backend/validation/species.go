@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// ValidateSpecies validates a species catalog entry. The care defaults are
+// checked with the same rules as plant configs, so every plant autofilled
+// from the catalog passes plant validation.
+func ValidateSpecies(species types.Species) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	name := strings.TrimSpace(species.ScientificName)
+	if name == "" {
+		errors = append(errors, types.ValidationError{
+			Field:   "scientificName",
+			Message: "Scientific name is required",
+		})
+	} else if len(name) > constraints.speciesMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "scientificName",
+			Message: "Scientific name must be 100 characters or less",
+		})
+	}
+
+	if len(species.CommonNames) == 0 {
+		errors = append(errors, types.ValidationError{
+			Field:   "commonNames",
+			Message: "At least one common name is required",
+		})
+	}
+	for _, common := range species.CommonNames {
+		if len(common.Lang) != 2 {
+			errors = append(errors, types.ValidationError{
+				Field:   "commonNames",
+				Message: "Languages must be two-letter ISO 639-1 codes",
+			})
+			break
+		}
+		trimmed := strings.TrimSpace(common.Name)
+		if trimmed == "" || len(trimmed) > constraints.nameMaxLength {
+			errors = append(errors, types.ValidationError{
+				Field:   "commonNames",
+				Message: "Common names must be between 1 and 100 characters",
+			})
+			break
+		}
+	}
+
+	if !isSunlightRequirement(species.Sunlight) {
+		errors = append(errors, types.ValidationError{
+			Field:   "sunlight",
+			Message: "Sunlight must be one of: Full Sun, Indirect Sun, Partial Shade, Partial to Full Shade, Full Shade",
+		})
+	}
+
+	errors = append(errors, validateWateringConfig(types.WateringConfig{
+		IntervalDays: species.WateringIntervalDays,
+		Method:       types.MethodTopWatering,
+		WaterType:    types.WaterTap,
+	})...)
+	errors = append(errors, validateFertilizerConfig(types.FertilizerConfig{
+		Type:         types.FertilizerLiquid,
+		IntervalDays: species.FertilizingIntervalDays,
+		NPKRatio:     species.NPKRatio,
+	})...)
+	errors = append(errors, validateHumidityConfig(types.HumidityConfig{
+		RequiresMisting:     species.MistingIntervalDays > 0,
+		MistingIntervalDays: species.MistingIntervalDays,
+		TargetHumidityPct:   species.TargetHumidityPct,
+	})...)
+	errors = append(errors, validateSeasonalAdjustments(types.SeasonalAdjustments{
+		WinterRestPeriod:  species.Winter.RestPeriod,
+		WinterWaterFactor: species.Winter.WaterFactor,
+		MinTempCelsius:    species.Winter.MinTempCelsius,
+	})...)
+
+	errors = append(errors, validateToxicity(species.Toxicity)...)
+
+	return errors
+}
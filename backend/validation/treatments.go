@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+const (
+	// MaxTreatmentIntervalDays and MaxTreatmentRepetitions bound a plan to a
+	// few months of treatments
+	MaxTreatmentIntervalDays = 60
+	MaxTreatmentRepetitions  = 20
+)
+
+// ValidateCreateTreatmentPlanRequest validates a new treatment plan
+func ValidateCreateTreatmentPlanRequest(req types.CreateTreatmentPlanRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+	if strings.TrimSpace(req.InfectionID) == "" {
+		errors = append(errors, types.ValidationError{
+			Field:   "infectionId",
+			Message: "InfectionId is required",
+		})
+	}
+	product := strings.TrimSpace(req.Product)
+	if product == "" || len(product) > constraints.pestTreatmentMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "product",
+			Message: "Product is required and must be 200 characters or less",
+		})
+	}
+	if !isTreatmentMethod(req.Method) {
+		errors = append(errors, types.ValidationError{
+			Field:   "method",
+			Message: "Method must be one of: spray, wipe, drench, granules, biological, other",
+		})
+	}
+	if req.IntervalDays < 1 || req.IntervalDays > MaxTreatmentIntervalDays {
+		errors = append(errors, types.ValidationError{
+			Field:   "intervalDays",
+			Message: "IntervalDays must be between 1 and 60",
+		})
+	}
+	if req.Repetitions < 1 || req.Repetitions > MaxTreatmentRepetitions {
+		errors = append(errors, types.ValidationError{
+			Field:   "repetitions",
+			Message: "Repetitions must be between 1 and 20",
+		})
+	}
+	return errors
+}
+
+// ValidateLogTreatmentRequest validates an application of a treatment plan
+func ValidateLogTreatmentRequest(req types.LogTreatmentRequest, now time.Time) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+	if req.At != nil && req.At.After(now.Add(time.Minute)) {
+		errors = append(errors, types.ValidationError{
+			Field:   "at",
+			Message: "At must not be in the future",
+		})
+	}
+	if len(strings.TrimSpace(req.Notes)) > constraints.pestNotesMaxLength {
+		errors = append(errors, types.ValidationError{
+			Field:   "notes",
+			Message: "Notes must be 500 characters or less",
+		})
+	}
+	return errors
+}
+
+func isTreatmentMethod(method types.TreatmentMethod) bool {
+	switch method {
+	case types.TreatmentSpray,
+		types.TreatmentWipe,
+		types.TreatmentDrench,
+		types.TreatmentGranules,
+		types.TreatmentBiological,
+		types.TreatmentOther:
+		return true
+	default:
+		return false
+	}
+}
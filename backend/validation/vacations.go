@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/qreepex/water-me-app/backend/types"
+)
+
+// MaxVacationDays is the longest a vacation, and with it caretaker access, can last
+const MaxVacationDays = 90
+
+// ValidateCreateVacationRequest validates a planned vacation
+func ValidateCreateVacationRequest(
+	req types.CreateVacationRequest,
+	now time.Time,
+) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if req.StartsAt.IsZero() {
+		errors = append(errors, types.ValidationError{
+			Field:   "startsAt",
+			Message: "StartsAt is required",
+		})
+	}
+	if !req.EndsAt.After(now) || !req.EndsAt.After(req.StartsAt) {
+		errors = append(errors, types.ValidationError{
+			Field:   "endsAt",
+			Message: "EndsAt must be in the future and after startsAt",
+		})
+	}
+	if req.EndsAt.Sub(req.StartsAt) > MaxVacationDays*24*time.Hour {
+		errors = append(errors, types.ValidationError{
+			Field:   "endsAt",
+			Message: "A vacation can last at most 90 days",
+		})
+	}
+	return errors
+}
+
+// ValidateJoinVacationRequest validates a caretaker invite acceptance
+func ValidateJoinVacationRequest(req types.JoinVacationRequest) []types.ValidationError {
+	errors := make([]types.ValidationError, 0)
+
+	if strings.TrimSpace(req.Code) == "" {
+		errors = append(errors, types.ValidationError{
+			Field:   "code",
+			Message: "Code is required",
+		})
+	}
+	errors = append(errors, validateDisplayName(req.DisplayName)...)
+	return errors
+}
@@ -0,0 +1,18 @@
+package validation
+
+import "github.com/qreepex/water-me-app/backend/types"
+
+// MaxSnoozeDays bounds how far a watering reminder can be postponed
+const MaxSnoozeDays = 14
+
+// ValidateSnoozeRequest validates a request to postpone a watering reminder
+func ValidateSnoozeRequest(req types.SnoozeRequest) []types.ValidationError {
+	var errors []types.ValidationError
+	if req.Days < 1 || req.Days > MaxSnoozeDays {
+		errors = append(errors, types.ValidationError{
+			Field:   "days",
+			Message: "Days must be between 1 and 14",
+		})
+	}
+	return errors
+}